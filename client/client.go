@@ -5,34 +5,55 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 
 	// "log"
 	"os"
 	"regexp"
 	messenger "soa_mafia/messenger"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"soa_mafia/pkg/mafia_grpc"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	ReconnectAttempts = 30
+	ReconnectDelay    = 2 * time.Second
 )
 
 type MafiaClient struct {
 	grpc *mafia_grpc.MafiaClient
 
-	name       *string
-	playerInfo *mafia_grpc.PlayerInfo
-	messenger  *messenger.MafiaMessenger
+	name        *string
+	playerInfo  *mafia_grpc.PlayerInfo
+	messenger   *messenger.MafiaMessenger
+	rabbitmqUrl string
 
 	stdout *ThreadSafeStdout
+
+	token             string
+	gameId            string
+	stopNotifications context.CancelFunc
 }
 
-func NewMafiaClient(grpc_client mafia_grpc.MafiaClient) *MafiaClient {
+func NewMafiaClient(grpc_client mafia_grpc.MafiaClient, rabbitmqUrl string) *MafiaClient {
 	return &MafiaClient{
 		&grpc_client,
 		nil,
 		nil,
 		nil,
+		rabbitmqUrl,
 		NewThreadSafeStdout(),
+		"",
+		"",
+		nil,
 	}
 }
 
@@ -50,7 +71,7 @@ func (m *MafiaClient) setName(reader *bufio.Reader) {
 		name, err := reader.ReadString('\n')
 		name = strings.TrimSpace(name)
 		if err != nil {
-			m.stdout.Println("Error reading input: " + err.Error())
+			m.stdout.Println("Error reading input: " + errorToString(err))
 			continue
 		}
 
@@ -86,7 +107,7 @@ func (m *MafiaClient) RunConsoleClient() {
 		m.stdout.Println("Enter a command: ")
 		input, err := reader.ReadString('\n')
 		if err != nil {
-			m.stdout.Println("Error reading input: " + err.Error())
+			m.stdout.Println("Error reading input: " + errorToString(err))
 			continue
 		}
 
@@ -98,16 +119,38 @@ func (m *MafiaClient) RunConsoleClient() {
 			m.help()
 		case "join":
 			m.newGame(arg)
+		case "watch":
+			m.watch(arg)
+		case "lobby":
+			m.lobby(arg)
+		case "bots":
+			m.addBots(arg)
+		case "stats":
+			m.stats(arg)
+		case "top":
+			m.top(arg)
 		case "vote":
 			m.vote(arg)
+		case "revote":
+			m.changeVote(arg)
+		case "unvote":
+			m.withdrawVote()
+		case "skip":
+			m.skipVote()
+		case "votes":
+			m.votes()
 		case "kill":
 			m.kill(arg)
 		case "check":
 			m.check(arg)
+		case "heal":
+			m.heal(arg)
 		case "state":
 			m.state()
 		case "msg":
 			m.sendMsg(arg)
+		case "rematch":
+			m.rematch()
 		case "quit":
 			m.quit()
 		case "exit":
@@ -125,27 +168,159 @@ func (m *MafiaClient) help() {
 
 	sb.WriteString("========================== Available commands ========================\n")
 	sb.WriteString("help\n")
-	sb.WriteString("join {session_name} \t\t\t join the game\n")
+	sb.WriteString("join {session_name} [options] \t\t join the game\n")
+	sb.WriteString("\t options for a new session: players={n} {role}={n} kill_rule={leader|majority|unanimous}\n")
+	sb.WriteString("\t self_heal={true|false} repeat_heal={true|false} day={duration} night={duration}\n")
+	sb.WriteString("\t runoff={true|false} runoff_fallback={no_kill|random|all_tied} open_ballot={true|false} seed={n}\n")
+	sb.WriteString("\t omniscient_spectators={true|false} bots_after={duration},\n")
+	sb.WriteString("\t e.g. players=8 mafia=2 detective=1 doctor=1 kill_rule=majority day=3m night=1m\n")
+	sb.WriteString("\t password={text} makes a new session private or joins a private one\n")
+	sb.WriteString("watch {session_name} [omniscient] [password={text}] \t watch the game as a spectator\n")
+	sb.WriteString("lobby [waiting|started|finished] \t list game sessions\n")
+	sb.WriteString("bots [n] \t\t\t\t fill n empty seats (all by default) with bots, host only\n")
+	sb.WriteString("stats [player_name] \t\t\t show statistics and rating of a player (yours by default)\n")
+	sb.WriteString("top [n] \t\t\t\t show n best players by rating (10 by default)\n")
 	sb.WriteString("vote {player_name} \t\t\t vote for a player during the day\n")
-	sb.WriteString("kill {player_name} \t\t\t kill a player. (command only for mafia)\n")
+	sb.WriteString("revote {player_name} \t\t\t change your vote\n")
+	sb.WriteString("unvote \t\t\t\t\t withdraw your vote\n")
+	sb.WriteString("skip \t\t\t\t\t vote for nobody\n")
+	sb.WriteString("votes \t\t\t\t\t show votes of the day\n")
+	sb.WriteString("kill {player_name} \t\t\t vote to kill a player at night. (command only for mafia)\n")
 	sb.WriteString("check {player_name} \t\t\t check if player is mafia. (command only for detective)\n")
+	sb.WriteString("heal {player_name} \t\t\t save a player from mafia tonight. (command only for doctor)\n")
 	sb.WriteString("state \t\t\t\t\t get current game state\n")
 	sb.WriteString("msg {text} \t\t\t\t send text to chat\n")
+	sb.WriteString("rematch \t\t\t\t join the next game of the session with the same players\n")
 	sb.WriteString("quit \t\t\t\t\t quit the game session\n")
 	sb.WriteString("exit \t\t\t\t\t exit the program\n")
 
 	m.stdout.Println(sb.String())
 }
 
+func sessionsToString(sessions []*mafia_grpc.SessionInfo) string {
+	var sb strings.Builder
+
+	sb.WriteString("======================= Sessions =======================\n")
+	if len(sessions) == 0 {
+		sb.WriteString("No sessions\n")
+	}
+
+	for _, session := range sessions {
+		sb.WriteString(fmt.Sprintf("%s [%s] players: %d/%d", session.Session, strings.ToLower(session.Status.String()), session.Players, session.Capacity))
+		if session.Bots > 0 {
+			sb.WriteString(fmt.Sprintf(", bots: %d", session.Bots))
+		}
+		if session.Spectators > 0 {
+			sb.WriteString(fmt.Sprintf(", spectators: %d", session.Spectators))
+		}
+		if session.Private {
+			sb.WriteString(", private")
+		}
+		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("\t %s\n", configToString(session.Config)))
+	}
+
+	return sb.String()
+}
+
+func percent(part int32, total int32) string {
+	if total == 0 {
+		return "-"
+	}
+
+	return fmt.Sprintf("%d%%", part*100/total)
+}
+
+func statsToString(stats *mafia_grpc.PlayerStats) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("======================= %s =======================\n", stats.Name))
+	sb.WriteString(fmt.Sprintf("Rank: %d, rating: %.1f\n", stats.Rank, stats.Rating))
+	sb.WriteString(fmt.Sprintf("Games: %d, wins: %d (%s)\n", stats.Games, stats.Wins, percent(stats.Wins, stats.Games)))
+
+	roles := make([]string, 0, len(stats.GamesByRole))
+	for role := range stats.GamesByRole {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	for _, role := range roles {
+		games, wins := stats.GamesByRole[role], stats.WinsByRole[role]
+		sb.WriteString(fmt.Sprintf("\t %s: %d games, %d wins (%s)\n", role, games, wins, percent(wins, games)))
+	}
+
+	sb.WriteString(fmt.Sprintf("Survived: %d games (%s)\n", stats.Survived, percent(stats.Survived, stats.Games)))
+	if stats.Checks > 0 {
+		sb.WriteString(fmt.Sprintf("Detective checks: %d, mafia found: %d (%s)\n", stats.Checks, stats.MafiaFound, percent(stats.MafiaFound, stats.Checks)))
+	}
+
+	return sb.String()
+}
+
+func leaderboardToString(leaderboard *mafia_grpc.Leaderboard) string {
+	var sb strings.Builder
+
+	sb.WriteString("======================= Leaderboard =======================\n")
+	if len(leaderboard.Players) == 0 {
+		sb.WriteString("No finished games yet\n")
+	}
+
+	for _, player := range leaderboard.Players {
+		sb.WriteString(fmt.Sprintf("%d. %s \t rating: %.1f, games: %d, wins: %s, survived: %s\n",
+			player.Rank, player.Name, player.Rating, player.Games, percent(player.Wins, player.Games), percent(player.Survived, player.Games)))
+	}
+
+	if int32(len(leaderboard.Players)) < leaderboard.Total {
+		sb.WriteString(fmt.Sprintf("... %d players in total\n", leaderboard.Total))
+	}
+
+	return sb.String()
+}
+
+/*
+	Prints the rules in the syntax of the join options
+*/
+func configToString(config *mafia_grpc.SessionConfig) string {
+	options := []string{fmt.Sprintf("players=%d", config.GetPlayers())}
+
+	roles := make([]string, 0, len(config.GetRoles()))
+	for role := range config.GetRoles() {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	for _, role := range roles {
+		options = append(options, fmt.Sprintf("%s=%d", strings.ToLower(role), config.Roles[role]))
+	}
+
+	options = append(options,
+		"kill_rule="+strings.ToLower(config.GetKillRule().String()),
+		fmt.Sprintf("self_heal=%t", config.GetDoctorSelfHeal()),
+		fmt.Sprintf("repeat_heal=%t", config.GetDoctorRepeatHeal()),
+		fmt.Sprintf("day=%s", time.Duration(config.GetDayDuration())*time.Second),
+		fmt.Sprintf("night=%s", time.Duration(config.GetNightDuration())*time.Second),
+		fmt.Sprintf("runoff=%t", config.GetRunoff()),
+		"runoff_fallback="+strings.ToLower(config.GetRunoffFallback().String()),
+		fmt.Sprintf("open_ballot=%t", config.GetOpenBallot()),
+		fmt.Sprintf("omniscient_spectators=%t", config.GetOmniscientSpectators()),
+		fmt.Sprintf("bots_after=%s", time.Duration(config.GetBotFillDelay())*time.Second),
+	)
+
+	return strings.Join(options, " ")
+}
+
 func stateToString(state *mafia_grpc.GameState) string {
 	var sb strings.Builder
 
 	sb.WriteString("===================== Game State =====================\n")
 	sb.WriteString(fmt.Sprintf("Session: %s\n", state.Session))
+	sb.WriteString(fmt.Sprintf("Game: %s\n", state.GameId))
 
 	sb.WriteString("Alive Players:\n")
 	for _, player := range state.AlivePlayers {
-		sb.WriteString(fmt.Sprintf("- %s\n", player))
+		if containsString(state.Bots, player) {
+			sb.WriteString(fmt.Sprintf("- %s (bot)\n", player))
+		} else {
+			sb.WriteString(fmt.Sprintf("- %s\n", player))
+		}
 	}
 
 	sb.WriteString(fmt.Sprintf("Date: %d\n", state.Date))
@@ -156,6 +331,10 @@ func stateToString(state *mafia_grpc.GameState) string {
 		sb.WriteString("Night\n")
 	}
 
+	if state.IsRunoff {
+		sb.WriteString(fmt.Sprintf("Runoff between: %s\n", strings.Join(state.RunoffCandidates, ", ")))
+	}
+
 	gameStatus := "not started"
 	if state.IsStarted {
 		gameStatus = "started"
@@ -167,9 +346,106 @@ func stateToString(state *mafia_grpc.GameState) string {
 
 	sb.WriteString(fmt.Sprintf("Game %s\n", gameStatus))
 
+	if state.Seed != 0 {
+		sb.WriteString(fmt.Sprintf("Seed: %d\n", state.Seed))
+	}
+
+	if state.PhaseDeadline != 0 {
+		sb.WriteString(timeLeftToString(state) + "\n")
+	}
+
 	return sb.String()
 }
 
+func timeLeftToString(state *mafia_grpc.GameState) string {
+	timeLeft := time.Until(time.UnixMilli(state.PhaseDeadline)).Round(time.Second)
+	if timeLeft < 0 {
+		timeLeft = 0
+	}
+
+	return fmt.Sprintf("Time left: %s", timeLeft)
+}
+
+/*
+	Roles of all players are sent only to omniscient spectators
+*/
+func rolesToString(roles map[string]string) string {
+	if len(roles) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(roles))
+	for name := range roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString("\nRoles:")
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf("\n- %s: %s", name, roles[name]))
+	}
+
+	return sb.String()
+}
+
+func tallyToString(tally *mafia_grpc.VoteTally) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("Voted: %d\n", tally.Voted))
+
+	candidates := make([]string, 0, len(tally.Counts))
+	for candidate := range tally.Counts {
+		candidates = append(candidates, candidate)
+	}
+	sort.Strings(candidates)
+
+	for _, candidate := range candidates {
+		sb.WriteString(fmt.Sprintf("- %s: %d\n", candidate, tally.Counts[candidate]))
+	}
+
+	if tally.Skips > 0 {
+		sb.WriteString(fmt.Sprintf("- skipped: %d\n", tally.Skips))
+	}
+
+	for _, vote := range tally.Votes {
+		victim := vote.Victim
+		if victim == "" {
+			victim = "nobody"
+		}
+		sb.WriteString(fmt.Sprintf("%s -> %s\n", vote.Voter, victim))
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+/*
+	Returns message of the server error with its reason, e.g. "Game hasn't started yet (NOT_STARTED)"
+*/
+func errorToString(err error) string {
+	st := status.Convert(err)
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if ok {
+			return fmt.Sprintf("%s (%s)", st.Message(), info.GetReason())
+		}
+	}
+
+	return err.Error()
+}
+
+/*
+	Errors after which the subscription can't be restored: the player or the game is gone
+*/
+func isFinalError(err error) bool {
+	switch status.Code(err) {
+	case codes.NotFound, codes.Unauthenticated, codes.PermissionDenied, codes.FailedPrecondition:
+		return true
+	}
+
+	return false
+}
+
 func (m *MafiaClient) printErrorIfNotPlaying() bool {
 	if m.playerInfo == nil {
 		m.stdout.Println("You haven't joined any sessions yet")
@@ -186,7 +462,7 @@ func (m *MafiaClient) state() {
 
 	state, err := m.getStateGrpc()
 	if err != nil {
-		m.stdout.Println("Error while requesting the state of the game " + m.playerInfo.Session + ": " + err.Error())
+		m.stdout.Println("Error while requesting the state of the game " + m.playerInfo.Session + ": " + errorToString(err))
 		return
 	}
 
@@ -200,7 +476,7 @@ func (m *MafiaClient) sendMsg(msg string) {
 
 	err := m.messenger.Send(msg)
 	if err != nil {
-		m.stdout.Println("Error while sending a message: " + err.Error())
+		m.stdout.Println("Error while sending a message: " + errorToString(err))
 	}
 }
 
@@ -217,18 +493,66 @@ func notificationToString(notification *mafia_grpc.Notification) string {
 	var sb strings.Builder
 
 	sb.WriteString(fmt.Sprintf("Notification: %s\n", notification.Type))
-	if notification.Type == mafia_grpc.NotificationType_START {
-		sb.WriteString(fmt.Sprintf("Your role: %s", notification.GetRole()))
+	if notification.Type == mafia_grpc.NotificationType_STATE {
+		sb.WriteString(stateToString(notification.GameState))
+		if notification.GetRole() != "" {
+			sb.WriteString(fmt.Sprintf("Your role: %s", notification.GetRole()))
+		}
+		sb.WriteString(rolesToString(notification.GetRoles()))
+		if len(notification.GetMafiaTeam()) > 0 {
+			sb.WriteString(fmt.Sprintf("\nMafia team: %s", strings.Join(notification.GetMafiaTeam(), ", ")))
+			sb.WriteString(fmt.Sprintf("\nMafia leader: %s", notification.GetMafiaLeader()))
+		}
+		return sb.String()
+	} else if notification.Type == mafia_grpc.NotificationType_START {
+		if notification.GetRole() != "" {
+			sb.WriteString(fmt.Sprintf("Your role: %s", notification.GetRole()))
+		} else {
+			sb.WriteString("Game started")
+		}
+		sb.WriteString(rolesToString(notification.GetRoles()))
+		if len(notification.GetMafiaTeam()) > 0 {
+			sb.WriteString(fmt.Sprintf("\nMafia team: %s", strings.Join(notification.GetMafiaTeam(), ", ")))
+			sb.WriteString(fmt.Sprintf("\nMafia leader: %s", notification.GetMafiaLeader()))
+		}
+	} else if notification.Type == mafia_grpc.NotificationType_RUNOFF {
+		candidates := strings.Join(notification.GameState.RunoffCandidates, ", ")
+		sb.WriteString(fmt.Sprintf("Votes split! Vote again for one of: %s", candidates))
+	} else if notification.Type == mafia_grpc.NotificationType_REMATCH {
+		sb.WriteString(fmt.Sprintf("Game %s of session %s is open. ", notification.GetRematchGameId(), notification.GameState.Session))
+		sb.WriteString("Type \"rematch\" to play again")
+		return sb.String()
+	} else if notification.Type == mafia_grpc.NotificationType_VOTE_CAST {
+		sb.WriteString(tallyToString(notification.GetVoteTally()))
+	} else if notification.Type == mafia_grpc.NotificationType_NOTICE {
+		sb.WriteString(fmt.Sprintf("Notice from the server: %s", notification.GetNotice()))
+		return sb.String()
+	} else if notification.Type == mafia_grpc.NotificationType_SHUTDOWN {
+		sb.WriteString(notification.GetNotice())
+		return sb.String()
+	} else if notification.Type == mafia_grpc.NotificationType_FINISH && notification.GetNotice() != "" {
+		sb.WriteString(fmt.Sprintf("Game was stopped by the server: %s", notification.GetNotice()))
+		if notification.GetMafia() != "" {
+			sb.WriteString(fmt.Sprintf("\nMafia: %s", notification.GetMafia()))
+		}
+		return sb.String()
 	} else if notification.Type == mafia_grpc.NotificationType_FINISH {
 		sb.WriteString(fmt.Sprintf("Mafia %s ", notification.GetMafia()))
 		alive := notification.GameState.AlivePlayers
-		mafiaWon := containsString(alive, notification.GetMafia())
+		mafiaWon := false
+		for _, mafia := range notification.GetMafiaTeam() {
+			mafiaWon = mafiaWon || containsString(alive, mafia)
+		}
 
 		if mafiaWon {
 			sb.WriteString("won!")
 		} else {
 			sb.WriteString("lost!")
 		}
+
+		if notification.GameState.GetSeed() != 0 {
+			sb.WriteString(fmt.Sprintf("\nSeed: %d", notification.GameState.GetSeed()))
+		}
 	} else {
 		killed := notification.GetKilledPlayer()
 		if killed == "" {
@@ -236,6 +560,10 @@ func notificationToString(notification *mafia_grpc.Notification) string {
 		}
 		sb.WriteString(fmt.Sprintf("%s was killed!", killed))
 	}
+
+	if notification.GameState.GetPhaseDeadline() != 0 {
+		sb.WriteString("\n" + timeLeftToString(notification.GameState))
+	}
 	return sb.String()
 }
 
@@ -244,6 +572,7 @@ func (m *MafiaClient) processNotifications(notifications chan *mafia_grpc.Notifi
 		// log.Println(notificationToString(notification))
 		m.stdout.Println(notificationToString(notification))
 	}
+	m.stdout.Println("Notification stream closed")
 	// log.Println("processNotifications ended")
 }
 
@@ -254,38 +583,310 @@ func (m *MafiaClient) processMessages() {
 	// log.Println("processMessages ended")
 }
 
-func (m *MafiaClient) newGame(session string) {
+/*
+	Parses options of the join command:
+	players={n} {role}={n} kill_rule={rule} self_heal={bool} repeat_heal={bool}
+	day={duration} night={duration} runoff={bool} runoff_fallback={fallback} open_ballot={bool} seed={n}
+	omniscient_spectators={bool} bots_after={duration}
+*/
+func parseSessionConfig(options []string) (*mafia_grpc.SessionConfig, error) {
+	if len(options) == 0 {
+		return nil, nil
+	}
+
+	config := &mafia_grpc.SessionConfig{Roles: make(map[string]int32)}
+	for _, option := range options {
+		key, value, found := strings.Cut(option, "=")
+		if !found || key == "" {
+			return nil, errors.New("Invalid option: " + option)
+		}
+
+		if key == "kill_rule" {
+			rule, exists := mafia_grpc.KillRule_value[strings.ToUpper(value)]
+			if !exists {
+				return nil, errors.New("Unknown kill rule: " + value)
+			}
+
+			config.KillRule = mafia_grpc.KillRule(rule)
+			config.HasKillRule = true
+			continue
+		}
+
+		if key == "runoff_fallback" {
+			fallback, exists := mafia_grpc.RunoffFallback_value[strings.ToUpper(value)]
+			if !exists {
+				return nil, errors.New("Unknown runoff fallback: " + value)
+			}
+
+			config.RunoffFallback = mafia_grpc.RunoffFallback(fallback)
+			continue
+		}
+
+		if key == "self_heal" || key == "repeat_heal" || key == "runoff" || key == "open_ballot" || key == "omniscient_spectators" {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, errors.New("Invalid boolean in option: " + option)
+			}
+
+			switch key {
+			case "self_heal":
+				config.DoctorSelfHeal = enabled
+			case "repeat_heal":
+				config.DoctorRepeatHeal = enabled
+			case "runoff":
+				config.Runoff = enabled
+			case "open_ballot":
+				config.OpenBallot = enabled
+			case "omniscient_spectators":
+				config.OmniscientSpectators = enabled
+			}
+			continue
+		}
+
+		if key == "day" || key == "night" || key == "bots_after" {
+			duration, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, errors.New("Invalid duration in option: " + option)
+			}
+
+			seconds := int32(duration / time.Second)
+			switch key {
+			case "day":
+				config.DayDuration = seconds
+			case "night":
+				config.NightDuration = seconds
+			case "bots_after":
+				config.BotFillDelay = seconds
+			}
+			continue
+		}
+
+		if key == "seed" {
+			seed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, errors.New("Invalid seed in option: " + option)
+			}
+
+			config.Seed = seed
+			continue
+		}
+
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, errors.New("Invalid number in option: " + option)
+		}
+
+		if key == "players" {
+			config.Players = int32(number)
+			continue
+		}
+
+		role := strings.ToUpper(key[:1]) + strings.ToLower(key[1:])
+		config.Roles[role] = int32(number)
+	}
+
+	return config, nil
+}
+
+/*
+	Removes the password option, the password isn't a part of the session config
+*/
+func cutPassword(options []string) ([]string, string) {
+	rest := []string{}
+	password := ""
+	for _, option := range options {
+		if strings.HasPrefix(option, "password=") {
+			password = strings.TrimPrefix(option, "password=")
+			continue
+		}
+
+		rest = append(rest, option)
+	}
+
+	return rest, password
+}
+
+func (m *MafiaClient) newGame(arg string) {
+	words := strings.Fields(arg)
+	if len(words) == 0 {
+		m.stdout.Println("Session name is empty")
+		return
+	}
+
+	session := words[0]
+	options, password := cutPassword(words[1:])
+	config, err := parseSessionConfig(options)
+	if err != nil {
+		m.stdout.Println(errorToString(err))
+		return
+	}
+
 	if m.playerInfo != nil {
 		m.quit()
 	}
 
-	_, err := m.joinGrpc(session)
+	response, err := m.joinGrpc(session, config, password)
 	if err != nil {
-		m.stdout.Println("Error while joining the game session " + session + ": " + err.Error())
+		m.stdout.Println("Error while joining the game session " + session + ": " + errorToString(err))
 		return
 	}
 
 	m.playerInfo = &mafia_grpc.PlayerInfo{Session: session, Name: *m.name}
+	m.startGame(response)
+}
+
+/*
+	Subscribes for notifications and chat of the game the player has just joined
+*/
+func (m *MafiaClient) startGame(response *mafia_grpc.JoinResponse) {
+	m.token = response.GetToken()
+	m.gameId = response.GetGameId()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.stopNotifications = cancel
 
-	notifications, err := m.getNotificationsGrpc()
+	notifications, err := m.getNotificationsGrpc(ctx)
 	if err != nil {
-		m.stdout.Println("Error while trying to subscribe for notifications: " + err.Error())
+		m.stdout.Println("Error while trying to subscribe for notifications: " + errorToString(err))
 		return
 	}
 
 	go m.processNotifications(notifications)
-	m.messenger = messenger.NewMafiaMessenger(m.grpc, m.playerInfo)
+	m.messenger = messenger.NewMafiaMessenger(m.rabbitmqUrl, m.grpc, m.playerInfo, m.token, m.gameId)
 	go m.processMessages()
 }
 
+func (m *MafiaClient) rematch() {
+	if m.printErrorIfNotPlaying() {
+		return
+	}
+
+	response, err := m.rematchGrpc()
+	if err != nil {
+		m.stdout.Println("Error while joining the rematch: " + errorToString(err))
+		return
+	}
+
+	m.stopNotifications()
+	m.messenger.Close()
+	m.startGame(response)
+}
+
+func (m *MafiaClient) watch(arg string) {
+	words := strings.Fields(arg)
+	if len(words) == 0 {
+		m.stdout.Println("Usage: watch {session_name} [omniscient] [password={text}]")
+		return
+	}
+
+	options, password := cutPassword(words[1:])
+	if len(options) > 1 || (len(options) == 1 && options[0] != "omniscient") {
+		m.stdout.Println("Usage: watch {session_name} [omniscient] [password={text}]")
+		return
+	}
+
+	session := words[0]
+	if m.playerInfo != nil {
+		m.quit()
+	}
+
+	response, err := m.spectateGrpc(session, len(options) == 1, password)
+	if err != nil {
+		m.stdout.Println("Error while joining the game session " + session + " as a spectator: " + errorToString(err))
+		return
+	}
+
+	m.playerInfo = &mafia_grpc.PlayerInfo{Session: session, Name: *m.name}
+	m.startGame(response)
+}
+
+func (m *MafiaClient) lobby(arg string) {
+	statuses := []mafia_grpc.SessionStatus{}
+	for _, word := range strings.Fields(arg) {
+		status, exists := mafia_grpc.SessionStatus_value[strings.ToUpper(word)]
+		if !exists {
+			m.stdout.Println("Usage: lobby [waiting|started|finished]")
+			return
+		}
+
+		statuses = append(statuses, mafia_grpc.SessionStatus(status))
+	}
+
+	response, err := m.listSessionsGrpc(statuses)
+	if err != nil {
+		m.stdout.Println("Error while listing the game sessions: " + errorToString(err))
+		return
+	}
+
+	m.stdout.Println(sessionsToString(response.GetSessions()))
+}
+
+func (m *MafiaClient) stats(arg string) {
+	name := arg
+	if name == "" {
+		name = *m.name
+	}
+
+	response, err := m.getPlayerStatsGrpc(name)
+	if err != nil {
+		m.stdout.Println("Error while getting statistics of " + name + ": " + errorToString(err))
+		return
+	}
+
+	m.stdout.Println(statsToString(response))
+}
+
+func (m *MafiaClient) top(arg string) {
+	limit := 0
+	if arg != "" {
+		number, err := strconv.Atoi(arg)
+		if err != nil || number < 1 {
+			m.stdout.Println("Usage: top [n]")
+			return
+		}
+		limit = number
+	}
+
+	response, err := m.getLeaderboardGrpc(int32(limit))
+	if err != nil {
+		m.stdout.Println("Error while getting the leaderboard: " + errorToString(err))
+		return
+	}
+
+	m.stdout.Println(leaderboardToString(response))
+}
+
+func (m *MafiaClient) addBots(arg string) {
+	if m.printErrorIfNotPlaying() {
+		return
+	}
+
+	count := 0
+	if arg != "" {
+		number, err := strconv.Atoi(arg)
+		if err != nil || number < 1 {
+			m.stdout.Println("Usage: bots [n]")
+			return
+		}
+
+		count = number
+	}
+
+	_, err := m.addBotsGrpc(int32(count))
+	if err != nil {
+		m.stdout.Println("Error while adding bots: " + errorToString(err))
+	}
+}
+
 func (m *MafiaClient) quit() {
 	if m.printErrorIfNotPlaying() {
 		return
 	}
 
+	m.stopNotifications()
 	_, err := m.quitGrpc()
 	if err != nil {
-		m.stdout.Println("Error while quiting the game: " + err.Error())
+		m.stdout.Println("Error while quiting the game: " + errorToString(err))
 	}
 
 	m.messenger.Close()
@@ -300,9 +901,59 @@ func (m *MafiaClient) vote(victim string) {
 
 	_, err := m.voteGrpc(victim)
 	if err != nil {
-		m.stdout.Println("Error while voting: " + err.Error())
+		m.stdout.Println("Error while voting: " + errorToString(err))
+		return
+	}
+}
+
+func (m *MafiaClient) changeVote(victim string) {
+	if m.printErrorIfNotPlaying() {
+		return
+	}
+
+	_, err := m.changeVoteGrpc(victim)
+	if err != nil {
+		m.stdout.Println("Error while changing the vote: " + errorToString(err))
+		return
+	}
+}
+
+func (m *MafiaClient) withdrawVote() {
+	if m.printErrorIfNotPlaying() {
+		return
+	}
+
+	_, err := m.withdrawVoteGrpc()
+	if err != nil {
+		m.stdout.Println("Error while withdrawing the vote: " + errorToString(err))
+		return
+	}
+}
+
+func (m *MafiaClient) skipVote() {
+	if m.printErrorIfNotPlaying() {
+		return
+	}
+
+	_, err := m.skipVoteGrpc()
+	if err != nil {
+		m.stdout.Println("Error while skipping the vote: " + errorToString(err))
+		return
+	}
+}
+
+func (m *MafiaClient) votes() {
+	if m.printErrorIfNotPlaying() {
+		return
+	}
+
+	tally, err := m.getVotesGrpc()
+	if err != nil {
+		m.stdout.Println("Error while requesting votes: " + errorToString(err))
 		return
 	}
+
+	m.stdout.Println(tallyToString(tally))
 }
 
 func (m *MafiaClient) kill(victim string) {
@@ -312,7 +963,7 @@ func (m *MafiaClient) kill(victim string) {
 
 	_, err := m.killGrpc(victim)
 	if err != nil {
-		m.stdout.Println("Error while killing: " + err.Error())
+		m.stdout.Println("Error while killing: " + errorToString(err))
 		return
 	}
 }
@@ -324,7 +975,7 @@ func (m *MafiaClient) check(victim string) {
 
 	resp, err := m.checkIfMafiaGrpc(victim)
 	if err != nil {
-		m.stdout.Println("Error while checking if mafia: " + err.Error())
+		m.stdout.Println("Error while checking if mafia: " + errorToString(err))
 		return
 	}
 
@@ -335,35 +986,141 @@ func (m *MafiaClient) check(victim string) {
 	}
 }
 
+func (m *MafiaClient) heal(patient string) {
+	if m.printErrorIfNotPlaying() {
+		return
+	}
+
+	_, err := m.healGrpc(patient)
+	if err != nil {
+		m.stdout.Println("Error while healing: " + errorToString(err))
+		return
+	}
+}
+
 //////////////////////////////////////////// Private methods: grpc calls //////////////////////////////////////////
 
-func (m *MafiaClient) joinGrpc(session string) (*mafia_grpc.Response, error) {
+/*
+	Calls made on behalf of the player carry the token of the current game
+*/
+func (m *MafiaClient) callContext() context.Context {
+	if m.token == "" {
+		return context.Background()
+	}
+
+	return mafia_grpc.WithToken(context.Background(), m.token)
+}
+
+func (m *MafiaClient) joinGrpc(session string, config *mafia_grpc.SessionConfig, password string) (*mafia_grpc.JoinResponse, error) {
 	if m.playerInfo != nil {
-		return &mafia_grpc.Response{}, errors.New("Already joined the game")
+		return &mafia_grpc.JoinResponse{}, errors.New("Already joined the game")
 	}
 
 	playerInfo := &mafia_grpc.PlayerInfo{Session: session, Name: *m.name}
 
-	request := &mafia_grpc.JoinRequest{Player: playerInfo}
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	request := &mafia_grpc.JoinRequest{Player: playerInfo, Config: config, Password: password}
+	ctx, cancel := context.WithTimeout(m.callContext(), 10*time.Second)
 	defer cancel()
 
 	response, err := (*m.grpc).Join(ctx, request)
 	return response, err
 }
 
+func (m *MafiaClient) spectateGrpc(session string, omniscient bool, password string) (*mafia_grpc.JoinResponse, error) {
+	playerInfo := &mafia_grpc.PlayerInfo{Session: session, Name: *m.name}
+
+	request := &mafia_grpc.SpectateRequest{Player: playerInfo, Omniscient: omniscient, Password: password}
+	ctx, cancel := context.WithTimeout(m.callContext(), 10*time.Second)
+	defer cancel()
+
+	response, err := (*m.grpc).Spectate(ctx, request)
+	return response, err
+}
+
+func (m *MafiaClient) listSessionsGrpc(statuses []mafia_grpc.SessionStatus) (*mafia_grpc.SessionList, error) {
+	request := &mafia_grpc.ListSessionsRequest{Statuses: statuses}
+	ctx, cancel := context.WithTimeout(m.callContext(), 10*time.Second)
+	defer cancel()
+
+	response, err := (*m.grpc).ListSessions(ctx, request)
+	return response, err
+}
+
+func (m *MafiaClient) getPlayerStatsGrpc(name string) (*mafia_grpc.PlayerStats, error) {
+	request := &mafia_grpc.PlayerStatsRequest{Name: name}
+	ctx, cancel := context.WithTimeout(m.callContext(), 10*time.Second)
+	defer cancel()
+
+	response, err := (*m.grpc).GetPlayerStats(ctx, request)
+	return response, err
+}
+
+func (m *MafiaClient) getLeaderboardGrpc(limit int32) (*mafia_grpc.Leaderboard, error) {
+	request := &mafia_grpc.LeaderboardRequest{Limit: limit}
+	ctx, cancel := context.WithTimeout(m.callContext(), 10*time.Second)
+	defer cancel()
+
+	response, err := (*m.grpc).GetLeaderboard(ctx, request)
+	return response, err
+}
+
+func (m *MafiaClient) addBotsGrpc(count int32) (*mafia_grpc.Response, error) {
+	request := &mafia_grpc.AddBotsRequest{Player: m.playerInfo, Count: count}
+	ctx, cancel := context.WithTimeout(m.callContext(), 10*time.Second)
+	defer cancel()
+
+	response, err := (*m.grpc).AddBots(ctx, request)
+	return response, err
+}
+
 func (m *MafiaClient) voteGrpc(victim string) (*mafia_grpc.Response, error) {
 	request := &mafia_grpc.SetVictimRequest{Player: m.playerInfo, Victim: victim}
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(m.callContext(), 10*time.Second)
 	defer cancel()
 
 	response, err := (*m.grpc).Vote(ctx, request)
 	return response, err
 }
 
+func (m *MafiaClient) changeVoteGrpc(victim string) (*mafia_grpc.Response, error) {
+	request := &mafia_grpc.SetVictimRequest{Player: m.playerInfo, Victim: victim}
+	ctx, cancel := context.WithTimeout(m.callContext(), 10*time.Second)
+	defer cancel()
+
+	response, err := (*m.grpc).ChangeVote(ctx, request)
+	return response, err
+}
+
+func (m *MafiaClient) withdrawVoteGrpc() (*mafia_grpc.Response, error) {
+	request := m.playerInfo
+	ctx, cancel := context.WithTimeout(m.callContext(), 10*time.Second)
+	defer cancel()
+
+	response, err := (*m.grpc).WithdrawVote(ctx, request)
+	return response, err
+}
+
+func (m *MafiaClient) skipVoteGrpc() (*mafia_grpc.Response, error) {
+	request := m.playerInfo
+	ctx, cancel := context.WithTimeout(m.callContext(), 10*time.Second)
+	defer cancel()
+
+	response, err := (*m.grpc).SkipVote(ctx, request)
+	return response, err
+}
+
+func (m *MafiaClient) getVotesGrpc() (*mafia_grpc.VoteTally, error) {
+	request := m.playerInfo
+	ctx, cancel := context.WithTimeout(m.callContext(), 5*time.Second)
+	defer cancel()
+
+	response, err := (*m.grpc).GetVotes(ctx, request)
+	return response, err
+}
+
 func (m *MafiaClient) killGrpc(victim string) (*mafia_grpc.Response, error) {
 	request := &mafia_grpc.SetVictimRequest{Player: m.playerInfo, Victim: victim}
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(m.callContext(), 10*time.Second)
 	defer cancel()
 
 	response, err := (*m.grpc).Kill(ctx, request)
@@ -372,50 +1129,104 @@ func (m *MafiaClient) killGrpc(victim string) (*mafia_grpc.Response, error) {
 
 func (m *MafiaClient) checkIfMafiaGrpc(victim string) (*mafia_grpc.CheckMafiaResponse, error) {
 	request := &mafia_grpc.SetVictimRequest{Player: m.playerInfo, Victim: victim}
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(m.callContext(), 5*time.Second)
 	defer cancel()
 
 	response, err := (*m.grpc).CheckIfMafia(ctx, request)
 	return response, err
 }
 
+func (m *MafiaClient) healGrpc(patient string) (*mafia_grpc.Response, error) {
+	request := &mafia_grpc.SetVictimRequest{Player: m.playerInfo, Victim: patient}
+	ctx, cancel := context.WithTimeout(m.callContext(), 5*time.Second)
+	defer cancel()
+
+	response, err := (*m.grpc).Heal(ctx, request)
+	return response, err
+}
+
 func (m *MafiaClient) getStateGrpc() (*mafia_grpc.GameState, error) {
 	request := m.playerInfo
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(m.callContext(), 5*time.Second)
 	defer cancel()
 
 	response, err := (*m.grpc).GetState(ctx, request)
 	return response, err
 }
 
+func (m *MafiaClient) rematchGrpc() (*mafia_grpc.JoinResponse, error) {
+	request := m.playerInfo
+	ctx, cancel := context.WithTimeout(m.callContext(), 5*time.Second)
+	defer cancel()
+
+	response, err := (*m.grpc).Rematch(ctx, request)
+	return response, err
+}
+
 func (m *MafiaClient) quitGrpc() (*mafia_grpc.Response, error) {
 	request := m.playerInfo
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(m.callContext(), 5*time.Second)
 	defer cancel()
 
 	response, err := (*m.grpc).Quit(ctx, request)
 	return response, err
 }
 
-func (m *MafiaClient) getNotificationsGrpc() (chan *mafia_grpc.Notification, error) {
-	request := m.playerInfo
+/*
+	When the stream breaks, it is opened again with the same token,
+	until ctx is cancelled or ReconnectAttempts in a row fail.
+	Stream closed by the server isn't reopened: the player has left or the game is closed.
+	After SHUTDOWN it is, the game may be restored when the server is back
+*/
+func (m *MafiaClient) getNotificationsGrpc(ctx context.Context) (chan *mafia_grpc.Notification, error) {
+	request := &mafia_grpc.SubscribeRequest{Player: m.playerInfo}
+	ctx = mafia_grpc.WithToken(ctx, m.token)
 
 	notifications := make(chan *mafia_grpc.Notification)
 
-	notification_stream, err := (*m.grpc).GetNotifications(context.Background(), request)
+	notification_stream, err := (*m.grpc).GetNotifications(ctx, request)
+	if err != nil {
+		return nil, err
+	}
 
 	go func() {
+		defer close(notifications)
+		failures := 0
+		isShuttingDown := false
 		for {
 			notification, err := notification_stream.Recv()
-			if err != nil {
-				// log.Println(err)
-				break
+			if err == nil {
+				failures = 0
+				isShuttingDown = isShuttingDown || notification.Type == mafia_grpc.NotificationType_SHUTDOWN
+				// log.Println(notificationToString(notification))
+				notifications <- notification
+				continue
+			}
+
+			if (err == io.EOF && !isShuttingDown) || isFinalError(err) {
+				return
+			}
+
+			if failures == 0 && ctx.Err() == nil {
+				m.stdout.Println("Notifications connection lost, reconnecting ...")
+			}
+
+			for notification_stream = nil; notification_stream == nil; failures++ {
+				if failures >= ReconnectAttempts {
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(ReconnectDelay):
+				}
+
+				notification_stream, _ = (*m.grpc).GetNotifications(ctx, request)
 			}
-			// log.Println(notificationToString(notification))
-			notifications <- notification
 		}
 		// log.Println("Notification stream closed")
 	}()
 
-	return notifications, err
+	return notifications, nil
 }
@@ -1,19 +1,47 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"flag"
 	"log"
 	"os"
+	"time"
 
+	mafia_config "soa_mafia/pkg/mafia_config"
 	"soa_mafia/pkg/mafia_grpc"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
+/*
+	Time to connect to the server with TLS, without TLS the client waits for the server as long as it takes
+*/
+const TLSDialTimeout = 30 * time.Second
+
 func main() {
+	config, err := mafia_config.Load(flag.CommandLine, os.Args[1:], mafia_config.Client, mafia_config.RabbitMQ)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	ctx := context.Background()
+	options := []grpc.DialOption{grpc.WithInsecure(), grpc.WithBlock()}
+	if config.Client.TLSEnabled() {
+		tlsConfig, err := config.Client.TLSConfig()
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		// gRPC retries failed handshakes forever, so a wrong certificate would look like a server that is down
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, TLSDialTimeout)
+		defer cancel()
+		options = []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)), grpc.WithBlock(), grpc.WithReturnConnectionError()}
+	}
+
 	log.Println("Client running ...")
-	mafia_host := os.Getenv("MAFIA_HOST")
-	conn, err := grpc.Dial(fmt.Sprintf("%s:9000", mafia_host), grpc.WithInsecure(), grpc.WithBlock())
+	conn, err := grpc.DialContext(ctx, config.Client.ServerAddr, options...)
 	if err != nil {
 		log.Fatalln(err)
 	}
@@ -21,7 +49,7 @@ func main() {
 
 	grpc_client := mafia_grpc.NewMafiaClient(conn)
 
-	mafia_client := NewMafiaClient(grpc_client)
+	mafia_client := NewMafiaClient(grpc_client, config.RabbitMQ.URL())
 
 	mafia_client.Run()
 	// request := &mafia_grpc.JoinRequest{Player: &player_info}
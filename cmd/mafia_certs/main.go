@@ -0,0 +1,169 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	dir      = flag.String("dir", "certs", "directory for the certificates and keys")
+	hosts    = flag.String("hosts", "localhost,127.0.0.1,mafia_server", "names and IPs of the server separated by commas, empty skips the server certificate")
+	players  = flag.String("players", "", "names of the players separated by commas, each gets a client certificate for mutual TLS")
+	validFor = flag.Duration("valid_for", 365*24*time.Hour, "how long the issued certificates are valid")
+)
+
+/*
+	Issues certificates for testing TLS offline: the CA is created on the first run
+	and reused later, so certificates of new players can be added to the same directory
+*/
+func main() {
+	flag.Parse()
+
+	err := os.MkdirAll(*dir, 0700)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	ca, caKey, err := loadOrCreateCA()
+	if err != nil {
+		log.Fatalf("failed to prepare the CA: %v", err)
+	}
+
+	if *hosts != "" {
+		template := newTemplate("mafia server", x509.ExtKeyUsageServerAuth)
+		for _, host := range split(*hosts) {
+			if ip := net.ParseIP(host); ip != nil {
+				template.IPAddresses = append(template.IPAddresses, ip)
+			} else {
+				template.DNSNames = append(template.DNSNames, host)
+			}
+		}
+
+		err = issue("server", template, ca, caKey)
+		if err != nil {
+			log.Fatalf("failed to issue the server certificate: %v", err)
+		}
+	}
+
+	for _, player := range split(*players) {
+		err = issue(player, newTemplate(player, x509.ExtKeyUsageClientAuth), ca, caKey)
+		if err != nil {
+			log.Fatalf("failed to issue the certificate of %s: %v", player, err)
+		}
+	}
+}
+
+func loadOrCreateCA() (*x509.Certificate, crypto.Signer, error) {
+	certPath, keyPath := paths("ca")
+	pair, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err == nil {
+		ca, err := x509.ParseCertificate(pair.Certificate[0])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		fmt.Printf("Using CA %s\n", certPath)
+		return ca, pair.PrivateKey.(crypto.Signer), nil
+	}
+
+	if !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+
+	template := newTemplate("mafia CA", x509.ExtKeyUsageAny)
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+
+	err = issue("ca", template, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return loadOrCreateCA()
+}
+
+func newTemplate(commonName string, usage x509.ExtKeyUsage) *x509.Certificate {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(*validFor),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+}
+
+/*
+	Writes {name}.pem and {name}-key.pem, the certificate is self-signed if there is no CA
+*/
+func issue(name string, template *x509.Certificate, ca *x509.Certificate, caKey crypto.Signer) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	if ca == nil {
+		ca, caKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, key.Public(), caKey)
+	if err != nil {
+		return err
+	}
+
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	certPath, keyPath := paths(name)
+	err = os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}), 0600)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Issued %s for %s\n", certPath, template.Subject.CommonName)
+	return nil
+}
+
+func paths(name string) (string, string) {
+	return filepath.Join(*dir, name+".pem"), filepath.Join(*dir, name+"-key.pem")
+}
+
+func split(list string) []string {
+	result := []string{}
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
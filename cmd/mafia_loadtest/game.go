@@ -0,0 +1,518 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"soa_mafia/pkg/mafia_grpc"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+type Options struct {
+	Players     int32
+	Config      *mafia_grpc.SessionConfig
+	Timeout     time.Duration
+	CallTimeout time.Duration
+	InvalidRate float64
+	QuitRate    float64
+}
+
+/*
+	Result of one game. Game that doesn't finish in time is stuck, State is its last state.
+	Error means the game couldn't be set up
+*/
+type Result struct {
+	Session    string
+	Finished   bool
+	Duration   time.Duration
+	Violations []string
+	State      *mafia_grpc.GameState
+	Error      error
+}
+
+const moveAttempts = 3
+
+type check struct {
+	target  string
+	isMafia bool
+}
+
+/*
+	Game collects what the scripted players have seen, so the rules can be checked
+	when the mafia team is revealed at the end
+*/
+type game struct {
+	session string
+	client  mafia_grpc.MafiaClient
+	options Options
+
+	mu         sync.Mutex
+	roles      map[string]string
+	checks     []check
+	targets    map[int32]string
+	phases     map[string]*mafia_grpc.GameState
+	violations []string
+	finish     *mafia_grpc.Notification
+	done       chan struct{}
+}
+
+/*
+	Plays one game: every player joins, subscribes for notifications
+	and moves in reply to them until the game finishes or the time is up
+*/
+func playGame(client mafia_grpc.MafiaClient, session string, options Options, seed int64) Result {
+	start := time.Now()
+	g := &game{
+		session: session,
+		client:  client,
+		options: options,
+		roles:   make(map[string]string),
+		targets: make(map[int32]string),
+		phases:  make(map[string]*mafia_grpc.GameState),
+		done:    make(chan struct{}),
+	}
+	result := Result{Session: session}
+
+	players := []*player{}
+	for i := int32(0); i < options.Players; i++ {
+		p := &player{
+			game: g,
+			info: &mafia_grpc.PlayerInfo{Session: session, Name: fmt.Sprintf("p%d", i)},
+			rng:  rand.New(rand.NewSource(seed + int64(i))),
+			dead: make(map[string]bool),
+			gone: make(map[string]bool),
+		}
+
+		request := &mafia_grpc.JoinRequest{Player: p.info}
+		if i == 0 {
+			request.Config = options.Config
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), options.CallTimeout)
+		response, err := client.Join(ctx, request)
+		cancel()
+		if err != nil {
+			result.Error = fmt.Errorf("Player %s can't join: %v", p.info.Name, err)
+			return result
+		}
+
+		p.token = response.GetToken()
+		players = append(players, p)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, p := range players {
+		stream, err := client.GetNotifications(mafia_grpc.WithToken(ctx, p.token), &mafia_grpc.SubscribeRequest{Player: p.info})
+		if err != nil {
+			result.Error = fmt.Errorf("Player %s can't subscribe: %v", p.info.Name, err)
+			return result
+		}
+
+		wg.Add(1)
+		go func(p *player) {
+			defer wg.Done()
+			p.run(stream)
+		}(p)
+	}
+
+	timer := time.NewTimer(options.Timeout)
+	defer timer.Stop()
+
+	select {
+	case <-g.done:
+		result.Finished = true
+	case <-timer.C:
+		result.State = g.lastState(players)
+	}
+
+	result.Duration = time.Since(start)
+	cancel()
+	wg.Wait()
+
+	if result.Finished {
+		g.checkFinish()
+	}
+
+	result.Violations = g.violations
+	return result
+}
+
+func (g *game) call() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), g.options.CallTimeout)
+}
+
+func (g *game) violation(format string, args ...interface{}) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.violations = append(g.violations, fmt.Sprintf("%s: %s", g.session, fmt.Sprintf(format, args...)))
+}
+
+func (g *game) setRole(player string, role string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.roles[player] = role
+}
+
+func (g *game) addCheck(target string, isMafia bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.checks = append(g.checks, check{target, isMafia})
+}
+
+/*
+	Mafia agrees on the victim of the night as players would do in the chat,
+	otherwise the unanimity rule would never let the night end.
+	The target is replaced once some mafia finds out it's gone
+*/
+func (g *game) nightTarget(date int32, candidate string, isValid func(string) bool) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	target, exists := g.targets[date]
+	if exists && isValid(target) {
+		return target
+	}
+
+	g.targets[date] = candidate
+	return candidate
+}
+
+/*
+	Every player sees the same phases, the first copy is kept
+*/
+func (g *game) addPhase(state *mafia_grpc.GameState) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	key := fmt.Sprintf("%d/%t/%t", state.Date, state.IsDay, state.IsRunoff)
+	if _, exists := g.phases[key]; !exists {
+		g.phases[key] = state
+	}
+}
+
+func (g *game) finished(notification *mafia_grpc.Notification) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.finish == nil {
+		g.finish = notification
+		close(g.done)
+	}
+}
+
+/*
+	Checks the end of the game against the revealed mafia team: the game goes on
+	while mafia is alive and isn't everyone, roles and checks match the team
+*/
+func (g *game) checkFinish() {
+	team := make(map[string]bool)
+	for _, mafia := range g.finish.GetMafiaTeam() {
+		team[mafia] = true
+	}
+
+	countMafia := func(players []string) int {
+		count := 0
+		for _, player := range players {
+			if team[player] {
+				count++
+			}
+		}
+
+		return count
+	}
+
+	alive := g.finish.GetGameState().GetAlivePlayers()
+	if mafia := countMafia(alive); mafia != 0 && mafia != len(alive) {
+		g.violation("game finished with %d mafia of %d alive players", mafia, len(alive))
+	}
+
+	for _, state := range g.phases {
+		if state.IsFinished {
+			continue
+		}
+
+		if mafia := countMafia(state.AlivePlayers); mafia == 0 || mafia == len(state.AlivePlayers) {
+			g.violation("game went on at date %d with %d mafia of %d alive players", state.Date, mafia, len(state.AlivePlayers))
+		}
+	}
+
+	for player, role := range g.roles {
+		if (role == "Mafia") != team[player] {
+			g.violation("%s got role %s, mafia team is %v", player, role, g.finish.GetMafiaTeam())
+		}
+	}
+
+	for _, check := range g.checks {
+		if check.isMafia != team[check.target] {
+			g.violation("check of %s returned %t, mafia team is %v", check.target, check.isMafia, g.finish.GetMafiaTeam())
+		}
+	}
+}
+
+func (g *game) lastState(players []*player) *mafia_grpc.GameState {
+	for _, p := range players {
+		ctx, cancel := g.call()
+		state, err := g.client.GetState(mafia_grpc.WithToken(ctx, p.token), p.info)
+		cancel()
+		if err == nil {
+			return state
+		}
+	}
+
+	return nil
+}
+
+/*
+	Scripted player makes the move of its role in every phase
+	and sometimes a random move that may break the rules
+*/
+type player struct {
+	game  *game
+	info  *mafia_grpc.PlayerInfo
+	token string
+	rng   *rand.Rand
+
+	role    string
+	date    int32
+	dead    map[string]bool
+	gone    map[string]bool
+	players []string
+}
+
+func (p *player) run(stream mafia_grpc.Mafia_GetNotificationsClient) {
+	for {
+		notification, err := stream.Recv()
+		if err != nil {
+			return
+		}
+
+		if !p.handle(notification) {
+			return
+		}
+	}
+}
+
+/*
+	Returns false when the player has nothing more to do
+*/
+func (p *player) handle(notification *mafia_grpc.Notification) bool {
+	state := notification.GetGameState()
+	for _, alive := range state.GetAlivePlayers() {
+		if p.dead[alive] {
+			p.game.violation("%s is alive again in the notification %s to %s", alive, notification.Type, p.info.Name)
+		}
+	}
+
+	switch notification.Type {
+	case mafia_grpc.NotificationType_START:
+		p.role = notification.GetRole()
+		p.players = state.GetAlivePlayers()
+		p.game.setRole(p.info.Name, p.role)
+	case mafia_grpc.NotificationType_NEW_DAY, mafia_grpc.NotificationType_NEW_NIGHT:
+		for _, killed := range strings.Split(notification.GetKilledPlayer(), ", ") {
+			if killed != "" {
+				p.dead[killed] = true
+			}
+		}
+	case mafia_grpc.NotificationType_RUNOFF:
+	case mafia_grpc.NotificationType_FINISH:
+		p.game.finished(notification)
+		return false
+	default:
+		return true
+	}
+
+	p.date = state.GetDate()
+	p.game.addPhase(state)
+	if state.GetIsFinished() {
+		return true
+	}
+
+	if !p.dead[p.info.Name] {
+		p.move(state)
+	}
+
+	if p.rng.Float64() < p.game.options.InvalidRate {
+		p.randomMove()
+	}
+
+	if !p.dead[p.info.Name] && p.rng.Float64() < p.game.options.QuitRate {
+		ctx, cancel := p.game.call()
+		defer cancel()
+		p.game.client.Quit(mafia_grpc.WithToken(ctx, p.token), p.info)
+		return false
+	}
+
+	return true
+}
+
+/*
+	Players who quit aren't announced as killed, the server tells it when
+	such player is chosen as the target, then another target is chosen.
+	Replies of the calls are ahead of the notifications, so such players
+	are kept apart from the announced deaths
+*/
+func (p *player) move(state *mafia_grpc.GameState) {
+	for attempt := 0; attempt < moveAttempts; attempt++ {
+		target, err := p.tryMove(state)
+		if target == "" || !isTargetError(err) {
+			return
+		}
+
+		p.gone[target] = true
+	}
+}
+
+func (p *player) tryMove(state *mafia_grpc.GameState) (string, error) {
+	ctx, cancel := p.game.call()
+	defer cancel()
+	ctx = mafia_grpc.WithToken(ctx, p.token)
+
+	if state.IsDay {
+		candidates := state.AlivePlayers
+		if state.IsRunoff {
+			candidates = state.RunoffCandidates
+		}
+
+		target := p.pick(candidates)
+		if target == "" || p.rng.Float64() < 0.1 {
+			_, err := p.game.client.SkipVote(ctx, p.info)
+			return "", err
+		}
+
+		_, err := p.game.client.Vote(ctx, p.request(target))
+		if err == nil && p.rng.Float64() < 0.1 {
+			p.game.client.ChangeVote(ctx, p.request(p.pick(candidates)))
+		}
+		return target, err
+	}
+
+	target := p.pick(state.AlivePlayers)
+	if target == "" {
+		return "", nil
+	}
+
+	var err error
+	switch p.role {
+	case "Mafia":
+		target = p.game.nightTarget(state.Date, target, p.isAlive)
+		_, err = p.game.client.Kill(ctx, p.request(target))
+	case "Detective":
+		var response *mafia_grpc.CheckMafiaResponse
+		response, err = p.game.client.CheckIfMafia(ctx, p.request(target))
+		if err == nil {
+			p.game.addCheck(target, response.GetIsMafia())
+		}
+	case "Doctor":
+		_, err = p.game.client.Heal(ctx, p.request(target))
+	}
+
+	return target, err
+}
+
+func isTargetError(err error) bool {
+	for _, detail := range status.Convert(err).Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if ok && (info.Reason == mafia_grpc.ErrorReason_TARGET_DEAD.String() || info.Reason == mafia_grpc.ErrorReason_TARGET_NOT_FOUND.String()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+	Any action on any player, dead ones and strangers included.
+	Action the rules forbid whatever the phase is must be rejected
+*/
+func (p *player) randomMove() {
+	ctx, cancel := p.game.call()
+	defer cancel()
+	ctx = mafia_grpc.WithToken(ctx, p.token)
+
+	targets := append([]string{"stranger"}, p.players...)
+	target := targets[p.rng.Intn(len(targets))]
+	isDead := p.dead[p.info.Name]
+	isTargetValid := target != "stranger" && p.isAlive(target)
+
+	var err error
+	allowed := false
+	action := ""
+	switch p.rng.Intn(5) {
+	case 0:
+		action = "Vote"
+		_, err = p.game.client.Vote(ctx, p.request(target))
+		allowed = !isDead && isTargetValid
+	case 1:
+		action = "Kill"
+		if p.role == "Mafia" && isTargetValid {
+			target = p.game.nightTarget(p.date, target, p.isAlive)
+		}
+		_, err = p.game.client.Kill(ctx, p.request(target))
+		allowed = !isDead && isTargetValid && p.role == "Mafia"
+	case 2:
+		action = "CheckIfMafia"
+		var response *mafia_grpc.CheckMafiaResponse
+		response, err = p.game.client.CheckIfMafia(ctx, p.request(target))
+		allowed = !isDead && isTargetValid && p.role == "Detective"
+		if err == nil {
+			p.game.addCheck(target, response.GetIsMafia())
+		}
+	case 3:
+		action = "Heal"
+		_, err = p.game.client.Heal(ctx, p.request(target))
+		allowed = !isDead && isTargetValid && p.role == "Doctor"
+	case 4:
+		action = "SkipVote"
+		_, err = p.game.client.SkipVote(ctx, p.info)
+		allowed = !isDead
+	}
+
+	if err == nil && !allowed {
+		p.game.violation("%s (%s, dead: %t) was allowed to %s %s", p.info.Name, p.role, isDead, action, target)
+	}
+}
+
+/*
+	Returns a random alive player other than this one, empty if there is none
+*/
+func (p *player) pick(players []string) string {
+	candidates := []string{}
+	for _, player := range players {
+		if p.isTarget(player) {
+			candidates = append(candidates, player)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	sort.Strings(candidates)
+	return candidates[p.rng.Intn(len(candidates))]
+}
+
+func (p *player) isTarget(player string) bool {
+	return player != p.info.Name && p.isAlive(player)
+}
+
+func (p *player) isAlive(player string) bool {
+	return !p.dead[player] && !p.gone[player]
+}
+
+func (p *player) request(target string) *mafia_grpc.SetVictimRequest {
+	return &mafia_grpc.SetVictimRequest{Player: p.info, Victim: target}
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	mafia_config "soa_mafia/pkg/mafia_config"
+	"soa_mafia/pkg/mafia_grpc"
+	mafia_bot "soa_mafia/server/mafia_bot"
+	mafia_impl "soa_mafia/server/mafia_impl"
+	mafia_server "soa_mafia/server/mafia_server"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var (
+	mode        = flag.String("mode", "inprocess", "where the server runs: inprocess (in memory connection), loopback (TCP on 127.0.0.1) or remote at -server_addr")
+	games       = flag.Int("games", 1000, "number of games to play")
+	concurrency = flag.Int("concurrency", 50, "number of games played at the same time")
+	players     = flag.Int("players", 6, "number of players in every game")
+	mafia       = flag.Int("mafia", 0, "number of mafia, 0 means one for every four players")
+	detectives  = flag.Int("detectives", 1, "number of detectives")
+	doctors     = flag.Int("doctors", 1, "number of doctors")
+	killRule    = flag.String("kill_rule", "leader", "kill rule of the sessions: leader, majority or unanimous")
+	selfHeal    = flag.Bool("self_heal", true, "doctor may heal themself")
+	repeatHeal  = flag.Bool("repeat_heal", true, "doctor may heal the same player two nights in a row")
+	runoff      = flag.Bool("runoff", true, "tied day votes go to the runoff")
+	openBallot  = flag.Bool("open_ballot", true, "players see the votes of each other")
+	phase       = flag.Int("phase_duration", 0, "duration of days and nights in seconds, 0 means a phase lasts until everyone acts")
+	timeout     = flag.Duration("timeout", 30*time.Second, "game that doesn't finish in this time is reported as stuck")
+	callTimeout = flag.Duration("call_timeout", 5*time.Second, "timeout of every call")
+	invalidRate = flag.Float64("invalid_rate", 0.2, "probability of a random move, that may break the rules, after every notification")
+	quitRate    = flag.Float64("quit_rate", 0.005, "probability that a player quits after a notification")
+	seed        = flag.Int64("seed", 0, "seed of the moves of the players, 0 means a random seed")
+	verbose     = flag.Bool("verbose", false, "keep the log of the in-process server")
+)
+
+/*
+	Starts the server in this process and returns the connection to it.
+	In-process mode skips the network, loopback mode goes through the TCP stack
+*/
+func startServer(options ...grpc.DialOption) (*grpc.ClientConn, error) {
+	mafiaServer := mafia_server.NewServer(mafia_bot.Options{}, mafia_impl.DefaultConfig())
+	go mafiaServer.RunCleanup(10*time.Second, *timeout, 2**timeout)
+
+	srv := grpc.NewServer()
+	mafia_grpc.RegisterMafiaServer(srv, mafiaServer)
+
+	if *mode == "inprocess" {
+		lis := bufconn.Listen(1 << 20)
+		go srv.Serve(lis)
+
+		dialer := func(ctx context.Context, address string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}
+		return grpc.Dial("bufconn", append(options, grpc.WithContextDialer(dialer))...)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	go srv.Serve(lis)
+
+	return grpc.Dial(lis.Addr().String(), options...)
+}
+
+func sessionConfig() (*mafia_grpc.SessionConfig, error) {
+	rule, exists := mafia_grpc.KillRule_value[strings.ToUpper(*killRule)]
+	if !exists {
+		return nil, fmt.Errorf("Unknown kill rule: %s", *killRule)
+	}
+
+	mafiaCount := *mafia
+	if mafiaCount == 0 {
+		mafiaCount = *players / 4
+	}
+
+	if mafiaCount == 0 {
+		mafiaCount = 1
+	}
+
+	return &mafia_grpc.SessionConfig{
+		Players: int32(*players),
+		Roles: map[string]int32{
+			"Mafia":     int32(mafiaCount),
+			"Detective": int32(*detectives),
+			"Doctor":    int32(*doctors),
+		},
+		KillRule:         mafia_grpc.KillRule(rule),
+		HasKillRule:      true,
+		DoctorSelfHeal:   *selfHeal,
+		DoctorRepeatHeal: *repeatHeal,
+		Runoff:           *runoff,
+		OpenBallot:       *openBallot,
+		DayDuration:      int32(*phase),
+		NightDuration:    int32(*phase),
+	}, nil
+}
+
+/*
+	Connection to the remote server is set up by the client section of the shared config,
+	with TLS if it's enabled. Players of the load test have many names, so a client
+	certificate, which is issued to one player, can't be used
+*/
+func dialRemote(config mafia_config.ClientConfig, options []grpc.DialOption) (*grpc.ClientConn, error) {
+	if !config.TLSEnabled() {
+		return grpc.Dial(config.ServerAddr, append(options, grpc.WithInsecure())...)
+	}
+
+	if config.TLSClientCert != "" {
+		return nil, fmt.Errorf("Load test plays under many names, a client certificate fits only one")
+	}
+
+	tlsConfig, err := config.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return grpc.Dial(config.ServerAddr, append(options, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))...)
+}
+
+func main() {
+	clientConfig, err := mafia_config.Load(flag.CommandLine, os.Args[1:], mafia_config.Client)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	config, err := sessionConfig()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	stats := NewStats()
+	options := []grpc.DialOption{
+		grpc.WithUnaryInterceptor(stats.UnaryInterceptor),
+		grpc.WithStreamInterceptor(stats.StreamInterceptor),
+	}
+
+	var conn *grpc.ClientConn
+	switch *mode {
+	case "inprocess", "loopback":
+		if !*verbose {
+			log.SetOutput(io.Discard)
+		}
+		conn, err = startServer(append(options, grpc.WithInsecure())...)
+	case "remote":
+		conn, err = dialRemote(clientConfig.Client, options)
+	default:
+		err = fmt.Errorf("Unknown mode: %s", *mode)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	defer conn.Close()
+
+	client := mafia_grpc.NewMafiaClient(conn)
+	gameOptions := Options{
+		Players:     int32(*players),
+		Config:      config,
+		Timeout:     *timeout,
+		CallTimeout: *callTimeout,
+		InvalidRate: *invalidRate,
+		QuitRate:    *quitRate,
+	}
+
+	fmt.Printf("Playing %d games of %d players, %d at a time, mode %s, seed %d\n", *games, *players, *concurrency, *mode, *seed)
+
+	run := time.Now()
+	results := make(chan Result)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < *concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				session := fmt.Sprintf("load-%d-%d", run.Unix(), job)
+				results <- playGame(client, session, gameOptions, *seed+int64(job)*int64(*players))
+			}
+		}()
+	}
+
+	go func() {
+		for job := 0; job < *games; job++ {
+			jobs <- job
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	report := newReport()
+	progress := time.NewTicker(5 * time.Second)
+	defer progress.Stop()
+
+	for done := false; !done; {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				done = true
+				break
+			}
+			report.add(result)
+		case <-progress.C:
+			fmt.Printf("... %s\n", report.summary(time.Since(run)))
+		}
+	}
+
+	elapsed := time.Since(run)
+	calls, timeouts := stats.Count(codes.DeadlineExceeded, codes.Unavailable, codes.Internal, codes.Unknown)
+
+	fmt.Println()
+	fmt.Println(report.summary(elapsed))
+	fmt.Printf("Throughput: %.1f games/s, %.0f calls/s, %d calls failed with timeouts or server errors\n",
+		float64(report.finished)/elapsed.Seconds(), float64(calls)/elapsed.Seconds(), timeouts)
+	fmt.Println()
+	fmt.Print(stats.String())
+	fmt.Print(report.details())
+
+	if report.stuck > 0 || len(report.violations) > 0 || report.failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+/*
+	Details of this many stuck games, failures and violations are printed
+*/
+const reportLimit = 10
+
+type report struct {
+	finished   int
+	stuck      int
+	failed     int
+	durations  []time.Duration
+	stuckGames []Result
+	errors     []string
+	violations []string
+}
+
+func newReport() *report {
+	return &report{}
+}
+
+func (r *report) add(result Result) {
+	r.violations = append(r.violations, result.Violations...)
+
+	switch {
+	case result.Error != nil:
+		r.failed++
+		r.errors = append(r.errors, fmt.Sprintf("%s: %v", result.Session, result.Error))
+	case result.Finished:
+		r.finished++
+		r.durations = append(r.durations, result.Duration)
+	default:
+		r.stuck++
+		r.stuckGames = append(r.stuckGames, result)
+	}
+}
+
+func (r *report) summary(elapsed time.Duration) string {
+	return fmt.Sprintf("Games: %d finished, %d stuck, %d failed to start, %d rule violations in %s",
+		r.finished, r.stuck, r.failed, len(r.violations), elapsed.Round(time.Millisecond))
+}
+
+func (r *report) details() string {
+	var sb strings.Builder
+
+	if len(r.durations) > 0 {
+		sort.Slice(r.durations, func(i, j int) bool {
+			return r.durations[i] < r.durations[j]
+		})
+
+		sb.WriteString(fmt.Sprintf("\nGame duration: p50 %s, p90 %s, p99 %s, max %s\n",
+			percentile(r.durations, 0.5),
+			percentile(r.durations, 0.9),
+			percentile(r.durations, 0.99),
+			percentile(r.durations, 1),
+		))
+	}
+
+	if len(r.stuckGames) > 0 {
+		sb.WriteString("\nStuck games:\n")
+		for _, result := range first(r.stuckGames) {
+			sb.WriteString(fmt.Sprintf("- %s: %s\n", result.Session, result.State))
+		}
+	}
+
+	if len(r.errors) > 0 {
+		sb.WriteString("\nGames that failed to start:\n")
+		for _, err := range firstStrings(r.errors) {
+			sb.WriteString(fmt.Sprintf("- %s\n", err))
+		}
+	}
+
+	if len(r.violations) > 0 {
+		sb.WriteString("\nRule violations:\n")
+		for _, violation := range firstStrings(r.violations) {
+			sb.WriteString(fmt.Sprintf("- %s\n", violation))
+		}
+	}
+
+	return sb.String()
+}
+
+func first(results []Result) []Result {
+	if len(results) > reportLimit {
+		return results[:reportLimit]
+	}
+
+	return results
+}
+
+func firstStrings(lines []string) []string {
+	if len(lines) > reportLimit {
+		return lines[:reportLimit]
+	}
+
+	return lines
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+/*
+	Latencies of the calls of one RPC. Errors are counted by code,
+	calls rejected by the rules are expected, timeouts and internal errors are not
+*/
+type rpcStats struct {
+	latencies []time.Duration
+	errors    map[codes.Code]int
+}
+
+type Stats struct {
+	mu   sync.Mutex
+	rpcs map[string]*rpcStats
+}
+
+func NewStats() *Stats {
+	return &Stats{rpcs: make(map[string]*rpcStats)}
+}
+
+func (s *Stats) record(method string, latency time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := path.Base(method)
+	stats, exists := s.rpcs[name]
+	if !exists {
+		stats = &rpcStats{errors: make(map[codes.Code]int)}
+		s.rpcs[name] = stats
+	}
+
+	stats.latencies = append(stats.latencies, latency)
+	if err != nil {
+		stats.errors[status.Code(err)]++
+	}
+}
+
+/*
+	Measures every unary call of the client
+*/
+func (s *Stats) UnaryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	s.record(method, time.Since(start), err)
+	return err
+}
+
+/*
+	Measures the time to open the stream, the stream itself lasts the whole game
+*/
+func (s *Stats) StreamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	start := time.Now()
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	s.record(method, time.Since(start), err)
+	return stream, err
+}
+
+/*
+	Returns the number of calls and the number of failed calls with the given codes
+*/
+func (s *Stats) Count(failures ...codes.Code) (int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	calls, failed := 0, 0
+	for _, stats := range s.rpcs {
+		calls += len(stats.latencies)
+		for _, code := range failures {
+			failed += stats.errors[code]
+		}
+	}
+
+	return calls, failed
+}
+
+func (s *Stats) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.rpcs))
+	for name := range s.rpcs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%-18s %9s %9s %10s %10s %10s %10s  %s\n", "RPC", "calls", "errors", "p50", "p90", "p99", "max", "error codes"))
+	for _, name := range names {
+		stats := s.rpcs[name]
+		sort.Slice(stats.latencies, func(i, j int) bool {
+			return stats.latencies[i] < stats.latencies[j]
+		})
+
+		errors := 0
+		codeCounts := []string{}
+		for code, count := range stats.errors {
+			errors += count
+			codeCounts = append(codeCounts, fmt.Sprintf("%s=%d", code, count))
+		}
+		sort.Strings(codeCounts)
+
+		sb.WriteString(fmt.Sprintf("%-18s %9d %9d %10s %10s %10s %10s  %s\n",
+			name,
+			len(stats.latencies),
+			errors,
+			percentile(stats.latencies, 0.5),
+			percentile(stats.latencies, 0.9),
+			percentile(stats.latencies, 0.99),
+			percentile(stats.latencies, 1),
+			strings.Join(codeCounts, " "),
+		))
+	}
+
+	return sb.String()
+}
+
+/*
+	Latencies must be sorted
+*/
+func percentile(latencies []time.Duration, p float64) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+
+	index := int(p*float64(len(latencies))+0.5) - 1
+	if index < 0 {
+		index = 0
+	}
+
+	if index >= len(latencies) {
+		index = len(latencies) - 1
+	}
+
+	return latencies[index].Round(time.Microsecond)
+}
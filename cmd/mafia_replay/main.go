@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	mafia_config "soa_mafia/pkg/mafia_config"
+	mafia_impl "soa_mafia/server/mafia_impl"
+	mafia_storage "soa_mafia/server/mafia_storage"
+)
+
+var quiet = flag.Bool("quiet", false, "print only the final state and the result, without the events")
+
+func usage() {
+	var sb strings.Builder
+
+	sb.WriteString("Usage: mafia_replay [flags] {game_id}\n\n")
+	sb.WriteString("Replays the saved log of the game from the data directory of the server,\n")
+	sb.WriteString("checks that the replay matches the log and prints the events and the result.\n")
+	sb.WriteString("Logs are kept in the logs subdirectory of -data_dir, named by the game id.\n\n")
+	sb.WriteString("Flags:\n")
+
+	fmt.Fprint(flag.CommandLine.Output(), sb.String())
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = usage
+	// Replayed game logs its moves as the original did, they repeat the printed events
+	log.SetOutput(io.Discard)
+
+	config, err := mafia_config.Load(flag.CommandLine, os.Args[1:], mafia_config.Server)
+	if err != nil {
+		fail(err)
+	}
+
+	if flag.NArg() != 1 || config.Server.DataDir == "" {
+		usage()
+		os.Exit(2)
+	}
+
+	storage, err := mafia_storage.NewStorage(config.Server.DataDir)
+	if err != nil {
+		fail(err)
+	}
+
+	id := flag.Arg(0)
+	events, err := storage.LoadLog(id)
+	if err != nil {
+		fail(fmt.Errorf("Log of the game %s can't be read: %w", id, err))
+	}
+
+	game, err := mafia_impl.Verify(events)
+	if err != nil {
+		fail(err)
+	}
+
+	if !*quiet {
+		for _, event := range events {
+			fmt.Println(formatEvent(event))
+		}
+		fmt.Println()
+	}
+
+	fmt.Print(game.String())
+
+	result, finished := game.Result()
+	if !finished {
+		fmt.Println("Game has no winner")
+		return
+	}
+
+	winners := "civilians"
+	if result.MafiaWon {
+		winners = "mafia"
+	}
+	fmt.Printf("Winners: %s, days: %d\n", winners, result.Date)
+	for _, player := range result.Players {
+		fmt.Printf("  %s (%s) won=%v survived=%v\n", player.Name, player.Role, player.Won, player.Survived)
+	}
+}
+
+func formatEvent(event mafia_impl.Event) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "%s %s", event.Time.Format(time.RFC3339), event.Type)
+	if event.Player != "" {
+		fmt.Fprintf(&sb, " player=%s", event.Player)
+	}
+	if event.Target != "" {
+		fmt.Fprintf(&sb, " target=%s", event.Target)
+	}
+	if event.Date != 0 {
+		fmt.Fprintf(&sb, " date=%d", event.Date)
+	}
+	if event.Text != "" {
+		fmt.Fprintf(&sb, " text=%q", event.Text)
+	}
+	names := make([]string, 0, len(event.Roles))
+	for name := range event.Roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&sb, " %s=%s", name, event.Roles[name])
+	}
+
+	return sb.String()
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "Error:", err)
+	os.Exit(1)
+}
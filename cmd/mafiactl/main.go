@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	mafia_config "soa_mafia/pkg/mafia_config"
+	"soa_mafia/pkg/mafia_grpc"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var timeout = flag.Duration("timeout", 10*time.Second, "timeout of the call")
+
+func usage() {
+	var sb strings.Builder
+
+	sb.WriteString("Usage: mafiactl [flags] {command} [arguments]\n\n")
+	sb.WriteString("Commands:\n")
+	sb.WriteString("  list [waiting|started|finished]     list sessions with their players and roles\n")
+	sb.WriteString("  finish {session} [reason]           stop the game of the session without a winner\n")
+	sb.WriteString("  delete {session} [reason]           remove the session and its game\n")
+	sb.WriteString("  kick {session} {player} [reason]    remove the player from the game\n")
+	sb.WriteString("  notice [-session {session}] {text}  send the notice to one session or to all of them\n\n")
+	sb.WriteString("Flags:\n")
+
+	fmt.Fprint(flag.CommandLine.Output(), sb.String())
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = usage
+	config, err := mafia_config.Load(flag.CommandLine, os.Args[1:], mafia_config.Admin)
+	if err != nil {
+		fail(err)
+	}
+
+	args := flag.Args()
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	conn, err := grpc.Dial(config.Admin.Addr, grpc.WithInsecure())
+	if err != nil {
+		fail(err)
+	}
+	defer conn.Close()
+
+	client := mafia_grpc.NewMafiaAdminClient(conn)
+	ctx, cancel := context.WithTimeout(mafia_grpc.WithAdminToken(context.Background(), config.Admin.Token), *timeout)
+	defer cancel()
+
+	cmd, args := args[0], args[1:]
+	switch cmd {
+	case "list":
+		err = list(ctx, client, args)
+	case "finish":
+		err = finish(ctx, client, args)
+	case "delete":
+		err = deleteSession(ctx, client, args)
+	case "kick":
+		err = kick(ctx, client, args)
+	case "notice":
+		err = notice(ctx, client, args)
+	default:
+		err = fmt.Errorf("Unknown command: %s", cmd)
+	}
+
+	if err != nil {
+		fail(err)
+	}
+}
+
+func list(ctx context.Context, client mafia_grpc.MafiaAdminClient, args []string) error {
+	statuses := []mafia_grpc.SessionStatus{}
+	for _, arg := range args {
+		value, exists := mafia_grpc.SessionStatus_value[strings.ToUpper(arg)]
+		if !exists {
+			return fmt.Errorf("Unknown status: %s", arg)
+		}
+		statuses = append(statuses, mafia_grpc.SessionStatus(value))
+	}
+
+	response, err := client.ListGames(ctx, &mafia_grpc.ListSessionsRequest{Statuses: statuses})
+	if err != nil {
+		return err
+	}
+
+	if len(response.GetGames()) == 0 {
+		fmt.Println("No sessions")
+	}
+
+	for _, game := range response.GetGames() {
+		info := game.GetInfo()
+		fmt.Printf("%s [%s] players: %d/%d, bots: %d, spectators: %d",
+			info.Session, strings.ToLower(info.Status.String()), info.Players, info.Capacity, info.Bots, info.Spectators)
+		if info.Private {
+			fmt.Print(", private")
+		}
+		fmt.Println()
+		fmt.Println(indent(game.GetDetails()))
+	}
+
+	return nil
+}
+
+func finish(ctx context.Context, client mafia_grpc.MafiaAdminClient, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Usage: finish {session} [reason]")
+	}
+
+	_, err := client.FinishGame(ctx, &mafia_grpc.AdminSessionRequest{Session: args[0], Reason: strings.Join(args[1:], " ")})
+	if err == nil {
+		fmt.Printf("Game of session %s is finished\n", args[0])
+	}
+	return err
+}
+
+func deleteSession(ctx context.Context, client mafia_grpc.MafiaAdminClient, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Usage: delete {session} [reason]")
+	}
+
+	_, err := client.DeleteSession(ctx, &mafia_grpc.AdminSessionRequest{Session: args[0], Reason: strings.Join(args[1:], " ")})
+	if err == nil {
+		fmt.Printf("Session %s is deleted\n", args[0])
+	}
+	return err
+}
+
+func kick(ctx context.Context, client mafia_grpc.MafiaAdminClient, args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("Usage: kick {session} {player} [reason]")
+	}
+
+	_, err := client.KickPlayer(ctx, &mafia_grpc.KickRequest{Session: args[0], Player: args[1], Reason: strings.Join(args[2:], " ")})
+	if err == nil {
+		fmt.Printf("Player %s is removed from session %s\n", args[1], args[0])
+	}
+	return err
+}
+
+func notice(ctx context.Context, client mafia_grpc.MafiaAdminClient, args []string) error {
+	flags := flag.NewFlagSet("notice", flag.ContinueOnError)
+	session := flags.String("session", "", "session to send the notice to, all sessions by default")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	text := strings.Join(flags.Args(), " ")
+	if text == "" {
+		return fmt.Errorf("Usage: notice [-session {session}] {text}")
+	}
+
+	response, err := client.Broadcast(ctx, &mafia_grpc.NoticeRequest{Session: *session, Text: text})
+	if err == nil {
+		fmt.Printf("Notice is sent to %d sessions\n", response.GetSessions())
+	}
+	return err
+}
+
+func indent(text string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	return "\t" + strings.Join(lines, "\n\t")
+}
+
+/*
+	Prints the error with its reason, see ErrorReason
+*/
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "Error: "+errorToString(err))
+	os.Exit(1)
+}
+
+func errorToString(err error) string {
+	st := status.Convert(err)
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if ok {
+			return fmt.Sprintf("%s (%s)", st.Message(), info.GetReason())
+		}
+	}
+
+	return err.Error()
+}
@@ -4,41 +4,33 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
 	"soa_mafia/pkg/mafia_grpc"
 	"time"
 )
 
-const (
-	RabbitmqUrlPrefix = "amqp://guest:guest@"
-	RabbitmqHost      = "localhost"
-	RabbitmqPort      = "5672"
-)
-
 type MafiaMessenger struct {
 	messenger *Messenger
 
 	grpc   *mafia_grpc.MafiaClient
 	player *mafia_grpc.PlayerInfo
+	token  string
 }
 
-func NewMafiaMessenger(grpc *mafia_grpc.MafiaClient, player *mafia_grpc.PlayerInfo) *MafiaMessenger {
-	rabbitHost := os.Getenv("RABBITMQ_HOST")
-	if len(rabbitHost) == 0 {
-		rabbitHost = RabbitmqHost
-	}
-
-	rabbitmqUrl := fmt.Sprintf("%s%s:%s", RabbitmqUrlPrefix, rabbitHost, RabbitmqPort)
-
+/*
+	Chat of every game is a separate exchange named by the game id,
+	so games played under the same session name don't share messages
+*/
+func NewMafiaMessenger(rabbitmqUrl string, grpc *mafia_grpc.MafiaClient, player *mafia_grpc.PlayerInfo, token string, gameId string) *MafiaMessenger {
 	return &MafiaMessenger{
-		NewMessenger(rabbitmqUrl, player.GetSession()),
+		NewMessenger(rabbitmqUrl, gameId),
 		grpc,
 		player,
+		token,
 	}
 }
 
 func (m *MafiaMessenger) Send(msg string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	ctx, cancel := context.WithTimeout(mafia_grpc.WithToken(context.Background(), m.token), time.Second)
 	defer cancel()
 
 	response, err := (*m.grpc).CanChat(ctx, m.player)
@@ -0,0 +1,476 @@
+package mafia_config
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"soa_mafia/pkg/mafia_grpc"
+
+	"gopkg.in/yaml.v3"
+)
+
+/*
+	Path of the config file is taken from the -config flag or from this variable
+*/
+const ConfigEnv = "MAFIA_CONFIG"
+
+/*
+	Every flag of the config can be set by the variable named by the flag with this prefix,
+	e.g. -listen_addr by MAFIA_LISTEN_ADDR
+*/
+const EnvPrefix = "MAFIA_"
+
+/*
+	Section is the part of the config a binary uses, only flags of its sections are added
+*/
+type Section int
+
+const (
+	Server Section = iota
+	Game
+	Admin
+	Client
+	RabbitMQ
+)
+
+/*
+	Config holds the settings of all binaries, so a single file can describe an environment.
+	Values are taken from the defaults, the YAML file, the environment and the flags,
+	every source overrides the previous ones
+*/
+type Config struct {
+	Server   ServerConfig   `yaml:"server"`
+	Game     GameConfig     `yaml:"game"`
+	Admin    AdminConfig    `yaml:"admin"`
+	Client   ClientConfig   `yaml:"client"`
+	RabbitMQ RabbitMQConfig `yaml:"rabbitmq"`
+}
+
+type ServerConfig struct {
+	ListenAddr       string        `yaml:"listen_addr"`
+	MetricsAddr      string        `yaml:"metrics_addr"`
+	DataDir          string        `yaml:"data_dir"`
+	SnapshotInterval time.Duration `yaml:"snapshot_interval"`
+	FinishedTTL      time.Duration `yaml:"finished_ttl"`
+	IdleTTL          time.Duration `yaml:"idle_ttl"`
+	CleanupInterval  time.Duration `yaml:"cleanup_interval"`
+	BotDelay         time.Duration `yaml:"bot_delay"`
+	BotFillInterval  time.Duration `yaml:"bot_fill_interval"`
+	DrainTimeout     time.Duration `yaml:"drain_timeout"`
+
+	TLSCert     string `yaml:"tls_cert"`
+	TLSKey      string `yaml:"tls_key"`
+	TLSClientCA string `yaml:"tls_client_ca"`
+}
+
+/*
+	GameConfig holds defaults of the sessions created without these values.
+	Roles are used only for tables of the default size, other tables get
+	the standard composition
+*/
+type GameConfig struct {
+	Players           int32            `yaml:"players"`
+	Roles             map[string]int32 `yaml:"roles"`
+	KillRule          string           `yaml:"kill_rule"`
+	DayDuration       time.Duration    `yaml:"day_duration"`
+	NightDuration     time.Duration    `yaml:"night_duration"`
+	BotFillDelay      time.Duration    `yaml:"bot_fill_delay"`
+	ReconnectGrace    time.Duration    `yaml:"reconnect_grace"`
+	NotificationLimit int              `yaml:"notification_limit"`
+	OverflowPolicy    string           `yaml:"overflow_policy"`
+}
+
+/*
+	Admin service address is shared by the server, which listens on it, and mafiactl
+*/
+type AdminConfig struct {
+	Addr  string `yaml:"addr"`
+	Token string `yaml:"token"`
+}
+
+type ClientConfig struct {
+	ServerAddr string `yaml:"server_addr"`
+
+	TLS           bool   `yaml:"tls"`
+	TLSCA         string `yaml:"tls_ca"`
+	TLSClientCert string `yaml:"tls_client_cert"`
+	TLSClientKey  string `yaml:"tls_client_key"`
+	TLSServerName string `yaml:"tls_server_name"`
+}
+
+type RabbitMQConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+}
+
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			ListenAddr:       ":9000",
+			MetricsAddr:      ":9002",
+			DataDir:          "data",
+			SnapshotInterval: time.Second,
+			FinishedTTL:      10 * time.Minute,
+			IdleTTL:          time.Hour,
+			CleanupInterval:  time.Minute,
+			BotDelay:         3 * time.Second,
+			BotFillInterval:  time.Second,
+		},
+		Game: GameConfig{
+			Players:           4,
+			Roles:             map[string]int32{"mafia": 1, "detective": 1},
+			KillRule:          "leader",
+			ReconnectGrace:    time.Minute,
+			NotificationLimit: 64,
+			OverflowPolicy:    "drop_oldest",
+		},
+		Admin: AdminConfig{
+			Addr: "localhost:9001",
+		},
+		Client: ClientConfig{
+			ServerAddr: "localhost:9000",
+		},
+		RabbitMQ: RabbitMQConfig{
+			Host:     "localhost",
+			Port:     5672,
+			User:     "guest",
+			Password: "guest",
+		},
+	}
+}
+
+/*
+	Variables of the earlier versions, used when the variable of the flag isn't set
+*/
+var legacyEnv = map[string]struct {
+	name   string
+	format string
+}{
+	"server_addr":   {"MAFIA_HOST", "%s:9000"},
+	"rabbitmq_host": {"RABBITMQ_HOST", "%s"},
+}
+
+/*
+	Adds flags of the sections and -config to the flag set, parses the arguments
+	and returns the checked config. Flags of the binary itself may be added
+	to the set before, they are parsed together but aren't read from the environment
+*/
+func Load(fs *flag.FlagSet, args []string, sections ...Section) (*Config, error) {
+	config := Default()
+	path := fs.String("config", "", "YAML config file, "+ConfigEnv+" is used if the flag isn't set")
+
+	before := flagNames(fs)
+	for _, section := range sections {
+		config.addFlags(fs, section)
+	}
+
+	own := make(map[string]bool)
+	for name := range flagNames(fs) {
+		if !before[name] {
+			own[name] = true
+		}
+	}
+
+	err := fs.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	// Flags are parsed into the config first, so they are set again after the file and the environment
+	set := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		if own[f.Name] {
+			set[f.Name] = f.Value.String()
+		}
+	})
+
+	if *path == "" {
+		*path = os.Getenv(ConfigEnv)
+	}
+
+	config = Default()
+	if *path != "" {
+		err = config.readFile(*path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for name := range own {
+		value, exists := lookupEnv(name)
+		if !exists {
+			continue
+		}
+
+		err = fs.Set(name, value)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value %q of %s: %v", value, envName(name), err)
+		}
+	}
+
+	for name, value := range set {
+		fs.Set(name, value)
+	}
+
+	for _, section := range sections {
+		err = config.validate(section)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &config, nil
+}
+
+func (c *Config) addFlags(fs *flag.FlagSet, section Section) {
+	switch section {
+	case Server:
+		fs.StringVar(&c.Server.ListenAddr, "listen_addr", c.Server.ListenAddr, "address the game service listens on")
+		fs.StringVar(&c.Server.MetricsAddr, "metrics_addr", c.Server.MetricsAddr, "address of the HTTP metrics endpoint, empty disables it")
+		fs.StringVar(&c.Server.DataDir, "data_dir", c.Server.DataDir, "directory for snapshots of the games, empty disables persistence")
+		fs.DurationVar(&c.Server.SnapshotInterval, "snapshot_interval", c.Server.SnapshotInterval, "how often snapshots of the games are saved")
+		fs.DurationVar(&c.Server.FinishedTTL, "finished_ttl", c.Server.FinishedTTL, "time a finished session is kept, 0 keeps it forever")
+		fs.DurationVar(&c.Server.IdleTTL, "idle_ttl", c.Server.IdleTTL, "time a session without actions of players is kept, 0 keeps it forever")
+		fs.DurationVar(&c.Server.CleanupInterval, "cleanup_interval", c.Server.CleanupInterval, "how often expired sessions are removed")
+		fs.DurationVar(&c.Server.BotDelay, "bot_delay", c.Server.BotDelay, "the longest pause of a bot before a move")
+		fs.DurationVar(&c.Server.BotFillInterval, "bot_fill_interval", c.Server.BotFillInterval, "how often waiting sessions are checked for seats to give to bots")
+		fs.DurationVar(&c.Server.DrainTimeout, "drain_timeout", c.Server.DrainTimeout, "how long games in progress may finish after SIGTERM, 0 stops without waiting")
+		fs.StringVar(&c.Server.TLSCert, "tls_cert", c.Server.TLSCert, "certificate of the server in PEM, enables TLS")
+		fs.StringVar(&c.Server.TLSKey, "tls_key", c.Server.TLSKey, "private key of the server certificate in PEM")
+		fs.StringVar(&c.Server.TLSClientCA, "tls_client_ca", c.Server.TLSClientCA, "CA bundle of client certificates, enables mutual TLS: players must have certificates issued to their names")
+	case Game:
+		fs.Var(int32Value{&c.Game.Players}, "game_players", "table size of new sessions")
+		fs.Var(rolesValue{&c.Game.Roles}, "game_roles", "roles of new sessions, e.g. mafia=2,detective=1,doctor=1, the rest are civilians")
+		fs.StringVar(&c.Game.KillRule, "game_kill_rule", c.Game.KillRule, "kill rule of new sessions: leader, majority or unanimous")
+		fs.DurationVar(&c.Game.DayDuration, "game_day_duration", c.Game.DayDuration, "day duration of new sessions, 0 means the day lasts until everyone votes")
+		fs.DurationVar(&c.Game.NightDuration, "game_night_duration", c.Game.NightDuration, "night duration of new sessions, 0 means the night lasts until everyone acts")
+		fs.DurationVar(&c.Game.BotFillDelay, "game_bot_fill_delay", c.Game.BotFillDelay, "wait before bots take empty seats of new sessions, 0 means never")
+		fs.DurationVar(&c.Game.ReconnectGrace, "game_reconnect_grace", c.Game.ReconnectGrace, "time a disconnected player has to come back")
+		fs.IntVar(&c.Game.NotificationLimit, "game_notification_limit", c.Game.NotificationLimit, "size of the notification queue of every player and spectator")
+		fs.StringVar(&c.Game.OverflowPolicy, "game_overflow_policy", c.Game.OverflowPolicy, "what happens when the queue is full: drop_oldest or disconnect")
+	case Admin:
+		fs.StringVar(&c.Admin.Addr, "admin_addr", c.Admin.Addr, "address of the admin service, empty disables it on the server")
+		fs.StringVar(&c.Admin.Token, "admin_token", c.Admin.Token, "token of the admin service, empty requires none")
+	case Client:
+		fs.StringVar(&c.Client.ServerAddr, "server_addr", c.Client.ServerAddr, "address of the game server")
+		fs.BoolVar(&c.Client.TLS, "tls", c.Client.TLS, "connect with TLS, it's enabled by the other tls flags too")
+		fs.StringVar(&c.Client.TLSCA, "tls_ca", c.Client.TLSCA, "CA bundle to verify the server, system roots if empty")
+		fs.StringVar(&c.Client.TLSClientCert, "tls_client_cert", c.Client.TLSClientCert, "certificate of the player for mutual TLS")
+		fs.StringVar(&c.Client.TLSClientKey, "tls_client_key", c.Client.TLSClientKey, "private key of the player certificate")
+		fs.StringVar(&c.Client.TLSServerName, "tls_server_name", c.Client.TLSServerName, "name in the server certificate if it differs from the host of the address")
+	case RabbitMQ:
+		fs.StringVar(&c.RabbitMQ.Host, "rabbitmq_host", c.RabbitMQ.Host, "host of RabbitMQ for the chat")
+		fs.IntVar(&c.RabbitMQ.Port, "rabbitmq_port", c.RabbitMQ.Port, "port of RabbitMQ")
+		fs.StringVar(&c.RabbitMQ.User, "rabbitmq_user", c.RabbitMQ.User, "user of RabbitMQ")
+		fs.StringVar(&c.RabbitMQ.Password, "rabbitmq_password", c.RabbitMQ.Password, "password of the RabbitMQ user")
+	}
+}
+
+/*
+	Unknown keys are errors, so a typo in the file doesn't go unnoticed
+*/
+func (c *Config) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	// Decoder adds keys to the map instead of replacing it
+	defaultRoles := c.Game.Roles
+	c.Game.Roles = nil
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err = decoder.Decode(c)
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("Invalid config file %s: %v", path, err)
+	}
+
+	if c.Game.Roles == nil {
+		c.Game.Roles = defaultRoles
+	}
+
+	return nil
+}
+
+func (c *Config) validate(section Section) error {
+	switch section {
+	case Server:
+		if c.Server.ListenAddr == "" {
+			return fmt.Errorf("Listen address is empty")
+		}
+
+		if c.Server.SnapshotInterval <= 0 || c.Server.CleanupInterval <= 0 || c.Server.BotFillInterval <= 0 {
+			return fmt.Errorf("Intervals of snapshots, cleanup and bot fill must be positive")
+		}
+
+		if c.Server.FinishedTTL < 0 || c.Server.IdleTTL < 0 || c.Server.BotDelay < 0 || c.Server.DrainTimeout < 0 {
+			return fmt.Errorf("TTLs, bot delay and drain timeout can't be negative")
+		}
+
+		if (c.Server.TLSCert == "") != (c.Server.TLSKey == "") {
+			return fmt.Errorf("TLS certificate and key of the server must be set together")
+		}
+
+		if c.Server.TLSClientCA != "" && c.Server.TLSCert == "" {
+			return fmt.Errorf("Mutual TLS requires the certificate of the server")
+		}
+	case Game:
+		for role := range c.Game.Roles {
+			if role == "" {
+				return fmt.Errorf("Role without a name")
+			}
+		}
+
+		if _, exists := mafia_grpc.KillRule_value[strings.ToUpper(c.Game.KillRule)]; !exists {
+			return fmt.Errorf("Unknown kill rule: %s", c.Game.KillRule)
+		}
+
+		if c.Game.DayDuration%time.Second != 0 || c.Game.NightDuration%time.Second != 0 || c.Game.BotFillDelay%time.Second != 0 {
+			return fmt.Errorf("Phase durations and bot fill delay must be whole seconds")
+		}
+
+		if c.Game.NotificationLimit < 1 {
+			return fmt.Errorf("Notification limit must be positive")
+		}
+	case Client:
+		if c.Client.ServerAddr == "" {
+			return fmt.Errorf("Server address is empty")
+		}
+
+		if (c.Client.TLSClientCert == "") != (c.Client.TLSClientKey == "") {
+			return fmt.Errorf("TLS certificate and key of the client must be set together")
+		}
+	case RabbitMQ:
+		if c.RabbitMQ.Host == "" {
+			return fmt.Errorf("RabbitMQ host is empty")
+		}
+
+		if c.RabbitMQ.Port < 1 || c.RabbitMQ.Port > 65535 {
+			return fmt.Errorf("Invalid RabbitMQ port: %d", c.RabbitMQ.Port)
+		}
+	}
+
+	return nil
+}
+
+func (c RabbitMQConfig) URL() string {
+	u := url.URL{
+		Scheme: "amqp",
+		User:   url.UserPassword(c.User, c.Password),
+		Host:   net.JoinHostPort(c.Host, strconv.Itoa(c.Port)),
+		Path:   "/",
+	}
+
+	return u.String()
+}
+
+func envName(flagName string) string {
+	return EnvPrefix + strings.ToUpper(flagName)
+}
+
+func lookupEnv(flagName string) (string, bool) {
+	value, exists := os.LookupEnv(envName(flagName))
+	if exists {
+		return value, true
+	}
+
+	legacy, hasLegacy := legacyEnv[flagName]
+	if !hasLegacy {
+		return "", false
+	}
+
+	value, exists = os.LookupEnv(legacy.name)
+	if !exists || value == "" {
+		return "", false
+	}
+
+	return fmt.Sprintf(legacy.format, value), true
+}
+
+func flagNames(fs *flag.FlagSet) map[string]bool {
+	names := make(map[string]bool)
+	fs.VisitAll(func(f *flag.Flag) {
+		names[f.Name] = true
+	})
+
+	return names
+}
+
+type int32Value struct {
+	value *int32
+}
+
+func (v int32Value) String() string {
+	if v.value == nil {
+		return "0"
+	}
+
+	return strconv.Itoa(int(*v.value))
+}
+
+func (v int32Value) Set(s string) error {
+	value, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return err
+	}
+
+	*v.value = int32(value)
+	return nil
+}
+
+/*
+	Roles are written as role=count pairs separated by commas
+*/
+type rolesValue struct {
+	roles *map[string]int32
+}
+
+func (v rolesValue) String() string {
+	if v.roles == nil {
+		return ""
+	}
+
+	pairs := []string{}
+	for role, count := range *v.roles {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", role, count))
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+func (v rolesValue) Set(s string) error {
+	roles := make(map[string]int32)
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		role, count, found := strings.Cut(pair, "=")
+		if !found {
+			return fmt.Errorf("Invalid role: %s", pair)
+		}
+
+		number, err := strconv.ParseInt(strings.TrimSpace(count), 10, 32)
+		if err != nil {
+			return fmt.Errorf("Invalid number of %s: %s", role, count)
+		}
+
+		roles[strings.ToLower(strings.TrimSpace(role))] = int32(number)
+	}
+
+	*v.roles = roles
+	return nil
+}
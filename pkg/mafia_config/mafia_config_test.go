@@ -0,0 +1,132 @@
+package mafia_config
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func load(t *testing.T, args []string, sections ...Section) (*Config, error) {
+	t.Helper()
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return Load(fs, args, sections...)
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(content), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestSourcesOverrideEachOther(t *testing.T) {
+	t.Setenv(ConfigEnv, writeFile(t, `
+server:
+  listen_addr: ":7000"
+  metrics_addr: ":7002"
+  data_dir: "file"
+game:
+  kill_rule: majority
+  roles:
+    mafia: 2
+`))
+	t.Setenv("MAFIA_METRICS_ADDR", ":8002")
+	t.Setenv("MAFIA_DATA_DIR", "env")
+
+	config, err := load(t, []string{"-data_dir", "flag"}, Server, Game)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Server.ListenAddr != ":7000" || config.Server.MetricsAddr != ":8002" || config.Server.DataDir != "flag" {
+		t.Errorf("File, environment and flag must override each other in this order: %+v", config.Server)
+	}
+
+	if config.Server.SnapshotInterval != time.Second || config.Game.ReconnectGrace != time.Minute {
+		t.Errorf("Values that aren't set must keep the defaults: %+v", config)
+	}
+
+	if config.Game.KillRule != "majority" || len(config.Game.Roles) != 1 || config.Game.Roles["mafia"] != 2 {
+		t.Errorf("Roles of the file must replace the default ones: %+v", config.Game)
+	}
+}
+
+func TestInvalidConfigIsRejected(t *testing.T) {
+	t.Setenv(ConfigEnv, "")
+
+	cases := map[string][]string{
+		"unknown kill rule":     {"-game_kill_rule", "random"},
+		"empty queue":           {"-game_notification_limit", "0"},
+		"fractional phase":      {"-game_day_duration", "1500ms"},
+		"key without cert":      {"-tls_key", "key.pem"},
+		"mutual TLS without CA": {"-tls_client_ca", "ca.pem"},
+	}
+
+	for name, args := range cases {
+		_, err := load(t, args, Server, Game)
+		if err == nil {
+			t.Errorf("Config with %s must be rejected", name)
+		}
+	}
+
+	_, err := load(t, []string{"-config", writeFile(t, "server:\n  listen_adr: \":7000\"\n")}, Server)
+	if err == nil || !strings.Contains(err.Error(), "listen_adr") {
+		t.Errorf("Unknown key in the file must be rejected, got %v", err)
+	}
+
+	t.Setenv("MAFIA_SNAPSHOT_INTERVAL", "often")
+	_, err = load(t, nil, Server)
+	if err == nil || !strings.Contains(err.Error(), "MAFIA_SNAPSHOT_INTERVAL") {
+		t.Errorf("Invalid variable must be rejected, got %v", err)
+	}
+}
+
+func TestSectionsAddOnlyTheirFlags(t *testing.T) {
+	t.Setenv(ConfigEnv, "")
+
+	_, err := load(t, []string{"-listen_addr", ":7000"}, Client)
+	if err == nil {
+		t.Error("Client must not accept the flags of the server")
+	}
+
+	config, err := load(t, []string{"-server_addr", "host:1"}, Client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Client.ServerAddr != "host:1" {
+		t.Errorf("Flag of the client must be set, got %s", config.Client.ServerAddr)
+	}
+}
+
+func TestLegacyVariables(t *testing.T) {
+	t.Setenv(ConfigEnv, "")
+	t.Setenv("MAFIA_HOST", "old-host")
+
+	config, err := load(t, nil, Client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Client.ServerAddr != "old-host:9000" {
+		t.Errorf("MAFIA_HOST must set the server address, got %s", config.Client.ServerAddr)
+	}
+
+	t.Setenv("MAFIA_SERVER_ADDR", "new-host:9100")
+	config, err = load(t, nil, Client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Client.ServerAddr != "new-host:9100" {
+		t.Errorf("New variable must win over MAFIA_HOST, got %s", config.Client.ServerAddr)
+	}
+}
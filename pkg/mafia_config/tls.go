@@ -0,0 +1,80 @@
+package mafia_config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+func (c ServerConfig) TLSEnabled() bool {
+	return c.TLSCert != ""
+}
+
+/*
+	With the client CA every client must have a certificate issued by it
+*/
+func (c ServerConfig) TLSConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(c.TLSCert, c.TLSKey)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load the server certificate: %v", err)
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if c.TLSClientCA != "" {
+		config.ClientCAs, err = loadCertPool(c.TLSClientCA)
+		if err != nil {
+			return nil, err
+		}
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return config, nil
+}
+
+func (c ClientConfig) TLSEnabled() bool {
+	return c.TLS || c.TLSCA != "" || c.TLSClientCert != ""
+}
+
+func (c ClientConfig) TLSConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName: c.TLSServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if c.TLSCA != "" {
+		pool, err := loadCertPool(c.TLSCA)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+
+	if c.TLSClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(c.TLSClientCert, c.TLSClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load the client certificate: %v", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("No certificates in %s", path)
+	}
+
+	return pool, nil
+}
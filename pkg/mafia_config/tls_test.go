@@ -0,0 +1,194 @@
+package mafia_config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+/*
+	Issues the certificate signed by the parent, or a self-signed CA without the parent.
+	Certificate and key are written to {name}.pem and {name}-key.pem in the directory
+*/
+func issue(t *testing.T, dir string, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writePEM(t, filepath.Join(dir, name+".pem"), "CERTIFICATE", der)
+	writePEM(t, filepath.Join(dir, name+"-key.pem"), "EC PRIVATE KEY", keyDER)
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert, key
+}
+
+func writePEM(t *testing.T, path string, blockType string, der []byte) {
+	t.Helper()
+
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+/*
+	Makes the TLS handshake between the configs over a local connection
+*/
+func handshake(t *testing.T, server ServerConfig, client ClientConfig) error {
+	t.Helper()
+
+	serverTLS, err := server.TLSConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientTLS, err := client.TLSConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", serverTLS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer conn.Close()
+
+		err = conn.(*tls.Conn).Handshake()
+		if err == nil {
+			_, err = conn.Write([]byte{1})
+		}
+		serverErr <- err
+	}()
+
+	conn, err := tls.Dial("tcp", lis.Addr().String(), clientTLS)
+	if err == nil {
+		// With TLS 1.3 the client learns that its certificate is rejected only on the first read
+		_, err = conn.Read(make([]byte, 1))
+		conn.Close()
+	}
+
+	if err == nil {
+		err = <-serverErr
+	}
+
+	return err
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey := issue(t, dir, "ca", nil, nil)
+	issue(t, dir, "server", ca, caKey)
+	issue(t, dir, "alice", ca, caKey)
+	other, otherKey := issue(t, dir, "other-ca", nil, nil)
+	issue(t, dir, "mallory", other, otherKey)
+
+	path := func(name string) string {
+		return filepath.Join(dir, name)
+	}
+
+	server := ServerConfig{TLSCert: path("server.pem"), TLSKey: path("server-key.pem"), TLSClientCA: path("ca.pem")}
+	alice := ClientConfig{TLSCA: path("ca.pem"), TLSClientCert: path("alice.pem"), TLSClientKey: path("alice-key.pem")}
+	if !server.TLSEnabled() || !alice.TLSEnabled() {
+		t.Fatal("TLS must be enabled by the certificates")
+	}
+
+	err := handshake(t, server, alice)
+	if err != nil {
+		t.Fatalf("Client with the certificate of the CA must connect: %v", err)
+	}
+
+	anonymous := ClientConfig{TLSCA: path("ca.pem")}
+	if handshake(t, server, anonymous) == nil {
+		t.Error("Client without a certificate must not connect with mutual TLS")
+	}
+
+	mallory := ClientConfig{TLSCA: path("ca.pem"), TLSClientCert: path("mallory.pem"), TLSClientKey: path("mallory-key.pem")}
+	if handshake(t, server, mallory) == nil {
+		t.Error("Client with the certificate of another CA must not connect")
+	}
+
+	server.TLSClientCA = ""
+	err = handshake(t, server, anonymous)
+	if err != nil {
+		t.Fatalf("Client without a certificate must connect without mutual TLS: %v", err)
+	}
+
+	if handshake(t, server, ClientConfig{TLSCA: path("other-ca.pem")}) == nil {
+		t.Error("Client must not trust the server of another CA")
+	}
+}
+
+func TestTLSFilesAreChecked(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty.pem")
+	err := os.WriteFile(empty, nil, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ServerConfig{TLSCert: filepath.Join(dir, "missing.pem"), TLSKey: filepath.Join(dir, "missing.pem")}.TLSConfig()
+	if err == nil {
+		t.Error("Missing certificate of the server must be an error")
+	}
+
+	_, err = ClientConfig{TLSCA: empty}.TLSConfig()
+	if err == nil {
+		t.Error("CA bundle without certificates must be an error")
+	}
+
+	if (ClientConfig{}).TLSEnabled() || (ServerConfig{}).TLSEnabled() {
+		t.Error("TLS must be disabled without the settings")
+	}
+}
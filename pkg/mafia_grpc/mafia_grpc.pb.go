@@ -20,6 +20,167 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// Reason of a failed call, it is sent in google.rpc.ErrorInfo of the status
+type ErrorReason int32
+
+const (
+	ErrorReason_UNKNOWN_REASON   ErrorReason = 0
+	ErrorReason_INVALID_ARGUMENT ErrorReason = 1
+	ErrorReason_NO_SESSION       ErrorReason = 2
+	ErrorReason_NO_PLAYER        ErrorReason = 3
+	ErrorReason_PLAYER_EXISTS    ErrorReason = 4
+	ErrorReason_SESSION_FULL     ErrorReason = 5
+	ErrorReason_NOT_STARTED      ErrorReason = 6
+	ErrorReason_GAME_FINISHED    ErrorReason = 7
+	ErrorReason_WRONG_PHASE      ErrorReason = 8
+	ErrorReason_NOT_YOUR_TURN    ErrorReason = 9
+	ErrorReason_NOT_AUTHORIZED   ErrorReason = 10
+	ErrorReason_INVALID_TOKEN    ErrorReason = 11
+	ErrorReason_PLAYER_DEAD      ErrorReason = 12
+	ErrorReason_TARGET_NOT_FOUND ErrorReason = 13
+	ErrorReason_TARGET_DEAD      ErrorReason = 14
+	ErrorReason_RULE_VIOLATION   ErrorReason = 15
+	ErrorReason_GAME_CLOSED      ErrorReason = 16
+	ErrorReason_PLAYER_LEFT      ErrorReason = 17
+	ErrorReason_SHUTTING_DOWN    ErrorReason = 18
+)
+
+var ErrorReason_name = map[int32]string{
+	0:  "UNKNOWN_REASON",
+	1:  "INVALID_ARGUMENT",
+	2:  "NO_SESSION",
+	3:  "NO_PLAYER",
+	4:  "PLAYER_EXISTS",
+	5:  "SESSION_FULL",
+	6:  "NOT_STARTED",
+	7:  "GAME_FINISHED",
+	8:  "WRONG_PHASE",
+	9:  "NOT_YOUR_TURN",
+	10: "NOT_AUTHORIZED",
+	11: "INVALID_TOKEN",
+	12: "PLAYER_DEAD",
+	13: "TARGET_NOT_FOUND",
+	14: "TARGET_DEAD",
+	15: "RULE_VIOLATION",
+	16: "GAME_CLOSED",
+	17: "PLAYER_LEFT",
+	18: "SHUTTING_DOWN",
+}
+
+var ErrorReason_value = map[string]int32{
+	"UNKNOWN_REASON":   0,
+	"INVALID_ARGUMENT": 1,
+	"NO_SESSION":       2,
+	"NO_PLAYER":        3,
+	"PLAYER_EXISTS":    4,
+	"SESSION_FULL":     5,
+	"NOT_STARTED":      6,
+	"GAME_FINISHED":    7,
+	"WRONG_PHASE":      8,
+	"NOT_YOUR_TURN":    9,
+	"NOT_AUTHORIZED":   10,
+	"INVALID_TOKEN":    11,
+	"PLAYER_DEAD":      12,
+	"TARGET_NOT_FOUND": 13,
+	"TARGET_DEAD":      14,
+	"RULE_VIOLATION":   15,
+	"GAME_CLOSED":      16,
+	"PLAYER_LEFT":      17,
+	"SHUTTING_DOWN":    18,
+}
+
+func (x ErrorReason) String() string {
+	return proto.EnumName(ErrorReason_name, int32(x))
+}
+
+func (ErrorReason) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{0}
+}
+
+type KillRule int32
+
+const (
+	KillRule_LEADER    KillRule = 0
+	KillRule_MAJORITY  KillRule = 1
+	KillRule_UNANIMOUS KillRule = 2
+)
+
+var KillRule_name = map[int32]string{
+	0: "LEADER",
+	1: "MAJORITY",
+	2: "UNANIMOUS",
+}
+
+var KillRule_value = map[string]int32{
+	"LEADER":    0,
+	"MAJORITY":  1,
+	"UNANIMOUS": 2,
+}
+
+func (x KillRule) String() string {
+	return proto.EnumName(KillRule_name, int32(x))
+}
+
+func (KillRule) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{1}
+}
+
+type RunoffFallback int32
+
+const (
+	RunoffFallback_NO_KILL  RunoffFallback = 0
+	RunoffFallback_RANDOM   RunoffFallback = 1
+	RunoffFallback_ALL_TIED RunoffFallback = 2
+)
+
+var RunoffFallback_name = map[int32]string{
+	0: "NO_KILL",
+	1: "RANDOM",
+	2: "ALL_TIED",
+}
+
+var RunoffFallback_value = map[string]int32{
+	"NO_KILL":  0,
+	"RANDOM":   1,
+	"ALL_TIED": 2,
+}
+
+func (x RunoffFallback) String() string {
+	return proto.EnumName(RunoffFallback_name, int32(x))
+}
+
+func (RunoffFallback) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{2}
+}
+
+type SessionStatus int32
+
+const (
+	SessionStatus_WAITING  SessionStatus = 0
+	SessionStatus_STARTED  SessionStatus = 1
+	SessionStatus_FINISHED SessionStatus = 2
+)
+
+var SessionStatus_name = map[int32]string{
+	0: "WAITING",
+	1: "STARTED",
+	2: "FINISHED",
+}
+
+var SessionStatus_value = map[string]int32{
+	"WAITING":  0,
+	"STARTED":  1,
+	"FINISHED": 2,
+}
+
+func (x SessionStatus) String() string {
+	return proto.EnumName(SessionStatus_name, int32(x))
+}
+
+func (SessionStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{3}
+}
+
 type NotificationType int32
 
 const (
@@ -27,6 +188,12 @@ const (
 	NotificationType_FINISH    NotificationType = 1
 	NotificationType_NEW_DAY   NotificationType = 2
 	NotificationType_NEW_NIGHT NotificationType = 3
+	NotificationType_RUNOFF    NotificationType = 4
+	NotificationType_VOTE_CAST NotificationType = 5
+	NotificationType_STATE     NotificationType = 6
+	NotificationType_REMATCH   NotificationType = 7
+	NotificationType_NOTICE    NotificationType = 8
+	NotificationType_SHUTDOWN  NotificationType = 9
 )
 
 var NotificationType_name = map[int32]string{
@@ -34,6 +201,12 @@ var NotificationType_name = map[int32]string{
 	1: "FINISH",
 	2: "NEW_DAY",
 	3: "NEW_NIGHT",
+	4: "RUNOFF",
+	5: "VOTE_CAST",
+	6: "STATE",
+	7: "REMATCH",
+	8: "NOTICE",
+	9: "SHUTDOWN",
 }
 
 var NotificationType_value = map[string]int32{
@@ -41,6 +214,12 @@ var NotificationType_value = map[string]int32{
 	"FINISH":    1,
 	"NEW_DAY":   2,
 	"NEW_NIGHT": 3,
+	"RUNOFF":    4,
+	"VOTE_CAST": 5,
+	"STATE":     6,
+	"REMATCH":   7,
+	"NOTICE":    8,
+	"SHUTDOWN":  9,
 }
 
 func (x NotificationType) String() string {
@@ -48,7 +227,7 @@ func (x NotificationType) String() string {
 }
 
 func (NotificationType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_fa11038ec5e9ab77, []int{0}
+	return fileDescriptor_fa11038ec5e9ab77, []int{4}
 }
 
 type Response struct {
@@ -83,171 +262,1267 @@ func (m *Response) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_Response proto.InternalMessageInfo
 
-func (m *Response) GetOk() bool {
+func (m *Response) GetOk() bool {
+	if m != nil {
+		return m.Ok
+	}
+	return false
+}
+
+type JoinResponse struct {
+	Ok                   bool     `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	Token                string   `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	GameId               string   `protobuf:"bytes,3,opt,name=gameId,proto3" json:"gameId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JoinResponse) Reset()         { *m = JoinResponse{} }
+func (m *JoinResponse) String() string { return proto.CompactTextString(m) }
+func (*JoinResponse) ProtoMessage()    {}
+func (*JoinResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{1}
+}
+
+func (m *JoinResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JoinResponse.Unmarshal(m, b)
+}
+func (m *JoinResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JoinResponse.Marshal(b, m, deterministic)
+}
+func (m *JoinResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JoinResponse.Merge(m, src)
+}
+func (m *JoinResponse) XXX_Size() int {
+	return xxx_messageInfo_JoinResponse.Size(m)
+}
+func (m *JoinResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_JoinResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JoinResponse proto.InternalMessageInfo
+
+func (m *JoinResponse) GetOk() bool {
+	if m != nil {
+		return m.Ok
+	}
+	return false
+}
+
+func (m *JoinResponse) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *JoinResponse) GetGameId() string {
+	if m != nil {
+		return m.GameId
+	}
+	return ""
+}
+
+type CheckMafiaResponse struct {
+	IsMafia              bool     `protobuf:"varint,1,opt,name=isMafia,proto3" json:"isMafia,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckMafiaResponse) Reset()         { *m = CheckMafiaResponse{} }
+func (m *CheckMafiaResponse) String() string { return proto.CompactTextString(m) }
+func (*CheckMafiaResponse) ProtoMessage()    {}
+func (*CheckMafiaResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{2}
+}
+
+func (m *CheckMafiaResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckMafiaResponse.Unmarshal(m, b)
+}
+func (m *CheckMafiaResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckMafiaResponse.Marshal(b, m, deterministic)
+}
+func (m *CheckMafiaResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckMafiaResponse.Merge(m, src)
+}
+func (m *CheckMafiaResponse) XXX_Size() int {
+	return xxx_messageInfo_CheckMafiaResponse.Size(m)
+}
+func (m *CheckMafiaResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckMafiaResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckMafiaResponse proto.InternalMessageInfo
+
+func (m *CheckMafiaResponse) GetIsMafia() bool {
+	if m != nil {
+		return m.IsMafia
+	}
+	return false
+}
+
+type ChatResponse struct {
+	CanChat              bool     `protobuf:"varint,1,opt,name=canChat,proto3" json:"canChat,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChatResponse) Reset()         { *m = ChatResponse{} }
+func (m *ChatResponse) String() string { return proto.CompactTextString(m) }
+func (*ChatResponse) ProtoMessage()    {}
+func (*ChatResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{3}
+}
+
+func (m *ChatResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChatResponse.Unmarshal(m, b)
+}
+func (m *ChatResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChatResponse.Marshal(b, m, deterministic)
+}
+func (m *ChatResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChatResponse.Merge(m, src)
+}
+func (m *ChatResponse) XXX_Size() int {
+	return xxx_messageInfo_ChatResponse.Size(m)
+}
+func (m *ChatResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChatResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChatResponse proto.InternalMessageInfo
+
+func (m *ChatResponse) GetCanChat() bool {
+	if m != nil {
+		return m.CanChat
+	}
+	return false
+}
+
+type PlayerInfo struct {
+	Session              string   `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PlayerInfo) Reset()         { *m = PlayerInfo{} }
+func (m *PlayerInfo) String() string { return proto.CompactTextString(m) }
+func (*PlayerInfo) ProtoMessage()    {}
+func (*PlayerInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{4}
+}
+
+func (m *PlayerInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PlayerInfo.Unmarshal(m, b)
+}
+func (m *PlayerInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PlayerInfo.Marshal(b, m, deterministic)
+}
+func (m *PlayerInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PlayerInfo.Merge(m, src)
+}
+func (m *PlayerInfo) XXX_Size() int {
+	return xxx_messageInfo_PlayerInfo.Size(m)
+}
+func (m *PlayerInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_PlayerInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PlayerInfo proto.InternalMessageInfo
+
+func (m *PlayerInfo) GetSession() string {
+	if m != nil {
+		return m.Session
+	}
+	return ""
+}
+
+func (m *PlayerInfo) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type SessionConfig struct {
+	Players              int32            `protobuf:"varint,1,opt,name=players,proto3" json:"players,omitempty"`
+	Roles                map[string]int32 `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	KillRule             KillRule         `protobuf:"varint,3,opt,name=killRule,proto3,enum=mafia_grpc.KillRule" json:"killRule,omitempty"`
+	DoctorSelfHeal       bool             `protobuf:"varint,4,opt,name=doctorSelfHeal,proto3" json:"doctorSelfHeal,omitempty"`
+	DoctorRepeatHeal     bool             `protobuf:"varint,5,opt,name=doctorRepeatHeal,proto3" json:"doctorRepeatHeal,omitempty"`
+	DayDuration          int32            `protobuf:"varint,6,opt,name=dayDuration,proto3" json:"dayDuration,omitempty"`
+	NightDuration        int32            `protobuf:"varint,7,opt,name=nightDuration,proto3" json:"nightDuration,omitempty"`
+	Runoff               bool             `protobuf:"varint,8,opt,name=runoff,proto3" json:"runoff,omitempty"`
+	RunoffFallback       RunoffFallback   `protobuf:"varint,9,opt,name=runoffFallback,proto3,enum=mafia_grpc.RunoffFallback" json:"runoffFallback,omitempty"`
+	OpenBallot           bool             `protobuf:"varint,10,opt,name=openBallot,proto3" json:"openBallot,omitempty"`
+	Seed                 int64            `protobuf:"varint,11,opt,name=seed,proto3" json:"seed,omitempty"`
+	OmniscientSpectators bool             `protobuf:"varint,12,opt,name=omniscientSpectators,proto3" json:"omniscientSpectators,omitempty"`
+	BotFillDelay         int32            `protobuf:"varint,13,opt,name=botFillDelay,proto3" json:"botFillDelay,omitempty"`
+	HasKillRule          bool             `protobuf:"varint,14,opt,name=hasKillRule,proto3" json:"hasKillRule,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *SessionConfig) Reset()         { *m = SessionConfig{} }
+func (m *SessionConfig) String() string { return proto.CompactTextString(m) }
+func (*SessionConfig) ProtoMessage()    {}
+func (*SessionConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{5}
+}
+
+func (m *SessionConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SessionConfig.Unmarshal(m, b)
+}
+func (m *SessionConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SessionConfig.Marshal(b, m, deterministic)
+}
+func (m *SessionConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionConfig.Merge(m, src)
+}
+func (m *SessionConfig) XXX_Size() int {
+	return xxx_messageInfo_SessionConfig.Size(m)
+}
+func (m *SessionConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SessionConfig proto.InternalMessageInfo
+
+func (m *SessionConfig) GetPlayers() int32 {
+	if m != nil {
+		return m.Players
+	}
+	return 0
+}
+
+func (m *SessionConfig) GetRoles() map[string]int32 {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
+func (m *SessionConfig) GetKillRule() KillRule {
+	if m != nil {
+		return m.KillRule
+	}
+	return KillRule_LEADER
+}
+
+func (m *SessionConfig) GetDoctorSelfHeal() bool {
+	if m != nil {
+		return m.DoctorSelfHeal
+	}
+	return false
+}
+
+func (m *SessionConfig) GetDoctorRepeatHeal() bool {
+	if m != nil {
+		return m.DoctorRepeatHeal
+	}
+	return false
+}
+
+func (m *SessionConfig) GetDayDuration() int32 {
+	if m != nil {
+		return m.DayDuration
+	}
+	return 0
+}
+
+func (m *SessionConfig) GetNightDuration() int32 {
+	if m != nil {
+		return m.NightDuration
+	}
+	return 0
+}
+
+func (m *SessionConfig) GetRunoff() bool {
+	if m != nil {
+		return m.Runoff
+	}
+	return false
+}
+
+func (m *SessionConfig) GetRunoffFallback() RunoffFallback {
+	if m != nil {
+		return m.RunoffFallback
+	}
+	return RunoffFallback_NO_KILL
+}
+
+func (m *SessionConfig) GetOpenBallot() bool {
+	if m != nil {
+		return m.OpenBallot
+	}
+	return false
+}
+
+func (m *SessionConfig) GetSeed() int64 {
+	if m != nil {
+		return m.Seed
+	}
+	return 0
+}
+
+func (m *SessionConfig) GetOmniscientSpectators() bool {
+	if m != nil {
+		return m.OmniscientSpectators
+	}
+	return false
+}
+
+func (m *SessionConfig) GetBotFillDelay() int32 {
+	if m != nil {
+		return m.BotFillDelay
+	}
+	return 0
+}
+
+func (m *SessionConfig) GetHasKillRule() bool {
+	if m != nil {
+		return m.HasKillRule
+	}
+	return false
+}
+
+type JoinRequest struct {
+	Player               *PlayerInfo    `protobuf:"bytes,1,opt,name=player,proto3" json:"player,omitempty"`
+	Config               *SessionConfig `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	Password             string         `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *JoinRequest) Reset()         { *m = JoinRequest{} }
+func (m *JoinRequest) String() string { return proto.CompactTextString(m) }
+func (*JoinRequest) ProtoMessage()    {}
+func (*JoinRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{6}
+}
+
+func (m *JoinRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JoinRequest.Unmarshal(m, b)
+}
+func (m *JoinRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JoinRequest.Marshal(b, m, deterministic)
+}
+func (m *JoinRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JoinRequest.Merge(m, src)
+}
+func (m *JoinRequest) XXX_Size() int {
+	return xxx_messageInfo_JoinRequest.Size(m)
+}
+func (m *JoinRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_JoinRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JoinRequest proto.InternalMessageInfo
+
+func (m *JoinRequest) GetPlayer() *PlayerInfo {
+	if m != nil {
+		return m.Player
+	}
+	return nil
+}
+
+func (m *JoinRequest) GetConfig() *SessionConfig {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+func (m *JoinRequest) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+type AddBotsRequest struct {
+	Player               *PlayerInfo `protobuf:"bytes,1,opt,name=player,proto3" json:"player,omitempty"`
+	Count                int32       `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *AddBotsRequest) Reset()         { *m = AddBotsRequest{} }
+func (m *AddBotsRequest) String() string { return proto.CompactTextString(m) }
+func (*AddBotsRequest) ProtoMessage()    {}
+func (*AddBotsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{7}
+}
+
+func (m *AddBotsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddBotsRequest.Unmarshal(m, b)
+}
+func (m *AddBotsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddBotsRequest.Marshal(b, m, deterministic)
+}
+func (m *AddBotsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddBotsRequest.Merge(m, src)
+}
+func (m *AddBotsRequest) XXX_Size() int {
+	return xxx_messageInfo_AddBotsRequest.Size(m)
+}
+func (m *AddBotsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddBotsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddBotsRequest proto.InternalMessageInfo
+
+func (m *AddBotsRequest) GetPlayer() *PlayerInfo {
+	if m != nil {
+		return m.Player
+	}
+	return nil
+}
+
+func (m *AddBotsRequest) GetCount() int32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type SpectateRequest struct {
+	Player               *PlayerInfo `protobuf:"bytes,1,opt,name=player,proto3" json:"player,omitempty"`
+	Omniscient           bool        `protobuf:"varint,2,opt,name=omniscient,proto3" json:"omniscient,omitempty"`
+	Password             string      `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *SpectateRequest) Reset()         { *m = SpectateRequest{} }
+func (m *SpectateRequest) String() string { return proto.CompactTextString(m) }
+func (*SpectateRequest) ProtoMessage()    {}
+func (*SpectateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{8}
+}
+
+func (m *SpectateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SpectateRequest.Unmarshal(m, b)
+}
+func (m *SpectateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SpectateRequest.Marshal(b, m, deterministic)
+}
+func (m *SpectateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SpectateRequest.Merge(m, src)
+}
+func (m *SpectateRequest) XXX_Size() int {
+	return xxx_messageInfo_SpectateRequest.Size(m)
+}
+func (m *SpectateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SpectateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SpectateRequest proto.InternalMessageInfo
+
+func (m *SpectateRequest) GetPlayer() *PlayerInfo {
+	if m != nil {
+		return m.Player
+	}
+	return nil
+}
+
+func (m *SpectateRequest) GetOmniscient() bool {
+	if m != nil {
+		return m.Omniscient
+	}
+	return false
+}
+
+func (m *SpectateRequest) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+type ListSessionsRequest struct {
+	Statuses             []SessionStatus `protobuf:"varint,1,rep,packed,name=statuses,proto3,enum=mafia_grpc.SessionStatus" json:"statuses,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ListSessionsRequest) Reset()         { *m = ListSessionsRequest{} }
+func (m *ListSessionsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListSessionsRequest) ProtoMessage()    {}
+func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{9}
+}
+
+func (m *ListSessionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSessionsRequest.Unmarshal(m, b)
+}
+func (m *ListSessionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSessionsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListSessionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSessionsRequest.Merge(m, src)
+}
+func (m *ListSessionsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListSessionsRequest.Size(m)
+}
+func (m *ListSessionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSessionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSessionsRequest proto.InternalMessageInfo
+
+func (m *ListSessionsRequest) GetStatuses() []SessionStatus {
+	if m != nil {
+		return m.Statuses
+	}
+	return nil
+}
+
+type SessionRequest struct {
+	Session              string   `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SessionRequest) Reset()         { *m = SessionRequest{} }
+func (m *SessionRequest) String() string { return proto.CompactTextString(m) }
+func (*SessionRequest) ProtoMessage()    {}
+func (*SessionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{10}
+}
+
+func (m *SessionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SessionRequest.Unmarshal(m, b)
+}
+func (m *SessionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SessionRequest.Marshal(b, m, deterministic)
+}
+func (m *SessionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionRequest.Merge(m, src)
+}
+func (m *SessionRequest) XXX_Size() int {
+	return xxx_messageInfo_SessionRequest.Size(m)
+}
+func (m *SessionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SessionRequest proto.InternalMessageInfo
+
+func (m *SessionRequest) GetSession() string {
+	if m != nil {
+		return m.Session
+	}
+	return ""
+}
+
+type SessionInfo struct {
+	Session              string         `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	GameId               string         `protobuf:"bytes,2,opt,name=gameId,proto3" json:"gameId,omitempty"`
+	Players              int32          `protobuf:"varint,3,opt,name=players,proto3" json:"players,omitempty"`
+	Capacity             int32          `protobuf:"varint,4,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	Status               SessionStatus  `protobuf:"varint,5,opt,name=status,proto3,enum=mafia_grpc.SessionStatus" json:"status,omitempty"`
+	Config               *SessionConfig `protobuf:"bytes,6,opt,name=config,proto3" json:"config,omitempty"`
+	Private              bool           `protobuf:"varint,7,opt,name=private,proto3" json:"private,omitempty"`
+	Spectators           int32          `protobuf:"varint,8,opt,name=spectators,proto3" json:"spectators,omitempty"`
+	Bots                 int32          `protobuf:"varint,9,opt,name=bots,proto3" json:"bots,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *SessionInfo) Reset()         { *m = SessionInfo{} }
+func (m *SessionInfo) String() string { return proto.CompactTextString(m) }
+func (*SessionInfo) ProtoMessage()    {}
+func (*SessionInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{11}
+}
+
+func (m *SessionInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SessionInfo.Unmarshal(m, b)
+}
+func (m *SessionInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SessionInfo.Marshal(b, m, deterministic)
+}
+func (m *SessionInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionInfo.Merge(m, src)
+}
+func (m *SessionInfo) XXX_Size() int {
+	return xxx_messageInfo_SessionInfo.Size(m)
+}
+func (m *SessionInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SessionInfo proto.InternalMessageInfo
+
+func (m *SessionInfo) GetSession() string {
+	if m != nil {
+		return m.Session
+	}
+	return ""
+}
+
+func (m *SessionInfo) GetGameId() string {
+	if m != nil {
+		return m.GameId
+	}
+	return ""
+}
+
+func (m *SessionInfo) GetPlayers() int32 {
+	if m != nil {
+		return m.Players
+	}
+	return 0
+}
+
+func (m *SessionInfo) GetCapacity() int32 {
+	if m != nil {
+		return m.Capacity
+	}
+	return 0
+}
+
+func (m *SessionInfo) GetStatus() SessionStatus {
+	if m != nil {
+		return m.Status
+	}
+	return SessionStatus_WAITING
+}
+
+func (m *SessionInfo) GetConfig() *SessionConfig {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+func (m *SessionInfo) GetPrivate() bool {
+	if m != nil {
+		return m.Private
+	}
+	return false
+}
+
+func (m *SessionInfo) GetSpectators() int32 {
+	if m != nil {
+		return m.Spectators
+	}
+	return 0
+}
+
+func (m *SessionInfo) GetBots() int32 {
+	if m != nil {
+		return m.Bots
+	}
+	return 0
+}
+
+type SessionList struct {
+	Sessions             []*SessionInfo `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *SessionList) Reset()         { *m = SessionList{} }
+func (m *SessionList) String() string { return proto.CompactTextString(m) }
+func (*SessionList) ProtoMessage()    {}
+func (*SessionList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{12}
+}
+
+func (m *SessionList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SessionList.Unmarshal(m, b)
+}
+func (m *SessionList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SessionList.Marshal(b, m, deterministic)
+}
+func (m *SessionList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionList.Merge(m, src)
+}
+func (m *SessionList) XXX_Size() int {
+	return xxx_messageInfo_SessionList.Size(m)
+}
+func (m *SessionList) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SessionList proto.InternalMessageInfo
+
+func (m *SessionList) GetSessions() []*SessionInfo {
+	if m != nil {
+		return m.Sessions
+	}
+	return nil
+}
+
+type AdminGame struct {
+	Info                 *SessionInfo `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
+	Details              string       `protobuf:"bytes,2,opt,name=details,proto3" json:"details,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *AdminGame) Reset()         { *m = AdminGame{} }
+func (m *AdminGame) String() string { return proto.CompactTextString(m) }
+func (*AdminGame) ProtoMessage()    {}
+func (*AdminGame) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{13}
+}
+
+func (m *AdminGame) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AdminGame.Unmarshal(m, b)
+}
+func (m *AdminGame) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AdminGame.Marshal(b, m, deterministic)
+}
+func (m *AdminGame) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AdminGame.Merge(m, src)
+}
+func (m *AdminGame) XXX_Size() int {
+	return xxx_messageInfo_AdminGame.Size(m)
+}
+func (m *AdminGame) XXX_DiscardUnknown() {
+	xxx_messageInfo_AdminGame.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AdminGame proto.InternalMessageInfo
+
+func (m *AdminGame) GetInfo() *SessionInfo {
+	if m != nil {
+		return m.Info
+	}
+	return nil
+}
+
+func (m *AdminGame) GetDetails() string {
+	if m != nil {
+		return m.Details
+	}
+	return ""
+}
+
+type AdminGameList struct {
+	Games                []*AdminGame `protobuf:"bytes,1,rep,name=games,proto3" json:"games,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *AdminGameList) Reset()         { *m = AdminGameList{} }
+func (m *AdminGameList) String() string { return proto.CompactTextString(m) }
+func (*AdminGameList) ProtoMessage()    {}
+func (*AdminGameList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{14}
+}
+
+func (m *AdminGameList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AdminGameList.Unmarshal(m, b)
+}
+func (m *AdminGameList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AdminGameList.Marshal(b, m, deterministic)
+}
+func (m *AdminGameList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AdminGameList.Merge(m, src)
+}
+func (m *AdminGameList) XXX_Size() int {
+	return xxx_messageInfo_AdminGameList.Size(m)
+}
+func (m *AdminGameList) XXX_DiscardUnknown() {
+	xxx_messageInfo_AdminGameList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AdminGameList proto.InternalMessageInfo
+
+func (m *AdminGameList) GetGames() []*AdminGame {
+	if m != nil {
+		return m.Games
+	}
+	return nil
+}
+
+type AdminSessionRequest struct {
+	Session              string   `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	Reason               string   `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AdminSessionRequest) Reset()         { *m = AdminSessionRequest{} }
+func (m *AdminSessionRequest) String() string { return proto.CompactTextString(m) }
+func (*AdminSessionRequest) ProtoMessage()    {}
+func (*AdminSessionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{15}
+}
+
+func (m *AdminSessionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AdminSessionRequest.Unmarshal(m, b)
+}
+func (m *AdminSessionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AdminSessionRequest.Marshal(b, m, deterministic)
+}
+func (m *AdminSessionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AdminSessionRequest.Merge(m, src)
+}
+func (m *AdminSessionRequest) XXX_Size() int {
+	return xxx_messageInfo_AdminSessionRequest.Size(m)
+}
+func (m *AdminSessionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AdminSessionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AdminSessionRequest proto.InternalMessageInfo
+
+func (m *AdminSessionRequest) GetSession() string {
+	if m != nil {
+		return m.Session
+	}
+	return ""
+}
+
+func (m *AdminSessionRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type KickRequest struct {
+	Session              string   `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	Player               string   `protobuf:"bytes,2,opt,name=player,proto3" json:"player,omitempty"`
+	Reason               string   `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KickRequest) Reset()         { *m = KickRequest{} }
+func (m *KickRequest) String() string { return proto.CompactTextString(m) }
+func (*KickRequest) ProtoMessage()    {}
+func (*KickRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{16}
+}
+
+func (m *KickRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KickRequest.Unmarshal(m, b)
+}
+func (m *KickRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KickRequest.Marshal(b, m, deterministic)
+}
+func (m *KickRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KickRequest.Merge(m, src)
+}
+func (m *KickRequest) XXX_Size() int {
+	return xxx_messageInfo_KickRequest.Size(m)
+}
+func (m *KickRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_KickRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KickRequest proto.InternalMessageInfo
+
+func (m *KickRequest) GetSession() string {
+	if m != nil {
+		return m.Session
+	}
+	return ""
+}
+
+func (m *KickRequest) GetPlayer() string {
+	if m != nil {
+		return m.Player
+	}
+	return ""
+}
+
+func (m *KickRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type NoticeRequest struct {
+	Session              string   `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	Text                 string   `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NoticeRequest) Reset()         { *m = NoticeRequest{} }
+func (m *NoticeRequest) String() string { return proto.CompactTextString(m) }
+func (*NoticeRequest) ProtoMessage()    {}
+func (*NoticeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{17}
+}
+
+func (m *NoticeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NoticeRequest.Unmarshal(m, b)
+}
+func (m *NoticeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NoticeRequest.Marshal(b, m, deterministic)
+}
+func (m *NoticeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NoticeRequest.Merge(m, src)
+}
+func (m *NoticeRequest) XXX_Size() int {
+	return xxx_messageInfo_NoticeRequest.Size(m)
+}
+func (m *NoticeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NoticeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NoticeRequest proto.InternalMessageInfo
+
+func (m *NoticeRequest) GetSession() string {
+	if m != nil {
+		return m.Session
+	}
+	return ""
+}
+
+func (m *NoticeRequest) GetText() string {
+	if m != nil {
+		return m.Text
+	}
+	return ""
+}
+
+type BroadcastResponse struct {
+	Sessions             int32    `protobuf:"varint,1,opt,name=sessions,proto3" json:"sessions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BroadcastResponse) Reset()         { *m = BroadcastResponse{} }
+func (m *BroadcastResponse) String() string { return proto.CompactTextString(m) }
+func (*BroadcastResponse) ProtoMessage()    {}
+func (*BroadcastResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{18}
+}
+
+func (m *BroadcastResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BroadcastResponse.Unmarshal(m, b)
+}
+func (m *BroadcastResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BroadcastResponse.Marshal(b, m, deterministic)
+}
+func (m *BroadcastResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BroadcastResponse.Merge(m, src)
+}
+func (m *BroadcastResponse) XXX_Size() int {
+	return xxx_messageInfo_BroadcastResponse.Size(m)
+}
+func (m *BroadcastResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BroadcastResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BroadcastResponse proto.InternalMessageInfo
+
+func (m *BroadcastResponse) GetSessions() int32 {
+	if m != nil {
+		return m.Sessions
+	}
+	return 0
+}
+
+type PlayerStatsRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PlayerStatsRequest) Reset()         { *m = PlayerStatsRequest{} }
+func (m *PlayerStatsRequest) String() string { return proto.CompactTextString(m) }
+func (*PlayerStatsRequest) ProtoMessage()    {}
+func (*PlayerStatsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{19}
+}
+
+func (m *PlayerStatsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PlayerStatsRequest.Unmarshal(m, b)
+}
+func (m *PlayerStatsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PlayerStatsRequest.Marshal(b, m, deterministic)
+}
+func (m *PlayerStatsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PlayerStatsRequest.Merge(m, src)
+}
+func (m *PlayerStatsRequest) XXX_Size() int {
+	return xxx_messageInfo_PlayerStatsRequest.Size(m)
+}
+func (m *PlayerStatsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PlayerStatsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PlayerStatsRequest proto.InternalMessageInfo
+
+func (m *PlayerStatsRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// Statistics of a player over all finished games, bots have none.
+// Statistics are kept by name: without mutual TLS anyone can play under the name
+type PlayerStats struct {
+	Name                 string           `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Rank                 int32            `protobuf:"varint,2,opt,name=rank,proto3" json:"rank,omitempty"`
+	Rating               float64          `protobuf:"fixed64,3,opt,name=rating,proto3" json:"rating,omitempty"`
+	Games                int32            `protobuf:"varint,4,opt,name=games,proto3" json:"games,omitempty"`
+	Wins                 int32            `protobuf:"varint,5,opt,name=wins,proto3" json:"wins,omitempty"`
+	GamesByRole          map[string]int32 `protobuf:"bytes,6,rep,name=gamesByRole,proto3" json:"gamesByRole,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	WinsByRole           map[string]int32 `protobuf:"bytes,7,rep,name=winsByRole,proto3" json:"winsByRole,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	Survived             int32            `protobuf:"varint,8,opt,name=survived,proto3" json:"survived,omitempty"`
+	Checks               int32            `protobuf:"varint,9,opt,name=checks,proto3" json:"checks,omitempty"`
+	MafiaFound           int32            `protobuf:"varint,10,opt,name=mafiaFound,proto3" json:"mafiaFound,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *PlayerStats) Reset()         { *m = PlayerStats{} }
+func (m *PlayerStats) String() string { return proto.CompactTextString(m) }
+func (*PlayerStats) ProtoMessage()    {}
+func (*PlayerStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{20}
+}
+
+func (m *PlayerStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PlayerStats.Unmarshal(m, b)
+}
+func (m *PlayerStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PlayerStats.Marshal(b, m, deterministic)
+}
+func (m *PlayerStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PlayerStats.Merge(m, src)
+}
+func (m *PlayerStats) XXX_Size() int {
+	return xxx_messageInfo_PlayerStats.Size(m)
+}
+func (m *PlayerStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_PlayerStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PlayerStats proto.InternalMessageInfo
+
+func (m *PlayerStats) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PlayerStats) GetRank() int32 {
 	if m != nil {
-		return m.Ok
+		return m.Rank
 	}
-	return false
+	return 0
 }
 
-type CheckMafiaResponse struct {
-	IsMafia              bool     `protobuf:"varint,1,opt,name=isMafia,proto3" json:"isMafia,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *PlayerStats) GetRating() float64 {
+	if m != nil {
+		return m.Rating
+	}
+	return 0
 }
 
-func (m *CheckMafiaResponse) Reset()         { *m = CheckMafiaResponse{} }
-func (m *CheckMafiaResponse) String() string { return proto.CompactTextString(m) }
-func (*CheckMafiaResponse) ProtoMessage()    {}
-func (*CheckMafiaResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_fa11038ec5e9ab77, []int{1}
+func (m *PlayerStats) GetGames() int32 {
+	if m != nil {
+		return m.Games
+	}
+	return 0
 }
 
-func (m *CheckMafiaResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CheckMafiaResponse.Unmarshal(m, b)
-}
-func (m *CheckMafiaResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CheckMafiaResponse.Marshal(b, m, deterministic)
+func (m *PlayerStats) GetWins() int32 {
+	if m != nil {
+		return m.Wins
+	}
+	return 0
 }
-func (m *CheckMafiaResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CheckMafiaResponse.Merge(m, src)
+
+func (m *PlayerStats) GetGamesByRole() map[string]int32 {
+	if m != nil {
+		return m.GamesByRole
+	}
+	return nil
 }
-func (m *CheckMafiaResponse) XXX_Size() int {
-	return xxx_messageInfo_CheckMafiaResponse.Size(m)
+
+func (m *PlayerStats) GetWinsByRole() map[string]int32 {
+	if m != nil {
+		return m.WinsByRole
+	}
+	return nil
 }
-func (m *CheckMafiaResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_CheckMafiaResponse.DiscardUnknown(m)
+
+func (m *PlayerStats) GetSurvived() int32 {
+	if m != nil {
+		return m.Survived
+	}
+	return 0
 }
 
-var xxx_messageInfo_CheckMafiaResponse proto.InternalMessageInfo
+func (m *PlayerStats) GetChecks() int32 {
+	if m != nil {
+		return m.Checks
+	}
+	return 0
+}
 
-func (m *CheckMafiaResponse) GetIsMafia() bool {
+func (m *PlayerStats) GetMafiaFound() int32 {
 	if m != nil {
-		return m.IsMafia
+		return m.MafiaFound
 	}
-	return false
+	return 0
 }
 
-type ChatResponse struct {
-	CanChat              bool     `protobuf:"varint,1,opt,name=canChat,proto3" json:"canChat,omitempty"`
+type LeaderboardRequest struct {
+	Limit                int32    `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	MinGames             int32    `protobuf:"varint,2,opt,name=minGames,proto3" json:"minGames,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *ChatResponse) Reset()         { *m = ChatResponse{} }
-func (m *ChatResponse) String() string { return proto.CompactTextString(m) }
-func (*ChatResponse) ProtoMessage()    {}
-func (*ChatResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_fa11038ec5e9ab77, []int{2}
+func (m *LeaderboardRequest) Reset()         { *m = LeaderboardRequest{} }
+func (m *LeaderboardRequest) String() string { return proto.CompactTextString(m) }
+func (*LeaderboardRequest) ProtoMessage()    {}
+func (*LeaderboardRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{21}
 }
 
-func (m *ChatResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ChatResponse.Unmarshal(m, b)
+func (m *LeaderboardRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LeaderboardRequest.Unmarshal(m, b)
 }
-func (m *ChatResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ChatResponse.Marshal(b, m, deterministic)
+func (m *LeaderboardRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LeaderboardRequest.Marshal(b, m, deterministic)
 }
-func (m *ChatResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ChatResponse.Merge(m, src)
+func (m *LeaderboardRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LeaderboardRequest.Merge(m, src)
 }
-func (m *ChatResponse) XXX_Size() int {
-	return xxx_messageInfo_ChatResponse.Size(m)
+func (m *LeaderboardRequest) XXX_Size() int {
+	return xxx_messageInfo_LeaderboardRequest.Size(m)
 }
-func (m *ChatResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_ChatResponse.DiscardUnknown(m)
+func (m *LeaderboardRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LeaderboardRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ChatResponse proto.InternalMessageInfo
+var xxx_messageInfo_LeaderboardRequest proto.InternalMessageInfo
 
-func (m *ChatResponse) GetCanChat() bool {
+func (m *LeaderboardRequest) GetLimit() int32 {
 	if m != nil {
-		return m.CanChat
+		return m.Limit
 	}
-	return false
+	return 0
 }
 
-type PlayerInfo struct {
-	Session              string   `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
-	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *LeaderboardRequest) GetMinGames() int32 {
+	if m != nil {
+		return m.MinGames
+	}
+	return 0
 }
 
-func (m *PlayerInfo) Reset()         { *m = PlayerInfo{} }
-func (m *PlayerInfo) String() string { return proto.CompactTextString(m) }
-func (*PlayerInfo) ProtoMessage()    {}
-func (*PlayerInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_fa11038ec5e9ab77, []int{3}
+type Leaderboard struct {
+	Players              []*PlayerStats `protobuf:"bytes,1,rep,name=players,proto3" json:"players,omitempty"`
+	Total                int32          `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
-func (m *PlayerInfo) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PlayerInfo.Unmarshal(m, b)
+func (m *Leaderboard) Reset()         { *m = Leaderboard{} }
+func (m *Leaderboard) String() string { return proto.CompactTextString(m) }
+func (*Leaderboard) ProtoMessage()    {}
+func (*Leaderboard) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{22}
 }
-func (m *PlayerInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PlayerInfo.Marshal(b, m, deterministic)
+
+func (m *Leaderboard) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Leaderboard.Unmarshal(m, b)
 }
-func (m *PlayerInfo) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PlayerInfo.Merge(m, src)
+func (m *Leaderboard) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Leaderboard.Marshal(b, m, deterministic)
 }
-func (m *PlayerInfo) XXX_Size() int {
-	return xxx_messageInfo_PlayerInfo.Size(m)
+func (m *Leaderboard) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Leaderboard.Merge(m, src)
 }
-func (m *PlayerInfo) XXX_DiscardUnknown() {
-	xxx_messageInfo_PlayerInfo.DiscardUnknown(m)
+func (m *Leaderboard) XXX_Size() int {
+	return xxx_messageInfo_Leaderboard.Size(m)
+}
+func (m *Leaderboard) XXX_DiscardUnknown() {
+	xxx_messageInfo_Leaderboard.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_PlayerInfo proto.InternalMessageInfo
+var xxx_messageInfo_Leaderboard proto.InternalMessageInfo
 
-func (m *PlayerInfo) GetSession() string {
+func (m *Leaderboard) GetPlayers() []*PlayerStats {
 	if m != nil {
-		return m.Session
+		return m.Players
 	}
-	return ""
+	return nil
 }
 
-func (m *PlayerInfo) GetName() string {
+func (m *Leaderboard) GetTotal() int32 {
 	if m != nil {
-		return m.Name
+		return m.Total
 	}
-	return ""
+	return 0
 }
 
-type JoinRequest struct {
+// Token of the player is passed in the metadata as for the other calls
+type SubscribeRequest struct {
 	Player               *PlayerInfo `protobuf:"bytes,1,opt,name=player,proto3" json:"player,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
 	XXX_unrecognized     []byte      `json:"-"`
 	XXX_sizecache        int32       `json:"-"`
 }
 
-func (m *JoinRequest) Reset()         { *m = JoinRequest{} }
-func (m *JoinRequest) String() string { return proto.CompactTextString(m) }
-func (*JoinRequest) ProtoMessage()    {}
-func (*JoinRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_fa11038ec5e9ab77, []int{4}
+func (m *SubscribeRequest) Reset()         { *m = SubscribeRequest{} }
+func (m *SubscribeRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeRequest) ProtoMessage()    {}
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{23}
 }
 
-func (m *JoinRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_JoinRequest.Unmarshal(m, b)
+func (m *SubscribeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeRequest.Unmarshal(m, b)
 }
-func (m *JoinRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_JoinRequest.Marshal(b, m, deterministic)
+func (m *SubscribeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeRequest.Marshal(b, m, deterministic)
 }
-func (m *JoinRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_JoinRequest.Merge(m, src)
+func (m *SubscribeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeRequest.Merge(m, src)
 }
-func (m *JoinRequest) XXX_Size() int {
-	return xxx_messageInfo_JoinRequest.Size(m)
+func (m *SubscribeRequest) XXX_Size() int {
+	return xxx_messageInfo_SubscribeRequest.Size(m)
 }
-func (m *JoinRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_JoinRequest.DiscardUnknown(m)
+func (m *SubscribeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_JoinRequest proto.InternalMessageInfo
+var xxx_messageInfo_SubscribeRequest proto.InternalMessageInfo
 
-func (m *JoinRequest) GetPlayer() *PlayerInfo {
+func (m *SubscribeRequest) GetPlayer() *PlayerInfo {
 	if m != nil {
 		return m.Player
 	}
@@ -266,7 +1541,7 @@ func (m *SetVictimRequest) Reset()         { *m = SetVictimRequest{} }
 func (m *SetVictimRequest) String() string { return proto.CompactTextString(m) }
 func (*SetVictimRequest) ProtoMessage()    {}
 func (*SetVictimRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_fa11038ec5e9ab77, []int{5}
+	return fileDescriptor_fa11038ec5e9ab77, []int{24}
 }
 
 func (m *SetVictimRequest) XXX_Unmarshal(b []byte) error {
@@ -301,6 +1576,116 @@ func (m *SetVictimRequest) GetVictim() string {
 	return ""
 }
 
+type Vote struct {
+	Voter                string   `protobuf:"bytes,1,opt,name=voter,proto3" json:"voter,omitempty"`
+	Victim               string   `protobuf:"bytes,2,opt,name=victim,proto3" json:"victim,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Vote) Reset()         { *m = Vote{} }
+func (m *Vote) String() string { return proto.CompactTextString(m) }
+func (*Vote) ProtoMessage()    {}
+func (*Vote) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{25}
+}
+
+func (m *Vote) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Vote.Unmarshal(m, b)
+}
+func (m *Vote) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Vote.Marshal(b, m, deterministic)
+}
+func (m *Vote) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Vote.Merge(m, src)
+}
+func (m *Vote) XXX_Size() int {
+	return xxx_messageInfo_Vote.Size(m)
+}
+func (m *Vote) XXX_DiscardUnknown() {
+	xxx_messageInfo_Vote.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Vote proto.InternalMessageInfo
+
+func (m *Vote) GetVoter() string {
+	if m != nil {
+		return m.Voter
+	}
+	return ""
+}
+
+func (m *Vote) GetVictim() string {
+	if m != nil {
+		return m.Victim
+	}
+	return ""
+}
+
+type VoteTally struct {
+	Counts               map[string]int32 `protobuf:"bytes,1,rep,name=counts,proto3" json:"counts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	Skips                int32            `protobuf:"varint,2,opt,name=skips,proto3" json:"skips,omitempty"`
+	Voted                int32            `protobuf:"varint,3,opt,name=voted,proto3" json:"voted,omitempty"`
+	Votes                []*Vote          `protobuf:"bytes,4,rep,name=votes,proto3" json:"votes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *VoteTally) Reset()         { *m = VoteTally{} }
+func (m *VoteTally) String() string { return proto.CompactTextString(m) }
+func (*VoteTally) ProtoMessage()    {}
+func (*VoteTally) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa11038ec5e9ab77, []int{26}
+}
+
+func (m *VoteTally) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VoteTally.Unmarshal(m, b)
+}
+func (m *VoteTally) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VoteTally.Marshal(b, m, deterministic)
+}
+func (m *VoteTally) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VoteTally.Merge(m, src)
+}
+func (m *VoteTally) XXX_Size() int {
+	return xxx_messageInfo_VoteTally.Size(m)
+}
+func (m *VoteTally) XXX_DiscardUnknown() {
+	xxx_messageInfo_VoteTally.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VoteTally proto.InternalMessageInfo
+
+func (m *VoteTally) GetCounts() map[string]int32 {
+	if m != nil {
+		return m.Counts
+	}
+	return nil
+}
+
+func (m *VoteTally) GetSkips() int32 {
+	if m != nil {
+		return m.Skips
+	}
+	return 0
+}
+
+func (m *VoteTally) GetVoted() int32 {
+	if m != nil {
+		return m.Voted
+	}
+	return 0
+}
+
+func (m *VoteTally) GetVotes() []*Vote {
+	if m != nil {
+		return m.Votes
+	}
+	return nil
+}
+
 type GameState struct {
 	Session              string   `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
 	AlivePlayers         []string `protobuf:"bytes,2,rep,name=alivePlayers,proto3" json:"alivePlayers,omitempty"`
@@ -308,6 +1693,13 @@ type GameState struct {
 	IsDay                bool     `protobuf:"varint,4,opt,name=isDay,proto3" json:"isDay,omitempty"`
 	IsStarted            bool     `protobuf:"varint,5,opt,name=isStarted,proto3" json:"isStarted,omitempty"`
 	IsFinished           bool     `protobuf:"varint,6,opt,name=isFinished,proto3" json:"isFinished,omitempty"`
+	PhaseDeadline        int64    `protobuf:"varint,7,opt,name=phaseDeadline,proto3" json:"phaseDeadline,omitempty"`
+	IsRunoff             bool     `protobuf:"varint,8,opt,name=isRunoff,proto3" json:"isRunoff,omitempty"`
+	RunoffCandidates     []string `protobuf:"bytes,9,rep,name=runoffCandidates,proto3" json:"runoffCandidates,omitempty"`
+	GameId               string   `protobuf:"bytes,10,opt,name=gameId,proto3" json:"gameId,omitempty"`
+	Seed                 int64    `protobuf:"varint,11,opt,name=seed,proto3" json:"seed,omitempty"`
+	Spectators           int32    `protobuf:"varint,12,opt,name=spectators,proto3" json:"spectators,omitempty"`
+	Bots                 []string `protobuf:"bytes,13,rep,name=bots,proto3" json:"bots,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -317,7 +1709,7 @@ func (m *GameState) Reset()         { *m = GameState{} }
 func (m *GameState) String() string { return proto.CompactTextString(m) }
 func (*GameState) ProtoMessage()    {}
 func (*GameState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_fa11038ec5e9ab77, []int{6}
+	return fileDescriptor_fa11038ec5e9ab77, []int{27}
 }
 
 func (m *GameState) XXX_Unmarshal(b []byte) error {
@@ -380,6 +1772,55 @@ func (m *GameState) GetIsFinished() bool {
 	return false
 }
 
+func (m *GameState) GetPhaseDeadline() int64 {
+	if m != nil {
+		return m.PhaseDeadline
+	}
+	return 0
+}
+
+func (m *GameState) GetIsRunoff() bool {
+	if m != nil {
+		return m.IsRunoff
+	}
+	return false
+}
+
+func (m *GameState) GetRunoffCandidates() []string {
+	if m != nil {
+		return m.RunoffCandidates
+	}
+	return nil
+}
+
+func (m *GameState) GetGameId() string {
+	if m != nil {
+		return m.GameId
+	}
+	return ""
+}
+
+func (m *GameState) GetSeed() int64 {
+	if m != nil {
+		return m.Seed
+	}
+	return 0
+}
+
+func (m *GameState) GetSpectators() int32 {
+	if m != nil {
+		return m.Spectators
+	}
+	return 0
+}
+
+func (m *GameState) GetBots() []string {
+	if m != nil {
+		return m.Bots
+	}
+	return nil
+}
+
 type Notification struct {
 	Type      NotificationType `protobuf:"varint,1,opt,name=type,proto3,enum=mafia_grpc.NotificationType" json:"type,omitempty"`
 	GameState *GameState       `protobuf:"bytes,2,opt,name=gameState,proto3" json:"gameState,omitempty"`
@@ -387,7 +1828,13 @@ type Notification struct {
 	//	*Notification_Role
 	//	*Notification_KilledPlayer
 	//	*Notification_Mafia
+	//	*Notification_RematchGameId
 	Details              isNotification_Details `protobuf_oneof:"details"`
+	MafiaTeam            []string               `protobuf:"bytes,6,rep,name=mafiaTeam,proto3" json:"mafiaTeam,omitempty"`
+	MafiaLeader          string                 `protobuf:"bytes,7,opt,name=mafiaLeader,proto3" json:"mafiaLeader,omitempty"`
+	VoteTally            *VoteTally             `protobuf:"bytes,8,opt,name=voteTally,proto3" json:"voteTally,omitempty"`
+	Roles                map[string]string      `protobuf:"bytes,10,rep,name=roles,proto3" json:"roles,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Notice               string                 `protobuf:"bytes,11,opt,name=notice,proto3" json:"notice,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
 	XXX_unrecognized     []byte                 `json:"-"`
 	XXX_sizecache        int32                  `json:"-"`
@@ -397,7 +1844,7 @@ func (m *Notification) Reset()         { *m = Notification{} }
 func (m *Notification) String() string { return proto.CompactTextString(m) }
 func (*Notification) ProtoMessage()    {}
 func (*Notification) Descriptor() ([]byte, []int) {
-	return fileDescriptor_fa11038ec5e9ab77, []int{7}
+	return fileDescriptor_fa11038ec5e9ab77, []int{28}
 }
 
 func (m *Notification) XXX_Unmarshal(b []byte) error {
@@ -448,12 +1895,18 @@ type Notification_Mafia struct {
 	Mafia string `protobuf:"bytes,5,opt,name=mafia,proto3,oneof"`
 }
 
+type Notification_RematchGameId struct {
+	RematchGameId string `protobuf:"bytes,9,opt,name=rematchGameId,proto3,oneof"`
+}
+
 func (*Notification_Role) isNotification_Details() {}
 
 func (*Notification_KilledPlayer) isNotification_Details() {}
 
 func (*Notification_Mafia) isNotification_Details() {}
 
+func (*Notification_RematchGameId) isNotification_Details() {}
+
 func (m *Notification) GetDetails() isNotification_Details {
 	if m != nil {
 		return m.Details
@@ -482,25 +1935,98 @@ func (m *Notification) GetMafia() string {
 	return ""
 }
 
+func (m *Notification) GetRematchGameId() string {
+	if x, ok := m.GetDetails().(*Notification_RematchGameId); ok {
+		return x.RematchGameId
+	}
+	return ""
+}
+
+func (m *Notification) GetMafiaTeam() []string {
+	if m != nil {
+		return m.MafiaTeam
+	}
+	return nil
+}
+
+func (m *Notification) GetMafiaLeader() string {
+	if m != nil {
+		return m.MafiaLeader
+	}
+	return ""
+}
+
+func (m *Notification) GetVoteTally() *VoteTally {
+	if m != nil {
+		return m.VoteTally
+	}
+	return nil
+}
+
+func (m *Notification) GetRoles() map[string]string {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
+func (m *Notification) GetNotice() string {
+	if m != nil {
+		return m.Notice
+	}
+	return ""
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*Notification) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
 		(*Notification_Role)(nil),
 		(*Notification_KilledPlayer)(nil),
 		(*Notification_Mafia)(nil),
+		(*Notification_RematchGameId)(nil),
 	}
 }
 
 func init() {
+	proto.RegisterEnum("mafia_grpc.ErrorReason", ErrorReason_name, ErrorReason_value)
+	proto.RegisterEnum("mafia_grpc.KillRule", KillRule_name, KillRule_value)
+	proto.RegisterEnum("mafia_grpc.RunoffFallback", RunoffFallback_name, RunoffFallback_value)
+	proto.RegisterEnum("mafia_grpc.SessionStatus", SessionStatus_name, SessionStatus_value)
 	proto.RegisterEnum("mafia_grpc.NotificationType", NotificationType_name, NotificationType_value)
 	proto.RegisterType((*Response)(nil), "mafia_grpc.Response")
+	proto.RegisterType((*JoinResponse)(nil), "mafia_grpc.JoinResponse")
 	proto.RegisterType((*CheckMafiaResponse)(nil), "mafia_grpc.CheckMafiaResponse")
 	proto.RegisterType((*ChatResponse)(nil), "mafia_grpc.ChatResponse")
 	proto.RegisterType((*PlayerInfo)(nil), "mafia_grpc.PlayerInfo")
+	proto.RegisterType((*SessionConfig)(nil), "mafia_grpc.SessionConfig")
+	proto.RegisterMapType((map[string]int32)(nil), "mafia_grpc.SessionConfig.RolesEntry")
 	proto.RegisterType((*JoinRequest)(nil), "mafia_grpc.JoinRequest")
+	proto.RegisterType((*AddBotsRequest)(nil), "mafia_grpc.AddBotsRequest")
+	proto.RegisterType((*SpectateRequest)(nil), "mafia_grpc.SpectateRequest")
+	proto.RegisterType((*ListSessionsRequest)(nil), "mafia_grpc.ListSessionsRequest")
+	proto.RegisterType((*SessionRequest)(nil), "mafia_grpc.SessionRequest")
+	proto.RegisterType((*SessionInfo)(nil), "mafia_grpc.SessionInfo")
+	proto.RegisterType((*SessionList)(nil), "mafia_grpc.SessionList")
+	proto.RegisterType((*AdminGame)(nil), "mafia_grpc.AdminGame")
+	proto.RegisterType((*AdminGameList)(nil), "mafia_grpc.AdminGameList")
+	proto.RegisterType((*AdminSessionRequest)(nil), "mafia_grpc.AdminSessionRequest")
+	proto.RegisterType((*KickRequest)(nil), "mafia_grpc.KickRequest")
+	proto.RegisterType((*NoticeRequest)(nil), "mafia_grpc.NoticeRequest")
+	proto.RegisterType((*BroadcastResponse)(nil), "mafia_grpc.BroadcastResponse")
+	proto.RegisterType((*PlayerStatsRequest)(nil), "mafia_grpc.PlayerStatsRequest")
+	proto.RegisterType((*PlayerStats)(nil), "mafia_grpc.PlayerStats")
+	proto.RegisterMapType((map[string]int32)(nil), "mafia_grpc.PlayerStats.GamesByRoleEntry")
+	proto.RegisterMapType((map[string]int32)(nil), "mafia_grpc.PlayerStats.WinsByRoleEntry")
+	proto.RegisterType((*LeaderboardRequest)(nil), "mafia_grpc.LeaderboardRequest")
+	proto.RegisterType((*Leaderboard)(nil), "mafia_grpc.Leaderboard")
+	proto.RegisterType((*SubscribeRequest)(nil), "mafia_grpc.SubscribeRequest")
 	proto.RegisterType((*SetVictimRequest)(nil), "mafia_grpc.SetVictimRequest")
+	proto.RegisterType((*Vote)(nil), "mafia_grpc.Vote")
+	proto.RegisterType((*VoteTally)(nil), "mafia_grpc.VoteTally")
+	proto.RegisterMapType((map[string]int32)(nil), "mafia_grpc.VoteTally.CountsEntry")
 	proto.RegisterType((*GameState)(nil), "mafia_grpc.GameState")
 	proto.RegisterType((*Notification)(nil), "mafia_grpc.Notification")
+	proto.RegisterMapType((map[string]string)(nil), "mafia_grpc.Notification.RolesEntry")
 }
 
 func init() {
@@ -508,43 +2034,153 @@ func init() {
 }
 
 var fileDescriptor_fa11038ec5e9ab77 = []byte{
-	// 607 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0xdd, 0x6e, 0xd3, 0x4c,
-	0x10, 0xb5, 0x53, 0x3b, 0xa9, 0x27, 0xf9, 0x2a, 0x6b, 0xd4, 0xaf, 0x58, 0x51, 0x55, 0xaa, 0x15,
-	0x17, 0x11, 0x17, 0x69, 0x95, 0x4a, 0x20, 0x01, 0xbd, 0xe8, 0x6f, 0x12, 0x10, 0x11, 0x38, 0x51,
-	0x11, 0xbd, 0xa9, 0x16, 0x67, 0xd3, 0xae, 0xec, 0x78, 0x4d, 0x76, 0x5b, 0x29, 0xaf, 0xc0, 0xdb,
-	0xf0, 0x2e, 0x3c, 0x10, 0xf2, 0x3a, 0x69, 0x36, 0x85, 0x54, 0xa8, 0x77, 0x3b, 0x67, 0xce, 0xcc,
-	0xce, 0x99, 0x9d, 0x59, 0x78, 0x9e, 0xc5, 0xd7, 0x7b, 0x63, 0x3a, 0xe2, 0xf4, 0xea, 0x7a, 0x92,
-	0x45, 0xc6, 0xb1, 0x99, 0x4d, 0x84, 0x12, 0x08, 0x0b, 0x84, 0xd4, 0x61, 0x3d, 0x64, 0x32, 0x13,
-	0xa9, 0x64, 0xb8, 0x01, 0x25, 0x11, 0x07, 0xf6, 0xae, 0xdd, 0x58, 0x0f, 0x4b, 0x22, 0x26, 0x4d,
-	0xc0, 0x93, 0x1b, 0x16, 0xc5, 0x1f, 0x73, 0xfa, 0x3d, 0x2b, 0x80, 0x0a, 0x97, 0x1a, 0x9a, 0x51,
-	0xe7, 0x26, 0x69, 0x40, 0xed, 0xe4, 0x86, 0x2a, 0x93, 0x19, 0xd1, 0x34, 0x87, 0xe6, 0xcc, 0x99,
-	0x49, 0xde, 0x00, 0x7c, 0x4a, 0xe8, 0x94, 0x4d, 0xba, 0xe9, 0x48, 0xe4, 0x3c, 0xc9, 0xa4, 0xe4,
-	0x22, 0xd5, 0x3c, 0x2f, 0x9c, 0x9b, 0x88, 0xe0, 0xa4, 0x74, 0xcc, 0x82, 0x92, 0x86, 0xf5, 0x99,
-	0x1c, 0x42, 0xf5, 0xbd, 0xe0, 0x69, 0xc8, 0xbe, 0xdf, 0x32, 0xa9, 0xb0, 0x09, 0xe5, 0x4c, 0xa7,
-	0xd2, 0xb1, 0xd5, 0xd6, 0x56, 0xd3, 0xd0, 0xbb, 0xb8, 0x24, 0x9c, 0xb1, 0xc8, 0x25, 0xf8, 0x7d,
-	0xa6, 0x2e, 0x78, 0xa4, 0xf8, 0xf8, 0x89, 0x39, 0x70, 0x0b, 0xca, 0x77, 0x3a, 0xc1, 0xac, 0xb0,
-	0x99, 0x45, 0x7e, 0xda, 0xe0, 0xb5, 0xe9, 0x98, 0xf5, 0x15, 0x55, 0xec, 0x11, 0x59, 0x04, 0x6a,
-	0x34, 0xe1, 0x77, 0xac, 0x48, 0x2d, 0x83, 0xd2, 0xee, 0x5a, 0xc3, 0x0b, 0x97, 0xb0, 0x5c, 0xfa,
-	0x90, 0x2a, 0x16, 0xac, 0xed, 0xda, 0x0d, 0x37, 0xd4, 0x67, 0xdc, 0x04, 0x97, 0xcb, 0x53, 0x3a,
-	0x0d, 0x1c, 0xdd, 0xce, 0xc2, 0xc0, 0x6d, 0xf0, 0xb8, 0xec, 0x2b, 0x3a, 0x51, 0x6c, 0x18, 0xb8,
-	0xda, 0xb3, 0x00, 0x70, 0x07, 0x80, 0xcb, 0x73, 0x9e, 0x72, 0x79, 0xc3, 0x86, 0x41, 0x59, 0xbb,
-	0x0d, 0x84, 0xfc, 0xb2, 0xa1, 0xd6, 0x13, 0x8a, 0x8f, 0x78, 0x44, 0x55, 0x5e, 0xdc, 0x3e, 0x38,
-	0x6a, 0x9a, 0x31, 0x5d, 0xf3, 0x46, 0x6b, 0xdb, 0x6c, 0x85, 0xc9, 0x1b, 0x4c, 0x33, 0x16, 0x6a,
-	0x26, 0x1e, 0x80, 0x77, 0x3d, 0x57, 0xad, 0x3b, 0x52, 0x6d, 0xfd, 0x6f, 0x86, 0xdd, 0xb7, 0x24,
-	0x5c, 0xf0, 0x70, 0x13, 0x9c, 0x89, 0x48, 0x0a, 0x7d, 0x5e, 0xc7, 0x0a, 0xb5, 0x85, 0x2f, 0xa0,
-	0x16, 0xf3, 0x24, 0x61, 0xc3, 0xa2, 0x0d, 0x5a, 0x68, 0xee, 0x5d, 0x42, 0x71, 0x0b, 0x5c, 0x9d,
-	0x5e, 0xab, 0xcd, 0xdd, 0x85, 0x79, 0xec, 0x41, 0x65, 0xc8, 0x14, 0xe5, 0x89, 0x7c, 0x79, 0x06,
-	0xfe, 0xc3, 0x6a, 0xd1, 0x03, 0xb7, 0x3f, 0x38, 0x0a, 0x07, 0xbe, 0x85, 0x00, 0xe5, 0xf3, 0x6e,
-	0xaf, 0xdb, 0xef, 0xf8, 0x36, 0x56, 0xa1, 0xd2, 0x3b, 0xfb, 0x72, 0x75, 0x7a, 0xf4, 0xd5, 0x2f,
-	0xe1, 0x7f, 0xe0, 0xe5, 0x46, 0xaf, 0xdb, 0xee, 0x0c, 0xfc, 0xb5, 0xd6, 0x0f, 0x07, 0x5c, 0x3d,
-	0xdc, 0xf8, 0x1a, 0x9c, 0x7c, 0xec, 0xf0, 0x99, 0xa9, 0xcc, 0x18, 0xc4, 0xfa, 0xa6, 0xe9, 0x98,
-	0xef, 0x00, 0xb1, 0xf0, 0x1d, 0x38, 0x17, 0x42, 0x31, 0x5c, 0xea, 0xe4, 0xc3, 0x11, 0x7c, 0x2c,
-	0xfa, 0x03, 0x4f, 0x92, 0x27, 0x46, 0xf7, 0xf2, 0x8d, 0x64, 0x51, 0xdc, 0x1d, 0x15, 0x22, 0x1e,
-	0xcf, 0xb2, 0x63, 0x7a, 0xff, 0xdc, 0x7c, 0x62, 0xe1, 0x5b, 0x58, 0x6f, 0x33, 0x55, 0x3c, 0xe0,
-	0x8a, 0x25, 0xa9, 0xff, 0xfd, 0xe9, 0x89, 0x85, 0x87, 0x50, 0x39, 0x29, 0xf6, 0x7f, 0x65, 0x6c,
-	0xb0, 0x5c, 0xc1, 0xe2, 0x2f, 0x21, 0x16, 0xbe, 0x02, 0xe7, 0xf3, 0x2d, 0x5f, 0x1d, 0xbb, 0xaa,
-	0x07, 0x1d, 0xf0, 0xdb, 0x4c, 0x99, 0xc3, 0x20, 0xff, 0xed, 0x7e, 0x33, 0x84, 0x58, 0xfb, 0xf6,
-	0x71, 0xfd, 0x32, 0x90, 0x82, 0x5e, 0x69, 0xca, 0xde, 0xf2, 0x27, 0xfb, 0xad, 0xac, 0xbf, 0xd6,
-	0x83, 0xdf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x1a, 0xe2, 0xb0, 0xc4, 0x7d, 0x05, 0x00, 0x00,
+	// 2357 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x59, 0xcd, 0x72, 0xdb, 0xc8,
+	0x11, 0x16, 0x7f, 0x45, 0x36, 0x7f, 0x8c, 0x9d, 0x55, 0xbc, 0x5c, 0xc6, 0xb1, 0x1d, 0xc4, 0xe5,
+	0xa8, 0xb4, 0x55, 0xb2, 0x57, 0xce, 0xcf, 0xda, 0xbb, 0xde, 0x2a, 0x8a, 0x84, 0x28, 0x5a, 0x14,
+	0xe8, 0x0c, 0x41, 0x29, 0xf6, 0x85, 0x05, 0x91, 0x23, 0x09, 0x45, 0x08, 0x60, 0x08, 0x50, 0x8e,
+	0x0e, 0x79, 0x83, 0x9c, 0xf2, 0x16, 0x79, 0x8c, 0x3c, 0x44, 0xce, 0x79, 0x80, 0x5c, 0x72, 0xcb,
+	0x2d, 0x49, 0xf5, 0xcc, 0x00, 0x1c, 0x48, 0xa4, 0x6c, 0x2b, 0xb7, 0xe9, 0x9e, 0xaf, 0x1b, 0x3d,
+	0xfd, 0x33, 0x3d, 0x33, 0x80, 0x47, 0xd3, 0xc9, 0xd9, 0xb3, 0x0b, 0xfb, 0xd4, 0xb1, 0x87, 0x67,
+	0xb3, 0xe9, 0x48, 0x19, 0x6e, 0x4f, 0x67, 0x7e, 0xe8, 0x13, 0x58, 0x70, 0xf4, 0x3a, 0x14, 0x28,
+	0x0b, 0xa6, 0xbe, 0x17, 0x30, 0x52, 0x85, 0xb4, 0x3f, 0xa9, 0xa5, 0x1e, 0xa7, 0x36, 0x0b, 0x34,
+	0xed, 0x4f, 0xf4, 0x2e, 0x94, 0xdf, 0xf8, 0x8e, 0xb7, 0x6a, 0x9e, 0x6c, 0x40, 0x2e, 0xf4, 0x27,
+	0xcc, 0xab, 0xa5, 0x1f, 0xa7, 0x36, 0x8b, 0x54, 0x10, 0xe4, 0x3e, 0xe4, 0xcf, 0xec, 0x0b, 0xd6,
+	0x19, 0xd7, 0x32, 0x9c, 0x2d, 0x29, 0x7d, 0x1b, 0x48, 0xf3, 0x9c, 0x8d, 0x26, 0x87, 0xf8, 0xf1,
+	0x58, 0x67, 0x0d, 0xd6, 0x9d, 0x80, 0xb3, 0xa4, 0xe2, 0x88, 0xd4, 0x37, 0xa1, 0xdc, 0x3c, 0xb7,
+	0x43, 0x15, 0x39, 0xb2, 0x3d, 0x64, 0x45, 0x48, 0x49, 0xea, 0xaf, 0x00, 0xde, 0xba, 0xf6, 0x15,
+	0x9b, 0x75, 0xbc, 0x53, 0x1f, 0x71, 0x01, 0x0b, 0x02, 0xc7, 0xf7, 0x38, 0xae, 0x48, 0x23, 0x92,
+	0x10, 0xc8, 0x7a, 0xf6, 0x05, 0x93, 0xe6, 0xf2, 0xb1, 0xfe, 0xef, 0x2c, 0x54, 0xfa, 0x62, 0xbe,
+	0xe9, 0x7b, 0xa7, 0xce, 0x19, 0xca, 0x4f, 0xb9, 0xb6, 0x80, 0xcb, 0xe7, 0x68, 0x44, 0x92, 0x57,
+	0x90, 0x9b, 0xf9, 0x2e, 0x0b, 0x6a, 0xe9, 0xc7, 0x99, 0xcd, 0xd2, 0xce, 0x93, 0x6d, 0xc5, 0xb3,
+	0x09, 0x1d, 0xdb, 0x14, 0x61, 0x86, 0x17, 0xce, 0xae, 0xa8, 0x10, 0x21, 0xcf, 0xa1, 0x30, 0x71,
+	0x5c, 0x97, 0xce, 0x5d, 0xc6, 0xfd, 0x52, 0xdd, 0xd9, 0x50, 0xc5, 0x0f, 0xe4, 0x1c, 0x8d, 0x51,
+	0xe4, 0x29, 0x54, 0xc7, 0xfe, 0x28, 0xf4, 0x67, 0x7d, 0xe6, 0x9e, 0xee, 0x33, 0xdb, 0xad, 0x65,
+	0xf9, 0xb2, 0xaf, 0x71, 0xc9, 0x16, 0x68, 0x82, 0x43, 0xd9, 0x94, 0xd9, 0x21, 0x47, 0xe6, 0x38,
+	0xf2, 0x06, 0x9f, 0x3c, 0x86, 0xd2, 0xd8, 0xbe, 0x6a, 0xcd, 0x67, 0x76, 0x88, 0xfe, 0xc9, 0xf3,
+	0xf5, 0xa9, 0x2c, 0xf2, 0x04, 0x2a, 0x9e, 0x73, 0x76, 0x1e, 0xc6, 0x98, 0x75, 0x8e, 0x49, 0x32,
+	0x31, 0xc6, 0xb3, 0xb9, 0xe7, 0x9f, 0x9e, 0xd6, 0x0a, 0xfc, 0x4b, 0x92, 0x22, 0xbb, 0x50, 0x15,
+	0xa3, 0x3d, 0xdb, 0x75, 0x4f, 0xec, 0xd1, 0xa4, 0x56, 0xe4, 0x6b, 0xad, 0xab, 0x6b, 0xa5, 0x09,
+	0x04, 0xbd, 0x26, 0x41, 0x1e, 0x02, 0xf8, 0x53, 0xe6, 0xed, 0xda, 0xae, 0xeb, 0x87, 0x35, 0xe0,
+	0xfa, 0x15, 0x0e, 0x46, 0x31, 0x60, 0x6c, 0x5c, 0x2b, 0x3d, 0x4e, 0x6d, 0x66, 0x28, 0x1f, 0x93,
+	0x1d, 0xd8, 0xf0, 0x2f, 0x3c, 0x27, 0x18, 0x39, 0xcc, 0x0b, 0xfb, 0x53, 0x36, 0x0a, 0xed, 0xd0,
+	0x9f, 0x05, 0xb5, 0x32, 0x97, 0x5e, 0x3a, 0x47, 0x74, 0x28, 0x9f, 0xf8, 0xe1, 0x9e, 0xe3, 0xba,
+	0x2d, 0xe6, 0xda, 0x57, 0xb5, 0x0a, 0x5f, 0x68, 0x82, 0x87, 0xfe, 0x3a, 0xb7, 0x83, 0x28, 0x38,
+	0xb5, 0x2a, 0x57, 0xa7, 0xb2, 0xea, 0xdf, 0x01, 0x2c, 0x82, 0x4d, 0x34, 0xc8, 0x4c, 0xd8, 0x95,
+	0xcc, 0x3b, 0x1c, 0x62, 0x8d, 0x5c, 0xda, 0xee, 0x5c, 0x24, 0x5d, 0x8e, 0x0a, 0xe2, 0x55, 0xfa,
+	0xbb, 0x94, 0xfe, 0xe7, 0x14, 0x94, 0x44, 0x79, 0xfd, 0x61, 0xce, 0x82, 0x90, 0x6c, 0x43, 0x5e,
+	0x24, 0x1a, 0x17, 0x2f, 0xed, 0xdc, 0x57, 0x7d, 0xb6, 0xc8, 0x6f, 0x2a, 0x51, 0xe4, 0x5b, 0xc8,
+	0x8f, 0x78, 0xb6, 0x71, 0xd5, 0xa5, 0x9d, 0xaf, 0x57, 0xa6, 0x23, 0x95, 0x40, 0x52, 0x87, 0xc2,
+	0xd4, 0x0e, 0x82, 0x0f, 0xfe, 0x2c, 0x2a, 0xce, 0x98, 0xd6, 0x8f, 0xa0, 0xda, 0x18, 0x8f, 0x77,
+	0xfd, 0x30, 0xb8, 0xab, 0x41, 0x1b, 0x90, 0x1b, 0xf9, 0x73, 0x2f, 0x8c, 0x96, 0xca, 0x09, 0xfd,
+	0x4f, 0x70, 0x4f, 0x3a, 0x9d, 0xdd, 0x55, 0x31, 0x66, 0x44, 0x1c, 0xc1, 0x5a, 0x5a, 0x66, 0x44,
+	0xcc, 0xb9, 0x75, 0x59, 0x5d, 0xf8, 0xb2, 0xeb, 0x04, 0xa1, 0xf4, 0x47, 0xbc, 0xb6, 0x5f, 0x43,
+	0x21, 0x08, 0xed, 0x70, 0x1e, 0x30, 0xac, 0xf2, 0xcc, 0x66, 0x75, 0xa9, 0xfb, 0xfa, 0x1c, 0x42,
+	0x63, 0xa8, 0xbe, 0x05, 0x55, 0x39, 0x15, 0x29, 0x5a, 0xb9, 0xdb, 0xe8, 0x7f, 0x4d, 0x43, 0x49,
+	0x82, 0x3f, 0xb2, 0x2f, 0x2d, 0x76, 0xcc, 0xb4, 0xba, 0x63, 0xaa, 0x3b, 0x51, 0x26, 0xb9, 0x13,
+	0xd5, 0xa1, 0x30, 0xb2, 0xa7, 0xf6, 0xc8, 0x09, 0xaf, 0xf8, 0xae, 0x90, 0xa3, 0x31, 0x8d, 0x79,
+	0x21, 0xec, 0xe5, 0xbb, 0xc0, 0xad, 0x0b, 0x93, 0x40, 0x25, 0x95, 0xf2, 0x9f, 0x9a, 0x4a, 0x68,
+	0xdb, 0xcc, 0xb9, 0xb4, 0x43, 0xc6, 0x77, 0x88, 0x02, 0x8d, 0x48, 0x8c, 0x56, 0xb0, 0xa8, 0xc0,
+	0x02, 0xb7, 0x4e, 0xe1, 0x60, 0xfd, 0x9e, 0xf8, 0x61, 0xc0, 0x77, 0x86, 0x1c, 0xe5, 0x63, 0x7d,
+	0x37, 0x76, 0x15, 0x06, 0x8b, 0xbc, 0x80, 0x82, 0xf4, 0x8d, 0x88, 0x4e, 0x69, 0xe7, 0xab, 0x25,
+	0x16, 0xf1, 0x1c, 0x89, 0x81, 0x3a, 0x85, 0x62, 0x63, 0x7c, 0xe1, 0x78, 0x6d, 0xfb, 0x82, 0x91,
+	0x6f, 0x20, 0xeb, 0x78, 0xa7, 0xbe, 0x4c, 0xb0, 0x95, 0xd2, 0x1c, 0x84, 0x6b, 0x19, 0xb3, 0xd0,
+	0x76, 0xdc, 0x40, 0x06, 0x20, 0x22, 0xf5, 0x1f, 0xa0, 0x12, 0xeb, 0xe4, 0x96, 0x7d, 0x03, 0x39,
+	0x0c, 0x4e, 0x64, 0xd6, 0x4f, 0x54, 0xc5, 0x31, 0x92, 0x0a, 0x8c, 0xde, 0x86, 0x2f, 0x39, 0xef,
+	0x53, 0x53, 0x86, 0x6f, 0xab, 0xcc, 0x0e, 0xfc, 0xa8, 0xa3, 0x4a, 0x4a, 0x3f, 0x86, 0xd2, 0x81,
+	0x33, 0x9a, 0x7c, 0x92, 0x02, 0x59, 0x59, 0x52, 0x81, 0xa0, 0x14, 0xc5, 0x99, 0x84, 0xe2, 0xd7,
+	0x50, 0x31, 0xfd, 0xd0, 0x19, 0xb1, 0x8f, 0xab, 0x26, 0x90, 0x0d, 0xd9, 0x1f, 0xc3, 0xa8, 0x79,
+	0xe2, 0x58, 0x7f, 0x06, 0x5f, 0xec, 0xce, 0x7c, 0x7b, 0x3c, 0xb2, 0x83, 0x45, 0x9f, 0xae, 0x27,
+	0x82, 0xc7, 0x73, 0x33, 0x8e, 0xd1, 0x26, 0x10, 0x51, 0xdf, 0x98, 0x80, 0x71, 0x31, 0x46, 0x7d,
+	0x39, 0xa5, 0xf4, 0xe5, 0x7f, 0x64, 0xa0, 0xa4, 0x40, 0x97, 0x61, 0x90, 0x37, 0xb3, 0xbd, 0x89,
+	0xdc, 0x6f, 0xf8, 0x98, 0xaf, 0xd4, 0x0e, 0x1d, 0xef, 0x8c, 0xaf, 0x34, 0x45, 0x25, 0x85, 0x9b,
+	0x93, 0x08, 0x9c, 0x28, 0x17, 0x41, 0xa0, 0x86, 0x0f, 0x8e, 0x27, 0x2a, 0x25, 0x47, 0xf9, 0x98,
+	0xbc, 0x81, 0x12, 0x9f, 0xdc, 0xbd, 0xc2, 0x8d, 0xbd, 0x96, 0xe7, 0x81, 0xde, 0xbc, 0xb9, 0x45,
+	0x71, 0xbb, 0xb6, 0xdb, 0x0b, 0xa8, 0xe8, 0xf7, 0xaa, 0x30, 0x69, 0x03, 0xa0, 0x4e, 0xa9, 0x6a,
+	0x9d, 0xab, 0xfa, 0xe5, 0x2a, 0x55, 0xc7, 0x8e, 0x97, 0xd0, 0xa4, 0x88, 0x72, 0xa7, 0xce, 0x67,
+	0x97, 0xce, 0x25, 0x1b, 0xcb, 0x92, 0x8a, 0x69, 0x5c, 0xf2, 0x08, 0x0f, 0x56, 0x51, 0x49, 0x49,
+	0x0a, 0x0b, 0x91, 0x7f, 0x69, 0xcf, 0x9f, 0x7b, 0x63, 0xde, 0x48, 0x73, 0x54, 0xe1, 0xd4, 0x7f,
+	0x04, 0xed, 0xba, 0xf5, 0x9f, 0xd3, 0xc0, 0xea, 0xaf, 0xe1, 0xde, 0x35, 0x93, 0x3f, 0xab, 0xff,
+	0xed, 0x01, 0xe9, 0x32, 0x7b, 0xcc, 0x66, 0x27, 0xbe, 0x3d, 0x1b, 0x47, 0xb9, 0xb0, 0x01, 0x39,
+	0xd7, 0xb9, 0x70, 0x42, 0x99, 0x3a, 0x82, 0xc0, 0xe5, 0xcb, 0xda, 0x0a, 0xa4, 0xa2, 0x98, 0xd6,
+	0x8f, 0xa0, 0xa4, 0xe8, 0x21, 0xdf, 0xaa, 0xc7, 0xb7, 0x1b, 0x5b, 0x87, 0x9a, 0x7d, 0x11, 0x4e,
+	0x9c, 0x63, 0x43, 0xdb, 0x8d, 0x6c, 0xe4, 0x84, 0xde, 0x03, 0xad, 0x3f, 0x3f, 0x09, 0x46, 0x33,
+	0xe7, 0xe4, 0xae, 0x9d, 0xeb, 0x4d, 0xb6, 0x90, 0xd6, 0x32, 0xf2, 0x60, 0xac, 0xbf, 0x07, 0xad,
+	0xcf, 0xc2, 0x23, 0x67, 0x14, 0x3a, 0x17, 0x77, 0x6d, 0x85, 0xf7, 0x21, 0x7f, 0xc9, 0x15, 0x44,
+	0x05, 0x2e, 0x28, 0xfd, 0x57, 0x90, 0x3d, 0xf2, 0x43, 0xc6, 0xdd, 0xed, 0x87, 0x52, 0x5d, 0x91,
+	0x0a, 0x62, 0xa5, 0xd4, 0xdf, 0x53, 0x50, 0x44, 0x31, 0xcb, 0x76, 0xdd, 0x2b, 0xf2, 0x12, 0xbb,
+	0xc0, 0xdc, 0x0b, 0x23, 0xc7, 0xfd, 0x5c, 0xb5, 0x25, 0x86, 0x6d, 0x37, 0x39, 0x46, 0xa4, 0xa8,
+	0x14, 0xc0, 0xcf, 0x06, 0x13, 0x67, 0x1a, 0x05, 0x47, 0x10, 0x91, 0x31, 0x63, 0xd9, 0xbd, 0x04,
+	0x41, 0x9e, 0x0a, 0x2e, 0x56, 0x22, 0x7e, 0x45, 0xbb, 0xfe, 0x15, 0x81, 0x0b, 0xea, 0x2f, 0xa1,
+	0xa4, 0x7c, 0xea, 0xb3, 0x52, 0xeb, 0xbf, 0x69, 0x28, 0x62, 0x72, 0x60, 0x9c, 0xd9, 0x2d, 0x7b,
+	0x9a, 0x0e, 0x65, 0xdb, 0x75, 0x2e, 0xd9, 0x5b, 0x99, 0x30, 0x78, 0xae, 0x2f, 0xd2, 0x04, 0x0f,
+	0xb7, 0x88, 0x31, 0x76, 0x39, 0xb1, 0x06, 0x3e, 0xc6, 0x2f, 0x3b, 0x41, 0xcb, 0xbe, 0x92, 0x27,
+	0x72, 0x41, 0x90, 0x07, 0x50, 0x74, 0x82, 0x7e, 0x68, 0xcf, 0x70, 0xc9, 0xe2, 0x04, 0xbe, 0x60,
+	0x60, 0x35, 0x3a, 0xc1, 0x9e, 0xe3, 0x39, 0xc1, 0x39, 0x1b, 0xf3, 0x3e, 0x5b, 0xa0, 0x0a, 0x07,
+	0x0f, 0xde, 0xd3, 0x73, 0x3b, 0x60, 0x2d, 0x66, 0x8f, 0x5d, 0xc7, 0x13, 0x6d, 0x35, 0x43, 0x93,
+	0x4c, 0x2c, 0x04, 0x27, 0xa0, 0xea, 0xd1, 0x3b, 0xa6, 0xf1, 0x22, 0x20, 0x8e, 0xd2, 0x4d, 0xdb,
+	0x1b, 0x3b, 0x68, 0x28, 0xee, 0x08, 0xb8, 0xa2, 0x1b, 0x7c, 0xe5, 0xc8, 0x01, 0x89, 0x23, 0xc7,
+	0xb2, 0xc3, 0x75, 0xb2, 0xa1, 0x97, 0x57, 0x36, 0xf4, 0x0a, 0xff, 0x16, 0x1f, 0xeb, 0xff, 0xc9,
+	0x40, 0x19, 0x3b, 0xcb, 0xa9, 0x33, 0x12, 0x37, 0x86, 0xe7, 0x90, 0x0d, 0xaf, 0xa6, 0x62, 0xff,
+	0xae, 0xee, 0x3c, 0x50, 0x83, 0xae, 0xe2, 0xac, 0xab, 0x29, 0xa3, 0x1c, 0x49, 0x5e, 0x40, 0xf1,
+	0x2c, 0x8a, 0xa1, 0x3c, 0xe2, 0x26, 0xda, 0x6d, 0x1c, 0x60, 0xba, 0xc0, 0x91, 0x0d, 0xc8, 0xe2,
+	0x7d, 0x4b, 0xb4, 0xb9, 0xfd, 0x35, 0xca, 0x29, 0xf2, 0x04, 0xca, 0x78, 0xad, 0x62, 0x63, 0x11,
+	0xd4, 0x5a, 0x56, 0xce, 0x26, 0xb8, 0xe4, 0x3e, 0xe4, 0xb8, 0xfa, 0x5a, 0x4e, 0x4e, 0x0b, 0x92,
+	0x3c, 0x85, 0xca, 0x8c, 0x5d, 0xd8, 0xe1, 0xe8, 0xbc, 0x2d, 0x5c, 0x56, 0x94, 0xf3, 0x49, 0x36,
+	0xc6, 0x9f, 0x0b, 0x58, 0xcc, 0xbe, 0xe0, 0x6d, 0xa3, 0x48, 0x17, 0x0c, 0xbc, 0x4a, 0x70, 0x42,
+	0xec, 0x55, 0x3c, 0xba, 0x45, 0xaa, 0xb2, 0x70, 0xc1, 0x97, 0x51, 0x95, 0xd5, 0x0a, 0x37, 0x17,
+	0x1c, 0x97, 0x20, 0x5d, 0xe0, 0xc8, 0xcb, 0xe8, 0x4e, 0x0a, 0xbc, 0x9a, 0x7e, 0xb1, 0xca, 0xb1,
+	0x4b, 0xae, 0xa4, 0xf7, 0x21, 0xef, 0xf1, 0xe6, 0xcf, 0xa3, 0x5d, 0xa4, 0x92, 0xfa, 0xbc, 0x2b,
+	0x4d, 0x51, 0xa9, 0xbb, 0xdd, 0x62, 0x7c, 0x90, 0xda, 0xfa, 0x67, 0x1a, 0x4a, 0xc6, 0x6c, 0x86,
+	0xb7, 0x4f, 0x3c, 0x69, 0x10, 0x02, 0xd5, 0x81, 0x79, 0x60, 0xf6, 0x8e, 0xcd, 0x21, 0x35, 0x1a,
+	0xfd, 0x9e, 0xa9, 0xad, 0x91, 0x0d, 0xd0, 0x3a, 0xe6, 0x51, 0xa3, 0xdb, 0x69, 0x0d, 0x1b, 0xb4,
+	0x3d, 0x38, 0x34, 0x4c, 0x4b, 0x4b, 0x91, 0x2a, 0x80, 0xd9, 0x1b, 0xf6, 0x8d, 0x7e, 0xbf, 0xd3,
+	0x33, 0xb5, 0x34, 0xa9, 0x40, 0xd1, 0xec, 0x0d, 0xdf, 0x76, 0x1b, 0xef, 0x0c, 0xaa, 0x65, 0xc8,
+	0x17, 0x50, 0x11, 0xe3, 0xa1, 0xf1, 0xfb, 0x4e, 0xdf, 0xea, 0x6b, 0x59, 0xa2, 0x41, 0x59, 0xc2,
+	0x87, 0x7b, 0x83, 0x6e, 0x57, 0xcb, 0x91, 0x7b, 0x50, 0x32, 0x7b, 0xd6, 0xb0, 0x6f, 0x35, 0xa8,
+	0x65, 0xb4, 0xb4, 0x3c, 0x4a, 0xb5, 0x1b, 0x87, 0xc6, 0x70, 0xaf, 0x63, 0x76, 0xfa, 0xfb, 0x46,
+	0x4b, 0x5b, 0x47, 0xcc, 0x31, 0xed, 0x99, 0xed, 0xe1, 0xdb, 0xfd, 0x46, 0xdf, 0xd0, 0x0a, 0x88,
+	0x41, 0xa1, 0x77, 0xbd, 0x01, 0x1d, 0x5a, 0x03, 0x6a, 0x6a, 0x45, 0xb4, 0x1a, 0x59, 0x8d, 0x81,
+	0xb5, 0xdf, 0xa3, 0x9d, 0xf7, 0x46, 0x4b, 0x03, 0x84, 0x45, 0x56, 0x5b, 0xbd, 0x03, 0xc3, 0xd4,
+	0x4a, 0xa8, 0x4a, 0xda, 0xd4, 0x32, 0x1a, 0x2d, 0xad, 0x8c, 0x2b, 0xb3, 0x1a, 0xb4, 0x6d, 0x58,
+	0x43, 0x14, 0xdf, 0xeb, 0x0d, 0xcc, 0x96, 0x56, 0x41, 0x98, 0xe4, 0x72, 0x58, 0x15, 0xd5, 0xd3,
+	0x41, 0xd7, 0x18, 0x1e, 0x75, 0x7a, 0xdd, 0x86, 0x85, 0xcb, 0xbd, 0x87, 0x20, 0x6e, 0x69, 0xb3,
+	0xdb, 0xeb, 0x1b, 0x2d, 0x4d, 0x53, 0x94, 0x77, 0x8d, 0x3d, 0x4b, 0xfb, 0x02, 0x0d, 0xe8, 0xef,
+	0x0f, 0x2c, 0xab, 0x63, 0xb6, 0x87, 0xad, 0xde, 0xb1, 0xa9, 0x91, 0xad, 0x17, 0x50, 0x88, 0x6e,
+	0xa4, 0x04, 0x20, 0xdf, 0x35, 0x1a, 0x2d, 0x83, 0x6a, 0x6b, 0xa4, 0x0c, 0x85, 0xc3, 0xc6, 0x9b,
+	0x1e, 0xed, 0x58, 0xef, 0xb4, 0x14, 0x7a, 0x72, 0x60, 0x36, 0xcc, 0xce, 0x61, 0x6f, 0xd0, 0xd7,
+	0xd2, 0x5b, 0xbf, 0x85, 0x6a, 0xf2, 0x2a, 0x4e, 0x4a, 0xb0, 0x6e, 0xf6, 0x86, 0x07, 0x9d, 0x6e,
+	0x57, 0x5b, 0x43, 0x3d, 0xb4, 0x61, 0xb6, 0x7a, 0x87, 0x5a, 0x0a, 0xf5, 0x34, 0xba, 0xdd, 0xa1,
+	0xd5, 0x31, 0x5a, 0x5c, 0xb0, 0x92, 0xb8, 0x47, 0xa0, 0xdc, 0x71, 0xa3, 0x83, 0x06, 0x69, 0x6b,
+	0x48, 0x44, 0x7e, 0xe7, 0x82, 0xb1, 0xcb, 0xd3, 0x5b, 0x7f, 0x49, 0x81, 0x76, 0xbd, 0xda, 0x49,
+	0x11, 0x72, 0x1c, 0x2f, 0x3e, 0x29, 0xd0, 0x5a, 0x8a, 0xdb, 0x62, 0x1c, 0x0f, 0x5b, 0x8d, 0x77,
+	0x32, 0x07, 0x8c, 0xe3, 0xa1, 0xd9, 0x69, 0xef, 0x5b, 0x5a, 0x86, 0x9b, 0x36, 0x30, 0x7b, 0x7b,
+	0x7b, 0x5a, 0x16, 0xa7, 0x8e, 0x7a, 0x96, 0x31, 0x6c, 0x36, 0xfa, 0x96, 0x96, 0x93, 0xda, 0x2c,
+	0x43, 0xcb, 0xa3, 0x06, 0x6a, 0x1c, 0x36, 0xac, 0xe6, 0xbe, 0xb6, 0x8e, 0x22, 0x66, 0xcf, 0xea,
+	0x34, 0x31, 0xd0, 0x65, 0x28, 0xa0, 0x03, 0xb9, 0xef, 0x8a, 0x3b, 0x7f, 0x03, 0xc8, 0xf1, 0x17,
+	0x27, 0xf2, 0x3d, 0x64, 0xf1, 0x42, 0x4e, 0x12, 0x27, 0x06, 0xe5, 0x8a, 0x5e, 0xaf, 0xdd, 0x9c,
+	0x10, 0x87, 0x5e, 0x7d, 0x8d, 0xfc, 0x20, 0x3b, 0xf0, 0x83, 0xe4, 0x5d, 0x23, 0xd9, 0xef, 0xeb,
+	0x89, 0x47, 0x1f, 0x45, 0x7a, 0x17, 0xa0, 0x79, 0x6e, 0x7b, 0x67, 0xec, 0xff, 0xd0, 0xf1, 0x23,
+	0x94, 0x8f, 0x9d, 0xf0, 0x7c, 0x3c, 0xb3, 0x3f, 0x70, 0x2d, 0x2b, 0xce, 0x12, 0x2b, 0xe5, 0x5f,
+	0x41, 0xa1, 0x3f, 0x71, 0xa6, 0x77, 0x92, 0xfd, 0x1e, 0x0a, 0x6d, 0x16, 0xa2, 0x68, 0xb0, 0x52,
+	0x76, 0xf9, 0x66, 0x26, 0x5c, 0x87, 0xd9, 0x7b, 0xc7, 0x65, 0x9b, 0xf8, 0x4e, 0xc8, 0x46, 0x93,
+	0xce, 0xa9, 0x88, 0xe2, 0xed, 0x5a, 0x1e, 0xaa, 0xb3, 0x37, 0xdf, 0x23, 0x85, 0x35, 0xfc, 0xad,
+	0xec, 0x6e, 0xd6, 0x08, 0x47, 0x88, 0x66, 0xf4, 0x49, 0x8e, 0x88, 0xdb, 0x98, 0xbe, 0x46, 0x5e,
+	0xc3, 0x7a, 0x53, 0xbc, 0x69, 0xae, 0x94, 0xad, 0x25, 0xed, 0x5f, 0xbc, 0x8f, 0xea, 0x6b, 0xe4,
+	0x37, 0x90, 0xfd, 0xdd, 0xdc, 0x09, 0x3f, 0x3b, 0x78, 0xaf, 0x61, 0x9d, 0x8a, 0x4e, 0xf6, 0x69,
+	0x9f, 0xbd, 0x96, 0xf9, 0x4d, 0x28, 0x44, 0x2f, 0x3c, 0xe4, 0xa7, 0x09, 0xa7, 0x25, 0xdf, 0x7d,
+	0x6e, 0x55, 0xf2, 0x06, 0xca, 0xea, 0x3b, 0x0d, 0x79, 0xa4, 0x62, 0x97, 0xbc, 0xe0, 0xd4, 0x97,
+	0xdd, 0xe9, 0x11, 0xa7, 0xaf, 0x91, 0x36, 0x54, 0x31, 0x06, 0xca, 0xdb, 0x4b, 0x7d, 0x09, 0xf8,
+	0x36, 0x45, 0x28, 0x24, 0x1c, 0x23, 0xdf, 0xc4, 0x92, 0x1a, 0x92, 0x0f, 0x65, 0x2b, 0xfd, 0x7a,
+	0xc0, 0xed, 0x50, 0x6f, 0xb1, 0x0f, 0x57, 0xdd, 0x45, 0x96, 0xd9, 0xa2, 0xcc, 0xc7, 0xca, 0xd4,
+	0x9b, 0x4e, 0x42, 0xd9, 0xcd, 0xab, 0x54, 0xfd, 0xab, 0x15, 0xf3, 0xbc, 0x66, 0xb4, 0x36, 0x0b,
+	0xd5, 0xad, 0x38, 0xb8, 0x96, 0xef, 0xd7, 0x6e, 0x3e, 0xc9, 0xd8, 0xa9, 0x82, 0xfa, 0xda, 0xf3,
+	0xd4, 0xce, 0xbf, 0xd2, 0x00, 0xbc, 0x8e, 0xf8, 0x7b, 0x07, 0xe9, 0x40, 0x11, 0x43, 0xc1, 0xef,
+	0x67, 0x1f, 0x8f, 0xe4, 0xd7, 0x4b, 0x1f, 0x51, 0x64, 0x2c, 0x0d, 0x00, 0x71, 0x44, 0x46, 0x1e,
+	0x79, 0x74, 0x03, 0x7a, 0x2d, 0x98, 0xab, 0x42, 0xb1, 0x0f, 0x95, 0x16, 0x73, 0x59, 0xc8, 0x24,
+	0xfe, 0xee, 0x9a, 0x5e, 0x03, 0xe0, 0x5b, 0x8c, 0x3c, 0x33, 0x7e, 0x95, 0x7c, 0xc4, 0x1f, 0x4d,
+	0x3e, 0x26, 0xde, 0x86, 0x62, 0xfc, 0x64, 0x42, 0xbe, 0xbe, 0xee, 0xd4, 0xf8, 0x21, 0xa6, 0xfe,
+	0x33, 0x75, 0xea, 0xc6, 0x23, 0x8b, 0xbe, 0xb6, 0x5b, 0x7f, 0x5f, 0x0b, 0x7c, 0x7b, 0xc8, 0x51,
+	0xcf, 0x92, 0x7f, 0x7c, 0x4e, 0xf2, 0xfc, 0x3f, 0xcf, 0x8b, 0xff, 0x0d, 0x00, 0xce, 0x83, 0x82,
+	0x6b, 0x0a, 0x1a, 0x00, 0x00,
 }
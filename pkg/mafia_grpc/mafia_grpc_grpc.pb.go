@@ -22,14 +22,26 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type MafiaClient interface {
-	Join(ctx context.Context, in *JoinRequest, opts ...grpc.CallOption) (*Response, error)
+	Join(ctx context.Context, in *JoinRequest, opts ...grpc.CallOption) (*JoinResponse, error)
 	Vote(ctx context.Context, in *SetVictimRequest, opts ...grpc.CallOption) (*Response, error)
+	ChangeVote(ctx context.Context, in *SetVictimRequest, opts ...grpc.CallOption) (*Response, error)
+	WithdrawVote(ctx context.Context, in *PlayerInfo, opts ...grpc.CallOption) (*Response, error)
+	SkipVote(ctx context.Context, in *PlayerInfo, opts ...grpc.CallOption) (*Response, error)
+	GetVotes(ctx context.Context, in *PlayerInfo, opts ...grpc.CallOption) (*VoteTally, error)
 	Kill(ctx context.Context, in *SetVictimRequest, opts ...grpc.CallOption) (*Response, error)
 	CheckIfMafia(ctx context.Context, in *SetVictimRequest, opts ...grpc.CallOption) (*CheckMafiaResponse, error)
+	Heal(ctx context.Context, in *SetVictimRequest, opts ...grpc.CallOption) (*Response, error)
 	GetState(ctx context.Context, in *PlayerInfo, opts ...grpc.CallOption) (*GameState, error)
 	CanChat(ctx context.Context, in *PlayerInfo, opts ...grpc.CallOption) (*ChatResponse, error)
 	Quit(ctx context.Context, in *PlayerInfo, opts ...grpc.CallOption) (*Response, error)
-	GetNotifications(ctx context.Context, in *PlayerInfo, opts ...grpc.CallOption) (Mafia_GetNotificationsClient, error)
+	Rematch(ctx context.Context, in *PlayerInfo, opts ...grpc.CallOption) (*JoinResponse, error)
+	Spectate(ctx context.Context, in *SpectateRequest, opts ...grpc.CallOption) (*JoinResponse, error)
+	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*SessionList, error)
+	GetSessionInfo(ctx context.Context, in *SessionRequest, opts ...grpc.CallOption) (*SessionInfo, error)
+	AddBots(ctx context.Context, in *AddBotsRequest, opts ...grpc.CallOption) (*Response, error)
+	GetPlayerStats(ctx context.Context, in *PlayerStatsRequest, opts ...grpc.CallOption) (*PlayerStats, error)
+	GetLeaderboard(ctx context.Context, in *LeaderboardRequest, opts ...grpc.CallOption) (*Leaderboard, error)
+	GetNotifications(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Mafia_GetNotificationsClient, error)
 }
 
 type mafiaClient struct {
@@ -40,8 +52,8 @@ func NewMafiaClient(cc grpc.ClientConnInterface) MafiaClient {
 	return &mafiaClient{cc}
 }
 
-func (c *mafiaClient) Join(ctx context.Context, in *JoinRequest, opts ...grpc.CallOption) (*Response, error) {
-	out := new(Response)
+func (c *mafiaClient) Join(ctx context.Context, in *JoinRequest, opts ...grpc.CallOption) (*JoinResponse, error) {
+	out := new(JoinResponse)
 	err := c.cc.Invoke(ctx, "/mafia_grpc.Mafia/Join", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -58,6 +70,42 @@ func (c *mafiaClient) Vote(ctx context.Context, in *SetVictimRequest, opts ...gr
 	return out, nil
 }
 
+func (c *mafiaClient) ChangeVote(ctx context.Context, in *SetVictimRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/mafia_grpc.Mafia/ChangeVote", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) WithdrawVote(ctx context.Context, in *PlayerInfo, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/mafia_grpc.Mafia/WithdrawVote", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) SkipVote(ctx context.Context, in *PlayerInfo, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/mafia_grpc.Mafia/SkipVote", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) GetVotes(ctx context.Context, in *PlayerInfo, opts ...grpc.CallOption) (*VoteTally, error) {
+	out := new(VoteTally)
+	err := c.cc.Invoke(ctx, "/mafia_grpc.Mafia/GetVotes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *mafiaClient) Kill(ctx context.Context, in *SetVictimRequest, opts ...grpc.CallOption) (*Response, error) {
 	out := new(Response)
 	err := c.cc.Invoke(ctx, "/mafia_grpc.Mafia/Kill", in, out, opts...)
@@ -76,6 +124,15 @@ func (c *mafiaClient) CheckIfMafia(ctx context.Context, in *SetVictimRequest, op
 	return out, nil
 }
 
+func (c *mafiaClient) Heal(ctx context.Context, in *SetVictimRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/mafia_grpc.Mafia/Heal", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *mafiaClient) GetState(ctx context.Context, in *PlayerInfo, opts ...grpc.CallOption) (*GameState, error) {
 	out := new(GameState)
 	err := c.cc.Invoke(ctx, "/mafia_grpc.Mafia/GetState", in, out, opts...)
@@ -103,7 +160,70 @@ func (c *mafiaClient) Quit(ctx context.Context, in *PlayerInfo, opts ...grpc.Cal
 	return out, nil
 }
 
-func (c *mafiaClient) GetNotifications(ctx context.Context, in *PlayerInfo, opts ...grpc.CallOption) (Mafia_GetNotificationsClient, error) {
+func (c *mafiaClient) Rematch(ctx context.Context, in *PlayerInfo, opts ...grpc.CallOption) (*JoinResponse, error) {
+	out := new(JoinResponse)
+	err := c.cc.Invoke(ctx, "/mafia_grpc.Mafia/Rematch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) Spectate(ctx context.Context, in *SpectateRequest, opts ...grpc.CallOption) (*JoinResponse, error) {
+	out := new(JoinResponse)
+	err := c.cc.Invoke(ctx, "/mafia_grpc.Mafia/Spectate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*SessionList, error) {
+	out := new(SessionList)
+	err := c.cc.Invoke(ctx, "/mafia_grpc.Mafia/ListSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) GetSessionInfo(ctx context.Context, in *SessionRequest, opts ...grpc.CallOption) (*SessionInfo, error) {
+	out := new(SessionInfo)
+	err := c.cc.Invoke(ctx, "/mafia_grpc.Mafia/GetSessionInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) AddBots(ctx context.Context, in *AddBotsRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/mafia_grpc.Mafia/AddBots", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) GetPlayerStats(ctx context.Context, in *PlayerStatsRequest, opts ...grpc.CallOption) (*PlayerStats, error) {
+	out := new(PlayerStats)
+	err := c.cc.Invoke(ctx, "/mafia_grpc.Mafia/GetPlayerStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) GetLeaderboard(ctx context.Context, in *LeaderboardRequest, opts ...grpc.CallOption) (*Leaderboard, error) {
+	out := new(Leaderboard)
+	err := c.cc.Invoke(ctx, "/mafia_grpc.Mafia/GetLeaderboard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) GetNotifications(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Mafia_GetNotificationsClient, error) {
 	stream, err := c.cc.NewStream(ctx, &Mafia_ServiceDesc.Streams[0], "/mafia_grpc.Mafia/GetNotifications", opts...)
 	if err != nil {
 		return nil, err
@@ -139,14 +259,26 @@ func (x *mafiaGetNotificationsClient) Recv() (*Notification, error) {
 // All implementations must embed UnimplementedMafiaServer
 // for forward compatibility
 type MafiaServer interface {
-	Join(context.Context, *JoinRequest) (*Response, error)
+	Join(context.Context, *JoinRequest) (*JoinResponse, error)
 	Vote(context.Context, *SetVictimRequest) (*Response, error)
+	ChangeVote(context.Context, *SetVictimRequest) (*Response, error)
+	WithdrawVote(context.Context, *PlayerInfo) (*Response, error)
+	SkipVote(context.Context, *PlayerInfo) (*Response, error)
+	GetVotes(context.Context, *PlayerInfo) (*VoteTally, error)
 	Kill(context.Context, *SetVictimRequest) (*Response, error)
 	CheckIfMafia(context.Context, *SetVictimRequest) (*CheckMafiaResponse, error)
+	Heal(context.Context, *SetVictimRequest) (*Response, error)
 	GetState(context.Context, *PlayerInfo) (*GameState, error)
 	CanChat(context.Context, *PlayerInfo) (*ChatResponse, error)
 	Quit(context.Context, *PlayerInfo) (*Response, error)
-	GetNotifications(*PlayerInfo, Mafia_GetNotificationsServer) error
+	Rematch(context.Context, *PlayerInfo) (*JoinResponse, error)
+	Spectate(context.Context, *SpectateRequest) (*JoinResponse, error)
+	ListSessions(context.Context, *ListSessionsRequest) (*SessionList, error)
+	GetSessionInfo(context.Context, *SessionRequest) (*SessionInfo, error)
+	AddBots(context.Context, *AddBotsRequest) (*Response, error)
+	GetPlayerStats(context.Context, *PlayerStatsRequest) (*PlayerStats, error)
+	GetLeaderboard(context.Context, *LeaderboardRequest) (*Leaderboard, error)
+	GetNotifications(*SubscribeRequest, Mafia_GetNotificationsServer) error
 	mustEmbedUnimplementedMafiaServer()
 }
 
@@ -154,18 +286,33 @@ type MafiaServer interface {
 type UnimplementedMafiaServer struct {
 }
 
-func (UnimplementedMafiaServer) Join(context.Context, *JoinRequest) (*Response, error) {
+func (UnimplementedMafiaServer) Join(context.Context, *JoinRequest) (*JoinResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Join not implemented")
 }
 func (UnimplementedMafiaServer) Vote(context.Context, *SetVictimRequest) (*Response, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Vote not implemented")
 }
+func (UnimplementedMafiaServer) ChangeVote(context.Context, *SetVictimRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeVote not implemented")
+}
+func (UnimplementedMafiaServer) WithdrawVote(context.Context, *PlayerInfo) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WithdrawVote not implemented")
+}
+func (UnimplementedMafiaServer) SkipVote(context.Context, *PlayerInfo) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SkipVote not implemented")
+}
+func (UnimplementedMafiaServer) GetVotes(context.Context, *PlayerInfo) (*VoteTally, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVotes not implemented")
+}
 func (UnimplementedMafiaServer) Kill(context.Context, *SetVictimRequest) (*Response, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Kill not implemented")
 }
 func (UnimplementedMafiaServer) CheckIfMafia(context.Context, *SetVictimRequest) (*CheckMafiaResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CheckIfMafia not implemented")
 }
+func (UnimplementedMafiaServer) Heal(context.Context, *SetVictimRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Heal not implemented")
+}
 func (UnimplementedMafiaServer) GetState(context.Context, *PlayerInfo) (*GameState, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetState not implemented")
 }
@@ -175,7 +322,28 @@ func (UnimplementedMafiaServer) CanChat(context.Context, *PlayerInfo) (*ChatResp
 func (UnimplementedMafiaServer) Quit(context.Context, *PlayerInfo) (*Response, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Quit not implemented")
 }
-func (UnimplementedMafiaServer) GetNotifications(*PlayerInfo, Mafia_GetNotificationsServer) error {
+func (UnimplementedMafiaServer) Rematch(context.Context, *PlayerInfo) (*JoinResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rematch not implemented")
+}
+func (UnimplementedMafiaServer) Spectate(context.Context, *SpectateRequest) (*JoinResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Spectate not implemented")
+}
+func (UnimplementedMafiaServer) ListSessions(context.Context, *ListSessionsRequest) (*SessionList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedMafiaServer) GetSessionInfo(context.Context, *SessionRequest) (*SessionInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSessionInfo not implemented")
+}
+func (UnimplementedMafiaServer) AddBots(context.Context, *AddBotsRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddBots not implemented")
+}
+func (UnimplementedMafiaServer) GetPlayerStats(context.Context, *PlayerStatsRequest) (*PlayerStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPlayerStats not implemented")
+}
+func (UnimplementedMafiaServer) GetLeaderboard(context.Context, *LeaderboardRequest) (*Leaderboard, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLeaderboard not implemented")
+}
+func (UnimplementedMafiaServer) GetNotifications(*SubscribeRequest, Mafia_GetNotificationsServer) error {
 	return status.Errorf(codes.Unimplemented, "method GetNotifications not implemented")
 }
 func (UnimplementedMafiaServer) mustEmbedUnimplementedMafiaServer() {}
@@ -227,6 +395,78 @@ func _Mafia_Vote_Handler(srv interface{}, ctx context.Context, dec func(interfac
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Mafia_ChangeVote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetVictimRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).ChangeVote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mafia_grpc.Mafia/ChangeVote",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).ChangeVote(ctx, req.(*SetVictimRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_WithdrawVote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerInfo)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).WithdrawVote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mafia_grpc.Mafia/WithdrawVote",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).WithdrawVote(ctx, req.(*PlayerInfo))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_SkipVote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerInfo)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).SkipVote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mafia_grpc.Mafia/SkipVote",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).SkipVote(ctx, req.(*PlayerInfo))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_GetVotes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerInfo)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).GetVotes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mafia_grpc.Mafia/GetVotes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).GetVotes(ctx, req.(*PlayerInfo))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Mafia_Kill_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SetVictimRequest)
 	if err := dec(in); err != nil {
@@ -263,6 +503,24 @@ func _Mafia_CheckIfMafia_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Mafia_Heal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetVictimRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).Heal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mafia_grpc.Mafia/Heal",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).Heal(ctx, req.(*SetVictimRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Mafia_GetState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PlayerInfo)
 	if err := dec(in); err != nil {
@@ -317,8 +575,134 @@ func _Mafia_Quit_Handler(srv interface{}, ctx context.Context, dec func(interfac
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Mafia_Rematch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerInfo)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).Rematch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mafia_grpc.Mafia/Rematch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).Rematch(ctx, req.(*PlayerInfo))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_Spectate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpectateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).Spectate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mafia_grpc.Mafia/Spectate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).Spectate(ctx, req.(*SpectateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mafia_grpc.Mafia/ListSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).ListSessions(ctx, req.(*ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_GetSessionInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).GetSessionInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mafia_grpc.Mafia/GetSessionInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).GetSessionInfo(ctx, req.(*SessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_AddBots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddBotsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).AddBots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mafia_grpc.Mafia/AddBots",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).AddBots(ctx, req.(*AddBotsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_GetPlayerStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).GetPlayerStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mafia_grpc.Mafia/GetPlayerStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).GetPlayerStats(ctx, req.(*PlayerStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_GetLeaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaderboardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).GetLeaderboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mafia_grpc.Mafia/GetLeaderboard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).GetLeaderboard(ctx, req.(*LeaderboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Mafia_GetNotifications_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(PlayerInfo)
+	m := new(SubscribeRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
@@ -353,6 +737,22 @@ var Mafia_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Vote",
 			Handler:    _Mafia_Vote_Handler,
 		},
+		{
+			MethodName: "ChangeVote",
+			Handler:    _Mafia_ChangeVote_Handler,
+		},
+		{
+			MethodName: "WithdrawVote",
+			Handler:    _Mafia_WithdrawVote_Handler,
+		},
+		{
+			MethodName: "SkipVote",
+			Handler:    _Mafia_SkipVote_Handler,
+		},
+		{
+			MethodName: "GetVotes",
+			Handler:    _Mafia_GetVotes_Handler,
+		},
 		{
 			MethodName: "Kill",
 			Handler:    _Mafia_Kill_Handler,
@@ -361,6 +761,10 @@ var Mafia_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CheckIfMafia",
 			Handler:    _Mafia_CheckIfMafia_Handler,
 		},
+		{
+			MethodName: "Heal",
+			Handler:    _Mafia_Heal_Handler,
+		},
 		{
 			MethodName: "GetState",
 			Handler:    _Mafia_GetState_Handler,
@@ -373,6 +777,34 @@ var Mafia_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Quit",
 			Handler:    _Mafia_Quit_Handler,
 		},
+		{
+			MethodName: "Rematch",
+			Handler:    _Mafia_Rematch_Handler,
+		},
+		{
+			MethodName: "Spectate",
+			Handler:    _Mafia_Spectate_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _Mafia_ListSessions_Handler,
+		},
+		{
+			MethodName: "GetSessionInfo",
+			Handler:    _Mafia_GetSessionInfo_Handler,
+		},
+		{
+			MethodName: "AddBots",
+			Handler:    _Mafia_AddBots_Handler,
+		},
+		{
+			MethodName: "GetPlayerStats",
+			Handler:    _Mafia_GetPlayerStats_Handler,
+		},
+		{
+			MethodName: "GetLeaderboard",
+			Handler:    _Mafia_GetLeaderboard_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -383,3 +815,233 @@ var Mafia_ServiceDesc = grpc.ServiceDesc{
 	},
 	Metadata: "pkg/mafia_grpc/mafia_grpc.proto",
 }
+
+// MafiaAdminClient is the client API for MafiaAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MafiaAdminClient interface {
+	ListGames(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*AdminGameList, error)
+	FinishGame(ctx context.Context, in *AdminSessionRequest, opts ...grpc.CallOption) (*Response, error)
+	DeleteSession(ctx context.Context, in *AdminSessionRequest, opts ...grpc.CallOption) (*Response, error)
+	KickPlayer(ctx context.Context, in *KickRequest, opts ...grpc.CallOption) (*Response, error)
+	Broadcast(ctx context.Context, in *NoticeRequest, opts ...grpc.CallOption) (*BroadcastResponse, error)
+}
+
+type mafiaAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMafiaAdminClient(cc grpc.ClientConnInterface) MafiaAdminClient {
+	return &mafiaAdminClient{cc}
+}
+
+func (c *mafiaAdminClient) ListGames(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*AdminGameList, error) {
+	out := new(AdminGameList)
+	err := c.cc.Invoke(ctx, "/mafia_grpc.MafiaAdmin/ListGames", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaAdminClient) FinishGame(ctx context.Context, in *AdminSessionRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/mafia_grpc.MafiaAdmin/FinishGame", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaAdminClient) DeleteSession(ctx context.Context, in *AdminSessionRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/mafia_grpc.MafiaAdmin/DeleteSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaAdminClient) KickPlayer(ctx context.Context, in *KickRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/mafia_grpc.MafiaAdmin/KickPlayer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaAdminClient) Broadcast(ctx context.Context, in *NoticeRequest, opts ...grpc.CallOption) (*BroadcastResponse, error) {
+	out := new(BroadcastResponse)
+	err := c.cc.Invoke(ctx, "/mafia_grpc.MafiaAdmin/Broadcast", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MafiaAdminServer is the server API for MafiaAdmin service.
+// All implementations must embed UnimplementedMafiaAdminServer
+// for forward compatibility
+type MafiaAdminServer interface {
+	ListGames(context.Context, *ListSessionsRequest) (*AdminGameList, error)
+	FinishGame(context.Context, *AdminSessionRequest) (*Response, error)
+	DeleteSession(context.Context, *AdminSessionRequest) (*Response, error)
+	KickPlayer(context.Context, *KickRequest) (*Response, error)
+	Broadcast(context.Context, *NoticeRequest) (*BroadcastResponse, error)
+	mustEmbedUnimplementedMafiaAdminServer()
+}
+
+// UnimplementedMafiaAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedMafiaAdminServer struct {
+}
+
+func (UnimplementedMafiaAdminServer) ListGames(context.Context, *ListSessionsRequest) (*AdminGameList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGames not implemented")
+}
+func (UnimplementedMafiaAdminServer) FinishGame(context.Context, *AdminSessionRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FinishGame not implemented")
+}
+func (UnimplementedMafiaAdminServer) DeleteSession(context.Context, *AdminSessionRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSession not implemented")
+}
+func (UnimplementedMafiaAdminServer) KickPlayer(context.Context, *KickRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method KickPlayer not implemented")
+}
+func (UnimplementedMafiaAdminServer) Broadcast(context.Context, *NoticeRequest) (*BroadcastResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Broadcast not implemented")
+}
+func (UnimplementedMafiaAdminServer) mustEmbedUnimplementedMafiaAdminServer() {}
+
+// UnsafeMafiaAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MafiaAdminServer will
+// result in compilation errors.
+type UnsafeMafiaAdminServer interface {
+	mustEmbedUnimplementedMafiaAdminServer()
+}
+
+func RegisterMafiaAdminServer(s grpc.ServiceRegistrar, srv MafiaAdminServer) {
+	s.RegisterService(&MafiaAdmin_ServiceDesc, srv)
+}
+
+func _MafiaAdmin_ListGames_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaAdminServer).ListGames(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mafia_grpc.MafiaAdmin/ListGames",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaAdminServer).ListGames(ctx, req.(*ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MafiaAdmin_FinishGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaAdminServer).FinishGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mafia_grpc.MafiaAdmin/FinishGame",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaAdminServer).FinishGame(ctx, req.(*AdminSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MafiaAdmin_DeleteSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaAdminServer).DeleteSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mafia_grpc.MafiaAdmin/DeleteSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaAdminServer).DeleteSession(ctx, req.(*AdminSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MafiaAdmin_KickPlayer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KickRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaAdminServer).KickPlayer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mafia_grpc.MafiaAdmin/KickPlayer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaAdminServer).KickPlayer(ctx, req.(*KickRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MafiaAdmin_Broadcast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NoticeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaAdminServer).Broadcast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mafia_grpc.MafiaAdmin/Broadcast",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaAdminServer).Broadcast(ctx, req.(*NoticeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MafiaAdmin_ServiceDesc is the grpc.ServiceDesc for MafiaAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MafiaAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "mafia_grpc.MafiaAdmin",
+	HandlerType: (*MafiaAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListGames",
+			Handler:    _MafiaAdmin_ListGames_Handler,
+		},
+		{
+			MethodName: "FinishGame",
+			Handler:    _MafiaAdmin_FinishGame_Handler,
+		},
+		{
+			MethodName: "DeleteSession",
+			Handler:    _MafiaAdmin_DeleteSession_Handler,
+		},
+		{
+			MethodName: "KickPlayer",
+			Handler:    _MafiaAdmin_KickPlayer_Handler,
+		},
+		{
+			MethodName: "Broadcast",
+			Handler:    _MafiaAdmin_Broadcast_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/mafia_grpc/mafia_grpc.proto",
+}
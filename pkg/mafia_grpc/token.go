@@ -0,0 +1,54 @@
+package mafia_grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+)
+
+/*
+	Token returned by Join, Spectate and Rematch is sent in this metadata key
+	with every call made on behalf of the player
+*/
+const TokenMetadataKey = "mafia-token"
+
+/*
+	Token of the administrator is sent in this key with every call of MafiaAdmin
+*/
+const AdminTokenMetadataKey = "mafia-admin-token"
+
+/*
+	Adds the token of the player to the outgoing call
+*/
+func WithToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, TokenMetadataKey, token)
+}
+
+/*
+	Returns the token of the incoming call, empty if there is none
+*/
+func TokenFromContext(ctx context.Context) string {
+	return fromIncomingContext(ctx, TokenMetadataKey)
+}
+
+func WithAdminToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, AdminTokenMetadataKey, token)
+}
+
+func AdminTokenFromContext(ctx context.Context) string {
+	return fromIncomingContext(ctx, AdminTokenMetadataKey)
+}
+
+func fromIncomingContext(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
@@ -0,0 +1,418 @@
+package mafia_bot
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math/rand"
+	"soa_mafia/pkg/mafia_grpc"
+	mafia_impl "soa_mafia/server/mafia_impl"
+	"sort"
+	"time"
+)
+
+const NamePrefix = "Bot"
+
+/*
+	Delay is the longest pause before a move, so bots don't answer instantly.
+	Seed makes the choices of the bots repeatable, zero means a random seed
+*/
+type Options struct {
+	Delay time.Duration
+	Seed  int64
+}
+
+/*
+	Bot plays inside the server against the Game API. It knows only what a player
+	with its role knows: the notifications of the game and the results of its own actions.
+	Suspicion grows for players who voted against the bot, against players known
+	to be innocent and for the night victims on the previous days
+*/
+type Bot struct {
+	game    *mafia_impl.Game
+	name    string
+	options Options
+	rng     *rand.Rand
+
+	role       mafia_impl.Role
+	mafiaTeam  map[string]bool
+	checked    map[string]bool
+	lastHealed string
+
+	suspicion map[string]int
+	dayVotes  map[string]string
+	pastVotes map[string][]string
+}
+
+/*
+	Adds bots to the empty seats of the game and starts them, returns names of the added bots
+*/
+func Fill(game *mafia_impl.Game, count int32, options Options) ([]string, error) {
+	names := []string{}
+	for i := 1; int32(len(names)) < count; i++ {
+		name := fmt.Sprintf("%s-%d", NamePrefix, i)
+		token, err := game.AddBot(name)
+		if errors.Is(err, mafia_impl.ErrPlayerExists) {
+			continue
+		}
+
+		if err != nil {
+			return names, err
+		}
+
+		err = Start(game, name, token, options)
+		if err != nil {
+			return names, err
+		}
+
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+/*
+	Connects the bot to the game and runs it until the game closes its notifications
+*/
+func Start(game *mafia_impl.Game, name string, token string, options Options) error {
+	notifications, snapshot, err := game.GetNotifications(name, token)
+	if err != nil {
+		return err
+	}
+
+	seed := options.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	bot := &Bot{
+		game:      game,
+		name:      name,
+		options:   options,
+		rng:       rand.New(rand.NewSource(seed + int64(hash(name)))),
+		mafiaTeam: make(map[string]bool),
+		checked:   make(map[string]bool),
+		suspicion: make(map[string]int),
+		dayVotes:  make(map[string]string),
+		pastVotes: make(map[string][]string),
+	}
+
+	go bot.run(snapshot, notifications)
+	return nil
+}
+
+func (b *Bot) run(snapshot *mafia_grpc.Notification, notifications <-chan *mafia_grpc.Notification) {
+	b.handle(snapshot)
+	for notification := range notifications {
+		b.handle(notification)
+	}
+
+	log.Printf("Bot %s: stopped", b.name)
+}
+
+func (b *Bot) handle(notification *mafia_grpc.Notification) {
+	state := notification.GetGameState()
+
+	switch notification.Type {
+	case mafia_grpc.NotificationType_STATE, mafia_grpc.NotificationType_START:
+		b.learnRole(notification)
+		b.act(state)
+	case mafia_grpc.NotificationType_NEW_NIGHT:
+		b.endDay()
+		b.act(state)
+	case mafia_grpc.NotificationType_NEW_DAY:
+		b.learnNightVictim(notification.GetKilledPlayer())
+		b.act(state)
+	case mafia_grpc.NotificationType_RUNOFF:
+		b.act(state)
+	case mafia_grpc.NotificationType_VOTE_CAST:
+		b.learnVotes(notification.GetVoteTally())
+	}
+}
+
+func (b *Bot) learnRole(notification *mafia_grpc.Notification) {
+	if notification.GetRole() != "" {
+		b.role = mafia_impl.Role(notification.GetRole())
+	}
+
+	for _, mafia := range notification.GetMafiaTeam() {
+		b.mafiaTeam[mafia] = true
+	}
+}
+
+/*
+	Open ballot shows who votes for whom. Voting against the bot
+	or against a player the detective knows to be innocent is suspicious
+*/
+func (b *Bot) learnVotes(tally *mafia_grpc.VoteTally) {
+	for _, vote := range tally.GetVotes() {
+		if b.dayVotes[vote.Voter] == vote.Victim {
+			continue
+		}
+
+		b.dayVotes[vote.Voter] = vote.Victim
+		if vote.Victim == b.name {
+			b.suspicion[vote.Voter]++
+		}
+
+		if isMafia, checked := b.checked[vote.Victim]; checked && !isMafia {
+			b.suspicion[vote.Voter]++
+		}
+	}
+}
+
+func (b *Bot) endDay() {
+	for voter, victim := range b.dayVotes {
+		b.pastVotes[voter] = append(b.pastVotes[voter], victim)
+	}
+
+	b.dayVotes = make(map[string]string)
+}
+
+/*
+	Night victim was killed by mafia, so it was innocent
+	and those who wanted to execute it are suspicious
+*/
+func (b *Bot) learnNightVictim(victim string) {
+	if victim == "" {
+		return
+	}
+
+	for voter, victims := range b.pastVotes {
+		for _, past := range victims {
+			if past == victim {
+				b.suspicion[voter] += 2
+			}
+		}
+	}
+}
+
+/*
+	Makes the move of the current phase after a pause. The move is skipped
+	if the phase has changed during the pause, the next notification brings the new one
+*/
+func (b *Bot) act(state *mafia_grpc.GameState) {
+	if !state.GetIsStarted() || state.GetIsFinished() || !contains(state.GetAlivePlayers(), b.name) {
+		return
+	}
+
+	if b.options.Delay > 0 {
+		time.Sleep(time.Duration(b.rng.Int63n(int64(b.options.Delay))))
+	}
+
+	current := b.game.GetGameState()
+	if current.Date != state.Date || current.IsDay != state.IsDay || current.IsRunoff != state.IsRunoff || current.IsFinished {
+		return
+	}
+
+	var err error
+	if current.IsDay {
+		err = b.vote(current)
+	} else {
+		err = b.actAtNight(current)
+	}
+
+	if err != nil {
+		log.Printf("Bot %s: %v", b.name, err)
+	}
+}
+
+func (b *Bot) vote(state *mafia_grpc.GameState) error {
+	candidates := state.GetAlivePlayers()
+	if state.IsRunoff {
+		candidates = state.GetRunoffCandidates()
+	}
+
+	candidates = b.exclude(candidates, b.name)
+	if b.role == mafia_impl.Mafia {
+		return b.voteAsMafia(candidates)
+	}
+
+	for _, candidate := range candidates {
+		if b.checked[candidate] {
+			return b.game.AddVote(b.name, candidate)
+		}
+	}
+
+	suspects := []string{}
+	for _, candidate := range candidates {
+		if isMafia, checked := b.checked[candidate]; !checked || isMafia {
+			suspects = append(suspects, candidate)
+		}
+	}
+
+	if len(suspects) > 0 {
+		candidates = suspects
+	}
+
+	if len(candidates) == 0 {
+		return b.game.SkipVote(b.name)
+	}
+
+	return b.game.AddVote(b.name, b.mostSuspicious(candidates))
+}
+
+/*
+	Mafia joins the leading vote against a civilian, so the day doesn't end with a tie
+*/
+func (b *Bot) voteAsMafia(candidates []string) error {
+	civilians := b.excludeMafia(candidates)
+	if len(civilians) == 0 {
+		return b.game.SkipVote(b.name)
+	}
+
+	tally, err := b.game.GetVotes(b.name)
+	if err != nil {
+		return err
+	}
+
+	target := civilians[b.rng.Intn(len(civilians))]
+	for _, civilian := range civilians {
+		if tally.Counts[civilian] > tally.Counts[target] {
+			target = civilian
+		}
+	}
+
+	return b.game.AddVote(b.name, target)
+}
+
+func (b *Bot) actAtNight(state *mafia_grpc.GameState) error {
+	alive := state.GetAlivePlayers()
+
+	switch b.role {
+	case mafia_impl.Mafia:
+		civilians := b.excludeMafia(alive)
+		if len(civilians) == 0 {
+			return nil
+		}
+
+		return b.game.KillPlayer(b.name, b.mafiaTarget(civilians, state))
+	case mafia_impl.Detective:
+		candidates := []string{}
+		for _, player := range b.exclude(alive, b.name) {
+			if _, checked := b.checked[player]; !checked {
+				candidates = append(candidates, player)
+			}
+		}
+
+		// The night doesn't end until the detective checks someone
+		if len(candidates) == 0 {
+			candidates = b.exclude(alive, b.name)
+		}
+
+		target := candidates[b.rng.Intn(len(candidates))]
+		isMafia, err := b.game.CheckIfMafia(b.name, target)
+		if err != nil {
+			return err
+		}
+
+		b.checked[target] = isMafia
+	case mafia_impl.Doctor:
+		config := b.game.Config()
+		candidates := alive
+		if !config.DoctorSelfHeal {
+			candidates = b.exclude(candidates, b.name)
+		}
+
+		if !config.DoctorRepeatHeal {
+			candidates = b.exclude(candidates, b.lastHealed)
+		}
+
+		// The night doesn't wait for the doctor who has nobody to heal
+		if len(candidates) == 0 {
+			return nil
+		}
+
+		patient := candidates[b.rng.Intn(len(candidates))]
+		err := b.game.HealPlayer(b.name, patient)
+		if err != nil {
+			return err
+		}
+
+		b.lastHealed = patient
+	}
+
+	return nil
+}
+
+/*
+	Mafia bots don't talk to each other, so every one of them makes the same choice:
+	the civilian who voted against the mafia most, ties are broken by the hash of the night
+*/
+func (b *Bot) mafiaTarget(civilians []string, state *mafia_grpc.GameState) string {
+	threat := make(map[string]int)
+	for voter, victims := range b.pastVotes {
+		for _, victim := range victims {
+			if b.mafiaTeam[victim] {
+				threat[voter]++
+			}
+		}
+	}
+
+	night := fmt.Sprintf("%s/%d", state.GameId, state.Date)
+	sort.Slice(civilians, func(i, j int) bool {
+		if threat[civilians[i]] != threat[civilians[j]] {
+			return threat[civilians[i]] > threat[civilians[j]]
+		}
+
+		return hash(night+civilians[i]) < hash(night+civilians[j])
+	})
+
+	return civilians[0]
+}
+
+func (b *Bot) mostSuspicious(candidates []string) string {
+	best := []string{}
+	for _, candidate := range candidates {
+		if len(best) > 0 && b.suspicion[candidate] < b.suspicion[best[0]] {
+			continue
+		}
+
+		if len(best) > 0 && b.suspicion[candidate] > b.suspicion[best[0]] {
+			best = best[:0]
+		}
+
+		best = append(best, candidate)
+	}
+
+	return best[b.rng.Intn(len(best))]
+}
+
+func (b *Bot) excludeMafia(players []string) []string {
+	result := []string{}
+	for _, player := range players {
+		if !b.mafiaTeam[player] {
+			result = append(result, player)
+		}
+	}
+
+	return result
+}
+
+func (b *Bot) exclude(players []string, excluded string) []string {
+	result := []string{}
+	for _, player := range players {
+		if player != excluded {
+			result = append(result, player)
+		}
+	}
+
+	return result
+}
+
+func contains(slice []string, str string) bool {
+	for _, value := range slice {
+		if value == str {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hash(str string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(str))
+	return h.Sum32()
+}
@@ -0,0 +1,90 @@
+package mafia_bot
+
+import (
+	"io"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	mafia_impl "soa_mafia/server/mafia_impl"
+)
+
+/*
+	Game logs every call, it's too much for the tests
+*/
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+/*
+	Games have no phase timers, so they end only by the moves of the bots.
+	Seeds of the deal and of the bots are fixed, the order of the moves
+	of different bots still depends on the scheduler
+*/
+func TestBotsPlayTheGameToTheEnd(t *testing.T) {
+	for seed := int64(1); seed <= 10; seed++ {
+		config := mafia_impl.NewConfig(8)
+		config.Roles = map[mafia_impl.Role]int32{mafia_impl.Mafia: 2, mafia_impl.Detective: 1, mafia_impl.Doctor: 1}
+		config.Seed = seed
+		game := mafia_impl.NewGame("bots", config)
+
+		finished := make(chan mafia_impl.GameResult, 1)
+		game.OnFinish(func(result mafia_impl.GameResult) {
+			finished <- result
+		})
+
+		names, err := Fill(game, config.Players, Options{Seed: seed})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(names) != int(config.Players) || len(game.Bots()) != int(config.Players) {
+			t.Fatalf("All %d seats must be taken by bots, got %v", config.Players, names)
+		}
+
+		var result mafia_impl.GameResult
+		select {
+		case result = <-finished:
+		case <-time.After(time.Minute):
+			t.Fatalf("Seed %d: bots are stuck, state: %s", seed, game.GetGameState())
+		}
+
+		checkResult(t, seed, result)
+
+		_, err = mafia_impl.Verify(game.Events())
+		if err != nil {
+			t.Errorf("Seed %d: log of the game doesn't replay: %v", seed, err)
+		}
+
+		game.Close()
+	}
+}
+
+/*
+	Mafia wins if any of them survived, every player wins or loses with their team
+*/
+func checkResult(t *testing.T, seed int64, result mafia_impl.GameResult) {
+	t.Helper()
+
+	if result.Date == 0 || len(result.Players) != 8 {
+		t.Errorf("Seed %d: game must be played for at least a night by all bots: %+v", seed, result)
+	}
+
+	mafiaSurvived := false
+	for _, player := range result.Players {
+		if player.Role == mafia_impl.Mafia && player.Survived {
+			mafiaSurvived = true
+		}
+	}
+
+	if result.MafiaWon != mafiaSurvived {
+		t.Errorf("Seed %d: mafia won = %v, but mafia survived = %v", seed, result.MafiaWon, mafiaSurvived)
+	}
+
+	for _, player := range result.Players {
+		if !player.IsBot || player.Won != ((player.Role == mafia_impl.Mafia) == result.MafiaWon) {
+			t.Errorf("Seed %d: wrong result of %s: %+v", seed, player.Name, player)
+		}
+	}
+}
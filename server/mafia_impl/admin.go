@@ -0,0 +1,136 @@
+package mafia_impl
+
+import (
+	"log"
+
+	"soa_mafia/pkg/mafia_grpc"
+)
+
+/*
+	Reason sent to the players when the administrator gives none
+*/
+const noReason = "no reason given"
+
+/*
+	Stops the game without a winner: players get FINISH with the reason,
+	the result doesn't go to the statistics
+*/
+func (g *Game) ForceFinish(reason string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.apply(Event{Type: EventForceFinish, Text: reason})
+}
+
+/*
+	Removes the player from the game as if they quit, the player gets the reason first
+*/
+func (g *Game) Kick(player string, reason string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.apply(Event{Type: EventKickPlayer, Player: player, Text: reason})
+}
+
+/*
+	Sends the message of the administrator to the players and spectators
+*/
+func (g *Game) Notice(text string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.apply(Event{Type: EventNotice, Text: text})
+}
+
+/*
+	Warns the players and spectators that the server is going to stop
+*/
+func (g *Game) Shutdown(text string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.apply(Event{Type: EventShutdown, Text: text})
+}
+
+func (g *Game) forceFinish(reason string) error {
+	if g.isClosed {
+		return NewError(ErrGameClosed, "Game is closed")
+	}
+
+	if g.isFinished {
+		return NewError(ErrGameFinished, "Game has finished already")
+	}
+
+	if reason == "" {
+		reason = noReason
+	}
+
+	g.isFinished = true
+	g.finishedAt = g.now()
+	g.stopPhaseTimer()
+	g.record(Event{Type: EventFinished, Date: g.date})
+
+	notification := g.getNotification(mafia_grpc.NotificationType_FINISH, nil)
+	notification.Notice = reason
+	g.notifyAll(notification)
+
+	log.Printf("Session %s: Game %s stopped by the administrator: %s", g.session, g.id, reason)
+	return nil
+}
+
+func (g *Game) kick(player string, reason string) error {
+	if g.isClosed {
+		return NewError(ErrGameClosed, "Game is closed")
+	}
+
+	if g.isFinished {
+		return NewError(ErrGameFinished, "Game has finished already")
+	}
+
+	info, exists := g.names2players[player]
+	if !exists {
+		return NewError(ErrNoPlayer, "Player doesn't exist")
+	}
+
+	if info.hasQuit {
+		return NewError(ErrPlayerLeft, "Player has already left the game")
+	}
+
+	if reason == "" {
+		reason = noReason
+	}
+
+	g.push(player, g.getNoticeNotification("You are removed from the game: "+reason))
+	g.deletePlayer(player)
+
+	log.Printf("Session %s: Player %s removed by the administrator: %s", g.session, player, reason)
+	return nil
+}
+
+func (g *Game) notice(text string) error {
+	if g.isClosed {
+		return NewError(ErrGameClosed, "Game is closed")
+	}
+
+	g.notifyAll(g.getNoticeNotification(text))
+	return nil
+}
+
+func (g *Game) shutdown(text string) error {
+	if g.isClosed {
+		return NewError(ErrGameClosed, "Game is closed")
+	}
+
+	notification := g.getNoticeNotification(text)
+	notification.Type = mafia_grpc.NotificationType_SHUTDOWN
+	g.notifyAll(notification)
+	return nil
+}
+
+func (g *Game) getNoticeNotification(text string) *mafia_grpc.Notification {
+	return &mafia_grpc.Notification{
+		Type:      mafia_grpc.NotificationType_NOTICE,
+		GameState: g.getGameState(),
+		Notice:    text,
+	}
+}
@@ -0,0 +1,126 @@
+package mafia_impl
+
+import (
+	"sort"
+	"time"
+)
+
+/*
+	Bot takes a seat like a player, its moves are made by the server, see mafia_bot.
+	Returns token of the bot, it is required to get notifications
+*/
+func (g *Game) AddBot(name string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.addBotWithToken(name)
+}
+
+/*
+	Returns tokens of the bots that are still in the game,
+	so the server can run them again after a restart
+*/
+func (g *Game) Bots() map[string]string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	bots := make(map[string]string)
+	for name, info := range g.names2players {
+		if info.isBot && !info.hasQuit {
+			bots[name] = info.token
+		}
+	}
+
+	return bots
+}
+
+/*
+	Bots are added by the host of the lobby, see getHost. Returns the number of empty seats
+*/
+func (g *Game) SeatsForBots(player string) (int32, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, isSpectator := g.spectators[player]; isSpectator {
+		return 0, NewError(ErrNotAuthorized, "Spectators can't add bots")
+	}
+
+	info, exists := g.names2players[player]
+	if !exists || info.hasQuit {
+		return 0, NewError(ErrNoPlayer, "Player doesn't exist")
+	}
+
+	if g.isClosed {
+		return 0, NewError(ErrGameClosed, "Game is closed")
+	}
+
+	if g.isStarted {
+		return 0, NewError(ErrSessionFull, "No more players can be added")
+	}
+
+	if host := g.getHost(); player != host {
+		return 0, NewError(ErrNotAuthorized, "Only the host of the session can add bots, the host is "+host)
+	}
+
+	return g.emptySeats(), nil
+}
+
+/*
+	Host is the player who has waited in the lobby the longest: the creator of the session
+	until they leave. It's taken from the log, so it's the same after a restart. Bots don't host
+*/
+func (g *Game) getHost() string {
+	joined := make(map[string]int)
+	for i, event := range g.events {
+		if event.Type == EventAddPlayer && !event.Bot {
+			joined[event.Player] = i
+		}
+	}
+
+	host, first := "", len(g.events)
+	for name, i := range joined {
+		info, exists := g.names2players[name]
+		if exists && !info.hasQuit && i < first {
+			host, first = name, i
+		}
+	}
+
+	return host
+}
+
+/*
+	Returns the number of seats the lobby gives to bots now: the session allows it,
+	someone is waiting and nobody has joined for BotFillDelay
+*/
+func (g *Game) SeatsToFill(now time.Time) int32 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.config.BotFillDelay == 0 || g.isStarted || g.isClosed || now.Sub(g.lastActivity) < g.config.BotFillDelay {
+		return 0
+	}
+
+	for _, info := range g.names2players {
+		if !info.isBot {
+			return g.emptySeats()
+		}
+	}
+
+	return 0
+}
+
+func (g *Game) emptySeats() int32 {
+	return g.config.Players - int32(len(g.names2players))
+}
+
+func (g *Game) getBots() []string {
+	bots := []string{}
+	for name, info := range g.names2players {
+		if info.isBot && !info.hasQuit {
+			bots = append(bots, name)
+		}
+	}
+
+	sort.Strings(bots)
+	return bots
+}
@@ -0,0 +1,60 @@
+package mafia_impl
+
+import (
+	"errors"
+	"testing"
+)
+
+func checkHost(t *testing.T, game *Game, host string, others ...string) {
+	t.Helper()
+
+	_, err := game.SeatsForBots(host)
+	if err != nil {
+		t.Fatalf("Host %s must add bots: %v", host, err)
+	}
+
+	for _, player := range others {
+		_, err = game.SeatsForBots(player)
+		if !errors.Is(err, ErrNotAuthorized) {
+			t.Fatalf("%s isn't the host and must not add bots, got %v", player, err)
+		}
+	}
+}
+
+func TestOnlyHostAddsBots(t *testing.T) {
+	game := NewGame("test", NewConfig(6))
+	defer game.Close()
+
+	for _, name := range []string{"alice", "bob", "carol"} {
+		_, err := game.AddPlayer(name)
+		mustSucceed(t, err)
+	}
+	_, err := game.AddBot("Bot-1")
+	mustSucceed(t, err)
+
+	checkHost(t, game, "alice", "bob", "carol", "Bot-1")
+
+	// Host leaves the lobby, the player who has waited the longest takes over
+	mustSucceed(t, game.DeletePlayer("alice"))
+	checkHost(t, game, "bob", "carol")
+
+	// Player who comes back waits from the new join
+	_, err = game.AddPlayer("alice")
+	mustSucceed(t, err)
+	mustSucceed(t, game.DeletePlayer("bob"))
+	checkHost(t, game, "carol", "alice")
+
+	restored := RestoreGame(game.Snapshot())
+	defer restored.Close()
+	checkHost(t, restored, "carol", "alice")
+}
+
+func TestBotsAreAddedOnlyToLobby(t *testing.T) {
+	game, _ := newStartedGame(t, NewConfig(4))
+	defer game.Close()
+
+	_, err := game.SeatsForBots("p1")
+	if !errors.Is(err, ErrSessionFull) {
+		t.Fatalf("Bots must not be added to the started game, got %v", err)
+	}
+}
@@ -0,0 +1,349 @@
+package mafia_impl
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"soa_mafia/pkg/mafia_grpc"
+	"strings"
+	"time"
+)
+
+const (
+	MinPlayers     int32 = 4
+	MaxPlayers     int32 = 20
+	DefaultPlayers int32 = 4
+
+	DefaultNotificationLimit = 64
+	DefaultReconnectGrace    = time.Minute
+)
+
+/*
+	OverflowPolicy defines what happens when a player doesn't read
+	notifications and the queue is full
+*/
+type OverflowPolicy int
+
+const (
+	DropOldest OverflowPolicy = iota
+	Disconnect
+)
+
+var overflowPolicies = map[string]OverflowPolicy{
+	"drop_oldest": DropOldest,
+	"disconnect":  Disconnect,
+}
+
+/*
+	Policy is named drop_oldest or disconnect
+*/
+func ParseOverflowPolicy(name string) (OverflowPolicy, error) {
+	policy, exists := overflowPolicies[strings.ToLower(name)]
+	if !exists {
+		return DropOldest, NewError(ErrInvalidArgument, "Unknown overflow policy: "+name)
+	}
+
+	return policy, nil
+}
+
+var knownRoles = []Role{Mafia, Detective, Doctor, Civilian}
+
+/*
+	Config describes the table of a single session.
+	Roles holds the number of players for every role, seats that are left
+	after all listed roles are given out are taken by civilians.
+	KillRule defines how votes of several mafia players choose the night victim.
+	DoctorSelfHeal allows doctor to heal themself, DoctorRepeatHeal allows
+	doctor to heal the same player two nights in a row.
+	DayDuration and NightDuration limit phases in time, when the time is up
+	the phase ends with the actions made so far. Zero means no limit.
+	Runoff enables the second day vote between the tied players,
+	RunoffFallback decides what happens if the runoff is tied too.
+	OpenBallot shows everyone who voted for whom during the day.
+	NotificationLimit is the size of the notification queue of every player,
+	OverflowPolicy is applied when the queue is full.
+	ReconnectGrace is the time a disconnected player has to come back
+	before they are removed from the game.
+	Seed initializes the random generator of the game: the same seed and
+	the same names of the players give the same deal. Zero means a random seed,
+	every game of the session gets its own.
+	OmniscientSpectators allows spectators to see roles of all players.
+	Session with PasswordHash is private: it can be joined only with the password.
+	BotFillDelay is the time the lobby waits for new players before bots take
+	the empty seats. Zero means bots are added only on request.
+*/
+type Config struct {
+	Players  int32
+	Roles    map[Role]int32
+	KillRule mafia_grpc.KillRule
+
+	DoctorSelfHeal   bool
+	DoctorRepeatHeal bool
+
+	DayDuration   time.Duration
+	NightDuration time.Duration
+
+	Runoff         bool
+	RunoffFallback mafia_grpc.RunoffFallback
+
+	OpenBallot bool
+
+	NotificationLimit int
+	OverflowPolicy    OverflowPolicy
+	ReconnectGrace    time.Duration
+
+	Seed int64
+
+	OmniscientSpectators bool
+
+	PasswordHash string
+
+	BotFillDelay time.Duration
+}
+
+func DefaultConfig() Config {
+	return NewConfig(DefaultPlayers)
+}
+
+/*
+	Returns config with the standard composition for the given table size:
+	one mafia for every four players, one detective and civilians.
+*/
+func NewConfig(players int32) Config {
+	mafia := players / 4
+	if mafia < 1 {
+		mafia = 1
+	}
+
+	return Config{
+		Players: players,
+		Roles: map[Role]int32{
+			Mafia:     mafia,
+			Detective: 1,
+		},
+		NotificationLimit: DefaultNotificationLimit,
+		OverflowPolicy:    DropOldest,
+		ReconnectGrace:    DefaultReconnectGrace,
+	}
+}
+
+func ConfigFromProto(config *mafia_grpc.SessionConfig) (Config, error) {
+	return ConfigWithDefaults(config, DefaultConfig())
+}
+
+/*
+	Session without config gets the defaults. Otherwise the defaults fill the values
+	the request leaves zero: table size, phase durations and bot fill delay,
+	roles too if the table has the default size. Kill rule LEADER is the zero value,
+	so it's taken from the request only with hasKillRule. Notification queue
+	and reconnect grace period always come from the defaults
+*/
+func ConfigWithDefaults(config *mafia_grpc.SessionConfig, defaults Config) (Config, error) {
+	if config == nil {
+		defaults.Roles = copyRoles(defaults.Roles)
+		return defaults, nil
+	}
+
+	players := config.GetPlayers()
+	if players == 0 {
+		players = defaults.Players
+	}
+
+	result := NewConfig(players)
+	if len(config.GetRoles()) > 0 {
+		result.Roles = make(map[Role]int32)
+		for role, count := range config.GetRoles() {
+			result.Roles[Role(role)] = count
+		}
+	} else if players == defaults.Players {
+		result.Roles = copyRoles(defaults.Roles)
+	}
+
+	result.KillRule = defaults.KillRule
+	if config.GetHasKillRule() || config.GetKillRule() != mafia_grpc.KillRule_LEADER {
+		result.KillRule = config.GetKillRule()
+	}
+	result.DoctorSelfHeal = config.GetDoctorSelfHeal()
+	result.DoctorRepeatHeal = config.GetDoctorRepeatHeal()
+	result.DayDuration = time.Duration(config.GetDayDuration()) * time.Second
+	result.NightDuration = time.Duration(config.GetNightDuration()) * time.Second
+	result.Runoff = config.GetRunoff()
+	result.RunoffFallback = config.GetRunoffFallback()
+	result.OpenBallot = config.GetOpenBallot()
+	result.Seed = config.GetSeed()
+	result.OmniscientSpectators = config.GetOmniscientSpectators()
+	result.BotFillDelay = time.Duration(config.GetBotFillDelay()) * time.Second
+
+	if result.DayDuration == 0 {
+		result.DayDuration = defaults.DayDuration
+	}
+	if result.NightDuration == 0 {
+		result.NightDuration = defaults.NightDuration
+	}
+	if result.BotFillDelay == 0 {
+		result.BotFillDelay = defaults.BotFillDelay
+	}
+
+	result.NotificationLimit = defaults.NotificationLimit
+	result.OverflowPolicy = defaults.OverflowPolicy
+	result.ReconnectGrace = defaults.ReconnectGrace
+
+	return result, result.Validate()
+}
+
+func copyRoles(roles map[Role]int32) map[Role]int32 {
+	result := make(map[Role]int32)
+	for role, count := range roles {
+		result[role] = count
+	}
+
+	return result
+}
+
+/*
+	Converts config to the ruleset shown in the lobby, the seed and the password are hidden
+*/
+func (c Config) ToProto() *mafia_grpc.SessionConfig {
+	roles := make(map[string]int32)
+	for role, count := range c.Roles {
+		roles[string(role)] = count
+	}
+
+	return &mafia_grpc.SessionConfig{
+		Players:              c.Players,
+		Roles:                roles,
+		KillRule:             c.KillRule,
+		HasKillRule:          true,
+		DoctorSelfHeal:       c.DoctorSelfHeal,
+		DoctorRepeatHeal:     c.DoctorRepeatHeal,
+		DayDuration:          int32(c.DayDuration / time.Second),
+		NightDuration:        int32(c.NightDuration / time.Second),
+		Runoff:               c.Runoff,
+		RunoffFallback:       c.RunoffFallback,
+		OpenBallot:           c.OpenBallot,
+		OmniscientSpectators: c.OmniscientSpectators,
+		BotFillDelay:         int32(c.BotFillDelay / time.Second),
+	}
+}
+
+/*
+	Empty password makes the session public
+*/
+func (c *Config) SetPassword(password string) {
+	c.PasswordHash = ""
+	if password != "" {
+		c.PasswordHash = hashPassword(password)
+	}
+}
+
+func (c Config) IsPrivate() bool {
+	return c.PasswordHash != ""
+}
+
+func (c Config) checkPassword(password string) bool {
+	if !c.IsPrivate() {
+		return true
+	}
+
+	return subtle.ConstantTimeCompare([]byte(c.PasswordHash), []byte(hashPassword(password))) == 1
+}
+
+func hashPassword(password string) string {
+	hash := sha256.Sum256([]byte(password))
+	return hex.EncodeToString(hash[:])
+}
+
+func (c Config) Validate() error {
+	if c.Players < MinPlayers || c.Players > MaxPlayers {
+		return NewError(ErrInvalidArgument, fmt.Sprintf("Number of players must be between %d and %d", MinPlayers, MaxPlayers))
+	}
+
+	var total int32 = 0
+	for role, count := range c.Roles {
+		if !isKnownRole(role) {
+			return NewError(ErrInvalidArgument, "Unknown role: "+string(role))
+		}
+
+		if count < 0 {
+			return NewError(ErrInvalidArgument, "Number of players with role "+string(role)+" is negative")
+		}
+
+		total += count
+	}
+
+	if total > c.Players {
+		return NewError(ErrInvalidArgument, "There are more roles than players")
+	}
+
+	if _, exists := c.Roles[Civilian]; exists && total != c.Players {
+		return NewError(ErrInvalidArgument, "Number of roles doesn't match number of players")
+	}
+
+	mafia := c.Roles[Mafia]
+	if mafia < 1 {
+		return NewError(ErrInvalidArgument, "There must be at least one mafia")
+	}
+
+	if 2*mafia >= c.Players {
+		return NewError(ErrInvalidArgument, "Mafia must be a minority")
+	}
+
+	if _, exists := mafia_grpc.KillRule_name[int32(c.KillRule)]; !exists {
+		return NewError(ErrInvalidArgument, "Unknown kill rule")
+	}
+
+	if _, exists := mafia_grpc.RunoffFallback_name[int32(c.RunoffFallback)]; !exists {
+		return NewError(ErrInvalidArgument, "Unknown runoff fallback")
+	}
+
+	if c.DayDuration < 0 || c.NightDuration < 0 {
+		return NewError(ErrInvalidArgument, "Phase duration is negative")
+	}
+
+	if c.BotFillDelay < 0 {
+		return NewError(ErrInvalidArgument, "Bot fill delay is negative")
+	}
+
+	if c.NotificationLimit < 1 {
+		return NewError(ErrInvalidArgument, "Notification limit must be positive")
+	}
+
+	if c.OverflowPolicy != DropOldest && c.OverflowPolicy != Disconnect {
+		return NewError(ErrInvalidArgument, "Unknown overflow policy")
+	}
+
+	if c.ReconnectGrace < 0 {
+		return NewError(ErrInvalidArgument, "Reconnect grace period is negative")
+	}
+
+	return nil
+}
+
+/*
+	Returns the list of roles to deal, its length is equal to Players
+*/
+func (c Config) roles() []Role {
+	roles := []Role{}
+	for _, role := range knownRoles {
+		for i := int32(0); i < c.Roles[role]; i++ {
+			roles = append(roles, role)
+		}
+	}
+
+	for int32(len(roles)) < c.Players {
+		roles = append(roles, Civilian)
+	}
+
+	return roles
+}
+
+func isKnownRole(role Role) bool {
+	for _, known := range knownRoles {
+		if known == role {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,82 @@
+package mafia_impl
+
+import (
+	"testing"
+
+	"soa_mafia/pkg/mafia_grpc"
+)
+
+func TestNotificationQueueComesFromDefaults(t *testing.T) {
+	defaults := DefaultConfig()
+	defaults.NotificationLimit = 8
+	defaults.OverflowPolicy = Disconnect
+
+	for _, request := range []*mafia_grpc.SessionConfig{nil, {Players: 6}} {
+		config, err := ConfigWithDefaults(request, defaults)
+		mustSucceed(t, err)
+
+		if config.NotificationLimit != 8 || config.OverflowPolicy != Disconnect {
+			t.Fatalf("Session config %s must get the queue of the defaults, got %d %d", request, config.NotificationLimit, config.OverflowPolicy)
+		}
+	}
+}
+
+func TestOverflowPolicyIsChecked(t *testing.T) {
+	for name, expected := range map[string]OverflowPolicy{"drop_oldest": DropOldest, "Disconnect": Disconnect} {
+		policy, err := ParseOverflowPolicy(name)
+		mustSucceed(t, err)
+
+		if policy != expected {
+			t.Fatalf("Policy %s must be %d, got %d", name, expected, policy)
+		}
+	}
+
+	if _, err := ParseOverflowPolicy("block"); err == nil {
+		t.Fatal("Unknown policy must be an error")
+	}
+
+	config := DefaultConfig()
+	config.OverflowPolicy = OverflowPolicy(5)
+	if config.Validate() == nil {
+		t.Fatal("Config with unknown policy must be invalid")
+	}
+
+	config = DefaultConfig()
+	config.NotificationLimit = 0
+	if config.Validate() == nil {
+		t.Fatal("Config without queue must be invalid")
+	}
+}
+
+func TestKillRuleComesFromDefaultsUnlessSet(t *testing.T) {
+	defaults := DefaultConfig()
+	defaults.KillRule = mafia_grpc.KillRule_MAJORITY
+
+	cases := []struct {
+		request  *mafia_grpc.SessionConfig
+		expected mafia_grpc.KillRule
+	}{
+		{nil, mafia_grpc.KillRule_MAJORITY},
+		{&mafia_grpc.SessionConfig{Players: 6}, mafia_grpc.KillRule_MAJORITY},
+		{&mafia_grpc.SessionConfig{KillRule: mafia_grpc.KillRule_UNANIMOUS}, mafia_grpc.KillRule_UNANIMOUS},
+		{&mafia_grpc.SessionConfig{KillRule: mafia_grpc.KillRule_LEADER, HasKillRule: true}, mafia_grpc.KillRule_LEADER},
+	}
+
+	for _, c := range cases {
+		config, err := ConfigWithDefaults(c.request, defaults)
+		mustSucceed(t, err)
+
+		if config.KillRule != c.expected {
+			t.Errorf("Session config %s must get kill rule %s, got %s", c.request, c.expected, config.KillRule)
+		}
+	}
+
+	// The lobby shows the rule, the same config creates the same session
+	config, err := ConfigWithDefaults(&mafia_grpc.SessionConfig{HasKillRule: true}, defaults)
+	mustSucceed(t, err)
+	copied, err := ConfigWithDefaults(config.ToProto(), defaults)
+	mustSucceed(t, err)
+	if copied.KillRule != mafia_grpc.KillRule_LEADER {
+		t.Errorf("Rule from the lobby must be kept, got %s", copied.KillRule)
+	}
+}
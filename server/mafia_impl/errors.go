@@ -0,0 +1,103 @@
+package mafia_impl
+
+import (
+	"soa_mafia/pkg/mafia_grpc"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const ErrorDomain = "soa_mafia"
+
+/*
+	GameError carries the reason clients can check in code instead of the message.
+	gRPC turns it into the status with the code of the reason and ErrorInfo details
+*/
+type GameError struct {
+	Reason  mafia_grpc.ErrorReason
+	Message string
+}
+
+/*
+	Errors of every reason. Errors with the same reason are equal for errors.Is,
+	the message may differ
+*/
+var (
+	ErrInvalidArgument = &GameError{mafia_grpc.ErrorReason_INVALID_ARGUMENT, "Invalid argument"}
+	ErrNoSession       = &GameError{mafia_grpc.ErrorReason_NO_SESSION, "No game session"}
+	ErrNoPlayer        = &GameError{mafia_grpc.ErrorReason_NO_PLAYER, "Player doesn't exist"}
+	ErrPlayerExists    = &GameError{mafia_grpc.ErrorReason_PLAYER_EXISTS, "Player already exists"}
+	ErrSessionFull     = &GameError{mafia_grpc.ErrorReason_SESSION_FULL, "No more players can be added"}
+	ErrNotStarted      = &GameError{mafia_grpc.ErrorReason_NOT_STARTED, "Game hasn't started yet"}
+	ErrGameFinished    = &GameError{mafia_grpc.ErrorReason_GAME_FINISHED, "Game has finished already"}
+	ErrWrongPhase      = &GameError{mafia_grpc.ErrorReason_WRONG_PHASE, "Action isn't allowed in this phase"}
+	ErrNotYourTurn     = &GameError{mafia_grpc.ErrorReason_NOT_YOUR_TURN, "Player has already acted"}
+	ErrNotAuthorized   = &GameError{mafia_grpc.ErrorReason_NOT_AUTHORIZED, "Player isn't allowed to do it"}
+	ErrInvalidToken    = &GameError{mafia_grpc.ErrorReason_INVALID_TOKEN, "Invalid token"}
+	ErrPlayerDead      = &GameError{mafia_grpc.ErrorReason_PLAYER_DEAD, "Player is dead"}
+	ErrTargetNotFound  = &GameError{mafia_grpc.ErrorReason_TARGET_NOT_FOUND, "Target doesn't exist"}
+	ErrTargetDead      = &GameError{mafia_grpc.ErrorReason_TARGET_DEAD, "Target is already dead"}
+	ErrRuleViolation   = &GameError{mafia_grpc.ErrorReason_RULE_VIOLATION, "Action breaks the rules of the session"}
+	ErrGameClosed      = &GameError{mafia_grpc.ErrorReason_GAME_CLOSED, "Game is closed"}
+	ErrPlayerLeft      = &GameError{mafia_grpc.ErrorReason_PLAYER_LEFT, "Player has already left the game"}
+	ErrShuttingDown    = &GameError{mafia_grpc.ErrorReason_SHUTTING_DOWN, "Server is shutting down"}
+)
+
+var reason2code = map[mafia_grpc.ErrorReason]codes.Code{
+	mafia_grpc.ErrorReason_INVALID_ARGUMENT: codes.InvalidArgument,
+	mafia_grpc.ErrorReason_NO_SESSION:       codes.NotFound,
+	mafia_grpc.ErrorReason_NO_PLAYER:        codes.NotFound,
+	mafia_grpc.ErrorReason_PLAYER_EXISTS:    codes.AlreadyExists,
+	mafia_grpc.ErrorReason_SESSION_FULL:     codes.ResourceExhausted,
+	mafia_grpc.ErrorReason_NOT_STARTED:      codes.FailedPrecondition,
+	mafia_grpc.ErrorReason_GAME_FINISHED:    codes.FailedPrecondition,
+	mafia_grpc.ErrorReason_WRONG_PHASE:      codes.FailedPrecondition,
+	mafia_grpc.ErrorReason_NOT_YOUR_TURN:    codes.FailedPrecondition,
+	mafia_grpc.ErrorReason_NOT_AUTHORIZED:   codes.PermissionDenied,
+	mafia_grpc.ErrorReason_INVALID_TOKEN:    codes.Unauthenticated,
+	mafia_grpc.ErrorReason_PLAYER_DEAD:      codes.FailedPrecondition,
+	mafia_grpc.ErrorReason_TARGET_NOT_FOUND: codes.NotFound,
+	mafia_grpc.ErrorReason_TARGET_DEAD:      codes.FailedPrecondition,
+	mafia_grpc.ErrorReason_RULE_VIOLATION:   codes.FailedPrecondition,
+	mafia_grpc.ErrorReason_GAME_CLOSED:      codes.FailedPrecondition,
+	mafia_grpc.ErrorReason_PLAYER_LEFT:      codes.FailedPrecondition,
+	mafia_grpc.ErrorReason_SHUTTING_DOWN:    codes.Unavailable,
+}
+
+/*
+	Returns error of the same kind with the given message
+*/
+func NewError(kind *GameError, message string) error {
+	return &GameError{kind.Reason, message}
+}
+
+func (e *GameError) Error() string {
+	return e.Message
+}
+
+func (e *GameError) Is(target error) bool {
+	other, ok := target.(*GameError)
+	return ok && other.Reason == e.Reason
+}
+
+/*
+	Used by gRPC to send the error to the client
+*/
+func (e *GameError) GRPCStatus() *status.Status {
+	code, exists := reason2code[e.Reason]
+	if !exists {
+		code = codes.Unknown
+	}
+
+	st := status.New(code, e.Message)
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: e.Reason.String(),
+		Domain: ErrorDomain,
+	})
+	if err != nil {
+		return st
+	}
+
+	return detailed
+}
@@ -0,0 +1,284 @@
+package mafia_impl
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"time"
+)
+
+type EventType string
+
+/*
+	Input events are the calls that change the game: API calls and timeouts.
+	Other events are their consequences, they are recorded for the review
+	and to repeat random choices during the replay
+*/
+const (
+	EventCreated          EventType = "Created"
+	EventAddPlayer        EventType = "AddPlayer"
+	EventAddVote          EventType = "AddVote"
+	EventChangeVote       EventType = "ChangeVote"
+	EventWithdrawVote     EventType = "WithdrawVote"
+	EventSkipVote         EventType = "SkipVote"
+	EventKillPlayer       EventType = "KillPlayer"
+	EventCheckIfMafia     EventType = "CheckIfMafia"
+	EventHealPlayer       EventType = "HealPlayer"
+	EventDeletePlayer     EventType = "DeletePlayer"
+	EventPhaseTimeout     EventType = "PhaseTimeout"
+	EventReconnectTimeout EventType = "ReconnectTimeout"
+	EventClose            EventType = "Close"
+	EventForceFinish      EventType = "ForceFinish"
+	EventKickPlayer       EventType = "KickPlayer"
+	EventNotice           EventType = "Notice"
+	EventShutdown         EventType = "Shutdown"
+
+	EventRolesAssigned EventType = "RolesAssigned"
+	EventNewNight      EventType = "NewNight"
+	EventNewDay        EventType = "NewDay"
+	EventRunoff        EventType = "Runoff"
+	EventRandomVictim  EventType = "RandomVictim"
+	EventPlayerKilled  EventType = "PlayerKilled"
+	EventFinished      EventType = "Finished"
+)
+
+/*
+	Event of the game log. Player is the one who acts, Target is the one acted upon,
+	Bot marks players added as bots, Text is the message of the administrator.
+	Created holds the session, game id, config and seed, RolesAssigned holds the deal,
+	phase events hold the date
+*/
+type Event struct {
+	Type   EventType
+	Time   time.Time
+	Player string `json:",omitempty"`
+	Target string `json:",omitempty"`
+	Bot    bool   `json:",omitempty"`
+	Text   string `json:",omitempty"`
+
+	Session string  `json:",omitempty"`
+	GameID  string  `json:",omitempty"`
+	Config  *Config `json:",omitempty"`
+	Seed    int64   `json:",omitempty"`
+
+	Roles       map[string]Role `json:",omitempty"`
+	MafiaLeader string          `json:",omitempty"`
+	Date        int32           `json:",omitempty"`
+}
+
+func (t EventType) isInput() bool {
+	switch t {
+	case EventRolesAssigned, EventNewNight, EventNewDay, EventRunoff, EventRandomVictim, EventPlayerKilled, EventFinished:
+		return false
+	}
+
+	return true
+}
+
+/*
+	Timeouts and actions of the administrator aren't activity of the players
+*/
+func (t EventType) isActivity() bool {
+	switch t {
+	case EventPhaseTimeout, EventReconnectTimeout, EventClose, EventForceFinish, EventKickPlayer, EventNotice, EventShutdown:
+		return false
+	}
+
+	return true
+}
+
+/*
+	State of the replay: the recorded log and the position of the input event being applied
+*/
+type replayState struct {
+	events []Event
+	cursor int
+}
+
+/*
+	Returns copy of the game log
+*/
+func (g *Game) Events() []Event {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	events := make([]Event, len(g.events))
+	copy(events, g.events)
+	return events
+}
+
+/*
+	Rebuilds the game from its log. Input events are applied in order, random
+	choices are taken from the recorded events instead of being made again.
+	Replayed game is for review only: it has no timers and tokens of the players differ
+*/
+func Replay(events []Event) (*Game, error) {
+	if len(events) == 0 || events[0].Type != EventCreated || events[0].Config == nil {
+		return nil, errors.New("Log must start with Created event")
+	}
+
+	created := events[0]
+	game := &Game{replay: &replayState{events: events}}
+	game.init(created.Session, *created.Config, savedSeed(created.Seed, *created.Config))
+	game.id = created.GameID
+	game.lastActivity = created.Time
+	game.events = append(game.events, created)
+
+	for i := 1; i < len(events); i++ {
+		event := events[i]
+		if !event.Type.isInput() {
+			continue
+		}
+
+		game.replay.cursor = i
+		err := game.apply(event)
+		if err != nil {
+			return nil, fmt.Errorf("Event %d (%s) can't be replayed: %v", i, event.Type, err)
+		}
+	}
+
+	return game, nil
+}
+
+/*
+	Replays the log and checks that the replay records the same events,
+	so the log describes the game completely. Returns the replayed game
+*/
+func Verify(events []Event) (*Game, error) {
+	game, err := Replay(events)
+	if err != nil {
+		return nil, err
+	}
+
+	replayed := game.Events()
+	for i := 0; i < len(events) || i < len(replayed); i++ {
+		if i >= len(events) || i >= len(replayed) || !sameEvent(events[i], replayed[i]) {
+			return game, fmt.Errorf("Replay differs from the log at event %d", i)
+		}
+	}
+
+	return game, nil
+}
+
+/*
+	Time read from a file has no monotonic clock, so times are compared separately
+*/
+func sameEvent(a Event, b Event) bool {
+	if !a.Time.Equal(b.Time) {
+		return false
+	}
+
+	a.Time, b.Time = time.Time{}, time.Time{}
+	return reflect.DeepEqual(a, b)
+}
+
+/*
+	Records the input event and applies it. Event that fails is removed from the log,
+	all checks are made before the state changes, so the failed event changes nothing.
+	Consequences of the event get its time. Events of players count as activity
+*/
+func (g *Game) apply(event Event) error {
+	if g.replay == nil {
+		event.Time = time.Now()
+	}
+
+	previousTime := g.eventTime
+	g.eventTime = event.Time
+	defer func() {
+		g.eventTime = previousTime
+	}()
+
+	size := len(g.events)
+	g.events = append(g.events, event)
+
+	err := g.execute(event)
+	if err != nil {
+		g.events = g.events[:size]
+		return err
+	}
+
+	if event.Type.isActivity() {
+		g.touch()
+	}
+
+	return nil
+}
+
+func (g *Game) execute(event Event) error {
+	switch event.Type {
+	case EventAddPlayer:
+		return g.addPlayer(event.Player, event.Bot)
+	case EventAddVote:
+		return g.addVote(event.Player, event.Target)
+	case EventChangeVote:
+		return g.changeVote(event.Player, event.Target)
+	case EventWithdrawVote:
+		return g.withdrawVote(event.Player)
+	case EventSkipVote:
+		return g.skipVote(event.Player)
+	case EventKillPlayer:
+		return g.killPlayer(event.Player, event.Target)
+	case EventCheckIfMafia:
+		return g.checkIfMafia(event.Player, event.Target)
+	case EventHealPlayer:
+		return g.healPlayer(event.Player, event.Target)
+	case EventDeletePlayer:
+		return g.quit(event.Player)
+	case EventPhaseTimeout:
+		return g.timeOutPhase()
+	case EventReconnectTimeout:
+		return g.timeOutReconnect(event.Player)
+	case EventClose:
+		if g.isClosed {
+			return NewError(ErrGameClosed, "Game is closed")
+		}
+
+		g.close()
+		return nil
+	case EventForceFinish:
+		return g.forceFinish(event.Text)
+	case EventKickPlayer:
+		return g.kick(event.Player, event.Text)
+	case EventNotice:
+		return g.notice(event.Text)
+	case EventShutdown:
+		return g.shutdown(event.Text)
+	}
+
+	return errors.New("Unknown event: " + string(event.Type))
+}
+
+/*
+	Adds the consequence of the input event to the log
+*/
+func (g *Game) record(event Event) {
+	event.Time = g.now()
+	g.events = append(g.events, event)
+}
+
+/*
+	Returns the consequence of the current input event from the replayed log
+*/
+func (g *Game) recorded(eventType EventType) (Event, bool) {
+	events := g.replay.events
+	for i := g.replay.cursor + 1; i < len(events) && !events[i].Type.isInput(); i++ {
+		if events[i].Type == eventType {
+			return events[i], true
+		}
+	}
+
+	log.Printf("Session %s: %s isn't found in the replayed log", g.session, eventType)
+	return Event{}, false
+}
+
+/*
+	Time of the event being applied
+*/
+func (g *Game) now() time.Time {
+	if !g.eventTime.IsZero() {
+		return g.eventTime
+	}
+
+	return time.Now()
+}
@@ -0,0 +1,129 @@
+package mafia_impl
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"soa_mafia/pkg/mafia_grpc"
+)
+
+/*
+	Plays the game with random moves of all players from one goroutine, so the game
+	depends only on the seed. Moves may break the rules, players may quit
+*/
+func playRandomMoves(t *testing.T, game *Game, names []string, rng *rand.Rand) {
+	t.Helper()
+
+	for step := 0; step < 5000 && !game.IsFinished(); step++ {
+		player, target := names[rng.Intn(len(names))], names[rng.Intn(len(names))]
+		switch rng.Intn(8) {
+		case 0, 1:
+			game.AddVote(player, target)
+		case 2:
+			game.KillPlayer(player, target)
+		case 3:
+			game.CheckIfMafia(player, target)
+		case 4:
+			game.HealPlayer(player, target)
+		case 5:
+			game.SkipVote(player)
+		case 6:
+			game.ChangeVote(player, target)
+		case 7:
+			if rng.Intn(20) == 0 {
+				game.DeletePlayer(player)
+			}
+		}
+	}
+
+	if !game.IsFinished() {
+		t.Fatalf("Game didn't finish: %s", game.GetGameState())
+	}
+}
+
+func sortedState(game *Game) (string, []string) {
+	state := game.GetGameState()
+	alive := state.GetAlivePlayers()
+	sort.Strings(alive)
+	state.AlivePlayers = nil
+
+	return state.String(), alive
+}
+
+func TestReplayRebuildsFinishedGame(t *testing.T) {
+	for seed := int64(1); seed <= 100; seed++ {
+		t.Run(fmt.Sprint(seed), func(t *testing.T) {
+			config := NewConfig(7)
+			config.Roles = map[Role]int32{Mafia: 2, Detective: 1, Doctor: 1}
+			config.DoctorSelfHeal = true
+			config.Runoff = true
+			config.RunoffFallback = mafia_grpc.RunoffFallback(seed % 3)
+			config.KillRule = mafia_grpc.KillRule(seed % 3)
+			config.Seed = seed
+			game, _ := newStartedGame(t, config)
+
+			names := []string{}
+			for i := 1; i <= 7; i++ {
+				names = append(names, fmt.Sprintf("p%d", i))
+			}
+			playRandomMoves(t, game, names, rand.New(rand.NewSource(seed)))
+
+			// Log goes through JSON as it does in the storage
+			data, err := json.Marshal(game.Events())
+			mustSucceed(t, err)
+
+			var events []Event
+			mustSucceed(t, json.Unmarshal(data, &events))
+
+			replayed, err := Verify(events)
+			mustSucceed(t, err)
+
+			originalState, originalAlive := sortedState(game)
+			replayedState, replayedAlive := sortedState(replayed)
+			if replayedState != originalState || !reflect.DeepEqual(replayedAlive, originalAlive) {
+				t.Fatalf("Replayed game differs:\n%s %v\n%s %v", originalState, originalAlive, replayedState, replayedAlive)
+			}
+
+			original, _ := game.Result()
+			result, finished := replayed.Result()
+			if !finished || !result.Finished.Equal(original.Finished) {
+				t.Fatalf("Replayed game must finish at %s, finished: %t at %s", original.Finished, finished, result.Finished)
+			}
+
+			result.Finished = original.Finished
+			if !reflect.DeepEqual(result, original) {
+				t.Fatalf("Replayed result differs:\n%+v\n%+v", original, result)
+			}
+		})
+	}
+}
+
+func TestVerifyFindsChangedLog(t *testing.T) {
+	names := []string{"p1", "p2", "p3", "p4", "p5"}
+	for seed := int64(1); seed <= 20; seed++ {
+		config := NewConfig(5)
+		config.Seed = seed
+		game, _ := newStartedGame(t, config)
+		playRandomMoves(t, game, names, rand.New(rand.NewSource(seed)))
+
+		events := game.Events()
+		for i, event := range events {
+			if event.Type != EventPlayerKilled {
+				continue
+			}
+
+			// Consequence doesn't match the moves anymore
+			events[i].Target = "somebody else"
+			if _, err := Verify(events); err == nil {
+				t.Fatal("Log with changed victim must not be verified")
+			}
+			return
+		}
+	}
+
+	t.Fatal("Nobody was killed in the games")
+}
@@ -3,456 +3,1551 @@ package mafia_impl
 import (
 	// "context"
 	// "fmt"
-	"errors"
+	crand "crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/hex"
 	"log"
 	"math/rand"
 	"soa_mafia/pkg/mafia_grpc"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 	// "google.golang.org/grpc"
 )
 
 /*
 	TODO:
-		- rwlock
 		- notify through channels
 		- pretty print, logging
-		- custom errors with constants and messages
 */
 
-const (
-	MaxPlayers int32 = 4
-)
-
 type Role string
 
 const (
 	Mafia     Role = "Mafia"
 	Detective Role = "Detective"
 	Civilian  Role = "Civilian"
+	Doctor    Role = "Doctor"
 )
 
+/*
+	Player is connected while somebody reads the notification channel.
+	Disconnected player keeps the seat for the reconnect grace period,
+	notifications are queued for them as usual
+*/
 type playerInfo struct {
 	role          Role
 	isAlive       bool
-	hasVoted      bool
-	notifications *chan *mafia_grpc.Notification
+	hasChecked    bool
+	healed        string
+	lastHealed    string
+	isBot         bool
+	token         string
+	hasQuit       bool
+	isConnected   bool
+	graceTimer    *time.Timer
+	notifications chan *mafia_grpc.Notification
 }
 
+/*
+	All exported methods of Game are safe for concurrent use: they hold mu
+	for the whole call. Every player has a bounded notification queue and
+	the game never waits for a reader, see push.
+	Session is the name players join by, it is reused by rematches,
+	while id is unique for every game
+*/
 type Game struct {
+	mu sync.Mutex
+
+	id            string
 	session       string
+	config        Config
 	names2players map[string]playerInfo
+	spectators    map[string]spectatorInfo
 	isStarted     bool
 	isFinished    bool
+	isClosed      bool
+
+	previousTokens map[string]string
+	lastActivity   time.Time
+	finishedAt     time.Time
+
+	events    []Event
+	eventTime time.Time
+	replay    *replayState
+	onFinish  func(GameResult)
+
+	seed int64
+	rng  *rand.Rand
 
 	alivePlayers int32
 	date         int32
 
 	isDay            bool
-	names2votes      map[string]int32
-	votes            int32
-	mafiaChoice      string
-	detectiveChecked bool
+	isRunoff         bool
+	runoffCandidates []string
+	dailyVotes       map[string]string
+	mafiaVotes       map[string]string
+	mafiaLeader      string
+
+	phase         int32
+	phaseDeadline time.Time
+	phaseTimer    *time.Timer
 }
 
 ////////////////////////////////////////////////// API ///////////////////////////////////////////////////////
 
-func NewGame(session string) *Game {
+/*
+	If the config has no seed, then a random one is chosen. It's kept apart from the config,
+	so the config stays as the organizer set it and the rematch gets a new seed.
+	The seed gets to the log and to the snapshots
+*/
+func NewGame(session string, config Config) *Game {
+	seed := config.Seed
+	if seed == 0 {
+		seed = generateSeed()
+	}
+
 	game := Game{}
-	game.init(session)
+	game.init(session, config, seed)
+	game.record(Event{Type: EventCreated, Session: session, GameID: game.id, Config: &game.config, Seed: seed})
 	return &game
 }
 
-func (g *Game) AddPlayer(name string) error {
+/*
+	Creates the next game of the finished session. Players of the previous game
+	get REMATCH notification and can join the new one with AddRematchPlayer,
+	after that the previous game is closed
+*/
+func NewRematch(previous *Game, config Config) *Game {
+	game := NewGame(previous.Session(), config)
+	game.previousTokens = previous.offerRematch(game.id)
+	return game
+}
+
+/*
+	Returns token of the new player, it is required to get notifications
+*/
+func (g *Game) AddPlayer(name string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.addPlayerWithToken(name)
+}
+
+/*
+	Same as AddPlayer, but only for players of the previous game of the session
+*/
+func (g *Game) AddRematchPlayer(name string, previousToken string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	token, exists := g.previousTokens[name]
+	if !exists {
+		return "", NewError(ErrNotAuthorized, "Player didn't take part in the previous game")
+	}
+
+	if !equalTokens(token, previousToken) {
+		return "", NewError(ErrInvalidToken, "Invalid token of the previous game")
+	}
+
+	return g.addPlayerWithToken(name)
+}
+
+func (g *Game) addPlayerWithToken(name string) (string, error) {
+	err := g.apply(Event{Type: EventAddPlayer, Player: name})
+	if err != nil {
+		return "", err
+	}
+
+	return g.names2players[name].token, nil
+}
+
+func (g *Game) addBotWithToken(name string) (string, error) {
+	err := g.apply(Event{Type: EventAddPlayer, Player: name, Bot: true})
+	if err != nil {
+		return "", err
+	}
+
+	return g.names2players[name].token, nil
+}
+
+func (g *Game) addPlayer(name string, isBot bool) error {
+	if g.isClosed {
+		return NewError(ErrGameClosed, "Game is closed")
+	}
+
 	if g.isStarted {
-		return errors.New("No more players can be added")
+		return NewError(ErrSessionFull, "No more players can be added")
 	}
 
 	if name == "" {
-		return errors.New("Name is empty")
+		return NewError(ErrInvalidArgument, "Name is empty")
 	}
 
 	_, exist := g.names2players[name]
-	if exist {
-		return errors.New("Player already exists")
+	_, isSpectator := g.spectators[name]
+	if exist || isSpectator {
+		return NewError(ErrPlayerExists, "Player already exists")
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return err
 	}
 
-	notifications := make(chan *mafia_grpc.Notification)
 	g.names2players[name] = playerInfo{
 		isAlive:       true,
-		hasVoted:      false,
-		notifications: &notifications,
+		isBot:         isBot,
+		token:         token,
+		notifications: make(chan *mafia_grpc.Notification, g.config.NotificationLimit),
 	}
 
 	g.alivePlayers++
-	if g.alivePlayers == MaxPlayers {
-		go g.start()
+	if g.alivePlayers == g.config.Players {
+		g.start()
 	}
 
 	return nil
 }
 
 func (g *Game) AddVote(player string, victim string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.apply(Event{Type: EventAddVote, Player: player, Target: victim})
+}
+
+func (g *Game) addVote(player string, victim string) error {
 	log.Println("AddVote: ", player, " -> ", victim)
 
-	if g.isFinished {
-		return errors.New("Game has finished already")
+	err := g.checkVotingTime()
+	if err != nil {
+		return err
 	}
 
-	if !g.isStarted {
-		return errors.New("Game hasn't started yet")
+	err = g.checkVoter(player)
+	if err != nil {
+		return err
 	}
 
-	if !g.isDay {
-		return errors.New("Players can't vote during night")
+	if g.hasVoted(player) {
+		return NewError(ErrNotYourTurn, "Player has already voted, change the vote instead")
 	}
 
-	if !g.canVote(player) {
-		return errors.New("Player can't vote or doesn't exist")
+	err = g.checkDailyVictim(victim)
+	if err != nil {
+		return err
+	}
+
+	g.dailyVotes[player] = victim
+	g.onVoteCast()
+	return nil
+}
+
+func (g *Game) ChangeVote(player string, victim string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.apply(Event{Type: EventChangeVote, Player: player, Target: victim})
+}
+
+func (g *Game) changeVote(player string, victim string) error {
+	log.Println("ChangeVote: ", player, " -> ", victim)
+
+	err := g.checkVotingTime()
+	if err != nil {
+		return err
 	}
 
-	if !g.canBeKilled(victim) {
-		return errors.New("Victim can't be killed or doesn't exist")
+	err = g.checkVoter(player)
+	if err != nil {
+		return err
 	}
 
-	g.names2votes[victim]++
-	g.votes++
+	if !g.hasVoted(player) {
+		return NewError(ErrNotYourTurn, "Player hasn't voted yet")
+	}
 
-	pInfo := g.names2players[player]
-	pInfo.hasVoted = true
-	g.names2players[player] = pInfo
+	err = g.checkDailyVictim(victim)
+	if err != nil {
+		return err
+	}
 
-	g.continueIfPossible()
+	g.dailyVotes[player] = victim
+	g.onVoteCast()
 	return nil
 }
 
+func (g *Game) WithdrawVote(player string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.apply(Event{Type: EventWithdrawVote, Player: player})
+}
+
+func (g *Game) withdrawVote(player string) error {
+	log.Println("WithdrawVote: ", player)
+
+	err := g.checkVotingTime()
+	if err != nil {
+		return err
+	}
+
+	err = g.checkVoter(player)
+	if err != nil {
+		return err
+	}
+
+	if !g.hasVoted(player) {
+		return NewError(ErrNotYourTurn, "Player hasn't voted yet")
+	}
+
+	delete(g.dailyVotes, player)
+	g.onVoteCast()
+	return nil
+}
+
+/*
+	Skipped vote counts as voted, but isn't given to anybody
+*/
+func (g *Game) SkipVote(player string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.apply(Event{Type: EventSkipVote, Player: player})
+}
+
+func (g *Game) skipVote(player string) error {
+	log.Println("SkipVote: ", player)
+
+	err := g.checkVotingTime()
+	if err != nil {
+		return err
+	}
+
+	err = g.checkVoter(player)
+	if err != nil {
+		return err
+	}
+
+	if g.hasVoted(player) {
+		return NewError(ErrNotYourTurn, "Player has already voted, change the vote instead")
+	}
+
+	g.dailyVotes[player] = ""
+	g.onVoteCast()
+	return nil
+}
+
+/*
+	Votes of the current day. Who voted for whom is shown only in open-ballot sessions
+*/
+func (g *Game) GetVotes(player string) (*mafia_grpc.VoteTally, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	_, exists := g.names2players[player]
+	_, isSpectator := g.spectators[player]
+	if !exists && !isSpectator {
+		return nil, NewError(ErrNoPlayer, "Player doesn't exist")
+	}
+
+	return g.getVoteTally(g.config.OpenBallot), nil
+}
+
 func (g *Game) KillPlayer(mafia string, victim string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.apply(Event{Type: EventKillPlayer, Player: mafia, Target: victim})
+}
+
+func (g *Game) killPlayer(mafia string, victim string) error {
 	log.Println("KillPlayer: ", mafia, " -> ", victim)
 
 	if g.isFinished {
-		return errors.New("Game has finished already")
+		return NewError(ErrGameFinished, "Game has finished already")
 	}
 
 	if !g.isStarted {
-		return errors.New("Game hasn't started yet")
+		return NewError(ErrNotStarted, "Game hasn't started yet")
 	}
 
 	if g.isDay {
-		return errors.New("Mafia can't kill during day")
+		return NewError(ErrWrongPhase, "Mafia can't kill during day")
 	}
 
-	if !g.canKill(mafia) {
-		return errors.New("Player is not mafia or doesn't exists")
+	err := g.checkMafia(mafia)
+	if err != nil {
+		return err
 	}
 
-	if !g.canBeKilled(victim) {
-		return errors.New("Victim can't be killed or doesn't exist")
+	err = g.checkTarget(victim)
+	if err != nil {
+		return err
 	}
 
-	g.mafiaChoice = victim
+	g.mafiaVotes[mafia] = victim
 
 	g.continueIfPossible()
 	return nil
 }
 
 func (g *Game) CheckIfMafia(detective string, suggestedMafia string) (bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	err := g.apply(Event{Type: EventCheckIfMafia, Player: detective, Target: suggestedMafia})
+	if err != nil {
+		return false, err
+	}
+
+	return g.names2players[suggestedMafia].role == Mafia, nil
+}
+
+func (g *Game) checkIfMafia(detective string, suggestedMafia string) error {
 	log.Println("CheckIfMafia: ", detective, " -> ", suggestedMafia)
 
 	if g.isFinished {
-		return false, errors.New("Game has finished already")
+		return NewError(ErrGameFinished, "Game has finished already")
 	}
 
 	if !g.isStarted {
-		return false, errors.New("Game hasn't started yet")
+		return NewError(ErrNotStarted, "Game hasn't started yet")
 	}
 
 	if g.isDay {
-		return false, errors.New("Detective can't check during the day")
+		return NewError(ErrWrongPhase, "Detective can't check during the day")
 	}
 
-	if !g.canCheck(detective) {
-		return false, errors.New("Player isn't detective or doesn't exist")
+	err := g.checkDetective(detective)
+	if err != nil {
+		return err
 	}
 
-	if !g.canBeChecked(suggestedMafia) {
-		return false, errors.New("Suggested Mafia is already dead or doesn't exist")
+	err = g.checkTarget(suggestedMafia)
+	if err != nil {
+		return err
 	}
 
-	g.detectiveChecked = true
+	info := g.names2players[detective]
+	info.hasChecked = true
+	g.names2players[detective] = info
+
 	g.continueIfPossible()
-	return g.names2players[suggestedMafia].role == Mafia, nil
+	return nil
 }
 
-func (g *Game) GetNotifications(player string) (*chan *mafia_grpc.Notification, error) {
-	info, exists := g.names2players[player]
-	if !exists {
-		return nil, errors.New("Player doesn't exist")
-	}
+func (g *Game) HealPlayer(doctor string, patient string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 
-	return info.notifications, nil
+	return g.apply(Event{Type: EventHealPlayer, Player: doctor, Target: patient})
 }
 
-func (g *Game) CanChat(player string) (bool, error) {
-	info, exists := g.names2players[player]
-	if !exists {
-		return false, errors.New("Player doesn't exist")
+func (g *Game) healPlayer(doctor string, patient string) error {
+	log.Println("HealPlayer: ", doctor, " -> ", patient)
+
+	if g.isFinished {
+		return NewError(ErrGameFinished, "Game has finished already")
 	}
 
-	canChat := info.isAlive && g.isDay && g.isStarted && !g.isFinished
+	if !g.isStarted {
+		return NewError(ErrNotStarted, "Game hasn't started yet")
+	}
 
-	return canChat, nil
-}
+	if g.isDay {
+		return NewError(ErrWrongPhase, "Doctor can't heal during the day")
+	}
 
-func (g *Game) DeletePlayer(player string) error {
-	info, exists := g.names2players[player]
-	if !exists {
-		return errors.New("Player doesn't exist")
+	err := g.checkDoctor(doctor)
+	if err != nil {
+		return err
 	}
 
-	if info.isAlive {
-		g.kill(player)
+	err = g.checkTarget(patient)
+	if err != nil {
+		return err
 	}
 
-	info = g.names2players[player]
-	if info.notifications != nil {
-		close(*info.notifications)
-		info.notifications = nil
-		g.names2players[player] = info
+	info := g.names2players[doctor]
+	if patient == doctor && !g.config.DoctorSelfHeal {
+		return NewError(ErrRuleViolation, "Doctor can't heal themself")
 	}
 
-	g.checkIfFinished()
-	return nil
-}
+	if patient == info.lastHealed && !g.config.DoctorRepeatHeal {
+		return NewError(ErrRuleViolation, "Doctor can't heal the same player two nights in a row")
+	}
 
-/////////////////////////////////////////////// checkers ////////////////////////////////////////////////////
+	info.healed = patient
+	g.names2players[doctor] = info
 
-func (g *Game) canCheck(detective string) bool {
-	info, exists := g.names2players[detective]
-	return exists && info.isAlive && info.role == Detective && !g.detectiveChecked
+	g.continueIfPossible()
+	return nil
 }
 
-func (g *Game) canBeChecked(suggestedMafia string) bool {
-	info, exists := g.names2players[suggestedMafia]
-	return exists && info.isAlive
-}
+/*
+	Checks that the call is made by the player or the spectator the token was given to
+*/
+func (g *Game) Authenticate(name string, token string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 
-func (g *Game) isDayOver() bool {
-	if !g.isDay {
-		return false
+	if token == "" {
+		return NewError(ErrInvalidToken, "Token is missing")
 	}
 
-	return g.votes >= g.alivePlayers
-}
+	if spectator, isSpectator := g.spectators[name]; isSpectator {
+		if !equalTokens(spectator.token, token) {
+			return NewError(ErrInvalidToken, "Invalid token")
+		}
 
-func (g *Game) isNightOver() bool {
-	if g.isDay {
-		return false
+		return nil
 	}
 
-	detective := g.getDetective()
-	detectiveInfo := g.names2players[detective]
-	detectiveChecked := !detectiveInfo.isAlive || g.detectiveChecked
+	info, exists := g.names2players[name]
+	if !exists {
+		return NewError(ErrNoPlayer, "Player doesn't exist")
+	}
+
+	if !equalTokens(info.token, token) {
+		return NewError(ErrInvalidToken, "Invalid token")
+	}
 
-	return g.mafiaChoice != "" && detectiveChecked
+	return nil
 }
 
-//////////////////////////////////////////
+/*
+	Connects the player to the notification queue and returns it together with
+	a snapshot of the game, which must be sent before the queued notifications.
+	Previous connection of the player is closed, the queued notifications
+	are moved to the new one
+*/
+func (g *Game) GetNotifications(player string, token string) (<-chan *mafia_grpc.Notification, *mafia_grpc.Notification, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 
-func (g *Game) continueIfPossible() {
-	if !g.isDayOver() && !g.isNightOver() {
-		return
+	if _, isSpectator := g.spectators[player]; isSpectator {
+		return g.connectSpectator(player, token)
 	}
 
-	var victim string
-	if g.isDay {
-		victim = g.determineDailyVictim()
-	} else {
-		victim = g.mafiaChoice
+	info, exists := g.names2players[player]
+	if !exists {
+		return nil, nil, NewError(ErrNoPlayer, "Player doesn't exist")
 	}
 
-	if victim != "" {
-		g.kill(victim)
+	if !equalTokens(info.token, token) {
+		return nil, nil, NewError(ErrInvalidToken, "Invalid token")
 	}
 
-	if g.isDay {
-		if g.checkIfFinished() {
-			return
-		}
-		g.newNight()
-		g.notifyAll(g.getNotification(mafia_grpc.NotificationType_NEW_NIGHT, &victim))
-	} else {
-		if g.checkIfFinished() {
-			return
-		}
-		g.newDay()
-		g.notifyAll(g.getNotification(mafia_grpc.NotificationType_NEW_DAY, &victim))
+	if info.hasQuit {
+		return nil, nil, NewError(ErrPlayerLeft, "Player has already left the game")
 	}
-}
 
-func (g *Game) checkIfFinished() bool {
-	if g.isFinished {
-		return true
+	if g.isClosed {
+		return nil, nil, NewError(ErrGameClosed, "Game is closed")
 	}
 
-	mafia := g.getMafia()
-	info := g.names2players[mafia]
-	if !info.isAlive {
-		g.isFinished = true
-		g.notifyAll(g.getNotification(mafia_grpc.NotificationType_FINISH, nil))
-		return true
+	notifications := make(chan *mafia_grpc.Notification, g.config.NotificationLimit)
+	if info.notifications != nil {
+		close(info.notifications)
+		for notification := range info.notifications {
+			notifications <- notification
+		}
 	}
 
-	if g.alivePlayers > 1 {
-		return false
+	if info.graceTimer != nil {
+		info.graceTimer.Stop()
+		info.graceTimer = nil
 	}
 
-	g.isFinished = true
-	g.notifyAll(g.getNotification(mafia_grpc.NotificationType_FINISH, nil))
-	return true
+	info.notifications = notifications
+	info.isConnected = true
+	g.names2players[player] = info
+	g.touch()
+
+	return notifications, g.getSnapshot(player), nil
 }
 
-func (g *Game) canVote(player string) bool {
+/*
+	Called when the notification stream of the player or the spectator breaks. They
+	are removed from the game if they don't reconnect during the grace period.
+	Channel identifies the connection, so a stale stream can't disconnect the new one
+*/
+func (g *Game) Disconnect(player string, notifications <-chan *mafia_grpc.Notification) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if spectator, isSpectator := g.spectators[player]; isSpectator {
+		if spectator.notifications == notifications && spectator.isConnected {
+			g.disconnectSpectator(player)
+		}
+		return
+	}
+
 	info, exists := g.names2players[player]
-	return exists && info.isAlive && !info.hasVoted
-}
+	if !exists || info.notifications != notifications || !info.isConnected {
+		return
+	}
 
-func (g *Game) canKill(mafia string) bool {
-	info, exists := g.names2players[mafia]
-	return exists && info.isAlive && info.role == Mafia && g.mafiaChoice == ""
-}
+	// No event is being applied now, so the player without the grace period is removed at once
+	if g.config.ReconnectGrace == 0 {
+		info.isConnected = false
+		g.names2players[player] = info
+		log.Printf("Session %s: Player %s disconnected", g.session, player)
+		g.apply(Event{Type: EventReconnectTimeout, Player: player})
+		return
+	}
 
-func (g *Game) canBeKilled(victim string) bool {
-	info, exists := g.names2players[victim]
-	return exists && info.isAlive
+	g.disconnect(player)
 }
 
-//////////////////////////////////////////// Private methods ///////////////////////////////////////////////
+func (g *Game) CanChat(player string) (bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 
-func (g *Game) init(session string) {
-	g.session = session
-	g.names2players = make(map[string]playerInfo)
-	g.isStarted = false
-	g.isFinished = false
+	if _, isSpectator := g.spectators[player]; isSpectator {
+		return false, nil
+	}
 
-	g.alivePlayers = 0
-	g.date = 0
+	info, exists := g.names2players[player]
+	if !exists {
+		return false, NewError(ErrNoPlayer, "Player doesn't exist")
+	}
 
-	g.isDay = true
-	g.names2votes = make(map[string]int32)
-	g.votes = 0
-	g.mafiaChoice = ""
-	g.detectiveChecked = false
+	canChat := info.isAlive && g.isDay && g.isStarted && !g.isFinished
+
+	return canChat, nil
 }
 
-func (g *Game) generateRoles() {
-	roles := []Role{Mafia, Detective, Civilian, Civilian}
+func (g *Game) DeletePlayer(player string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 
-	rand.Seed(time.Now().UnixNano())
-	rand.Shuffle(len(roles), func(i, j int) {
-		roles[i], roles[j] = roles[j], roles[i]
-	})
+	if _, isSpectator := g.spectators[player]; isSpectator {
+		g.removeSpectator(player)
+		return nil
+	}
 
-	for name := range g.names2players {
-		player := g.names2players[name]
-		player.role = roles[0]
-		g.names2players[name] = player
+	return g.apply(Event{Type: EventDeletePlayer, Player: player})
+}
 
-		roles = roles[1:]
+func (g *Game) quit(player string) error {
+	_, exists := g.names2players[player]
+	if !exists {
+		return NewError(ErrNoPlayer, "Player doesn't exist")
 	}
 
-	log.Printf("Session %s: Roles assigned successfully!", g.session)
+	g.deletePlayer(player)
+	return nil
 }
 
-func (g *Game) start() {
-	g.generateRoles()
-	g.isStarted = true
-	g.newNight()
-	g.notifyStart()
+func (g *Game) ID() string {
+	return g.id
 }
 
-func (g *Game) newDay() {
-	g.isDay = true
-
-	g.names2votes = make(map[string]int32)
-	g.votes = 0
+func (g *Game) Session() string {
+	return g.session
 }
 
-func (g *Game) newNight() {
-	g.date++
-	g.isDay = false
-	g.mafiaChoice = ""
-	g.detectiveChecked = false
+func (g *Game) Config() Config {
+	return g.config
 }
 
 /*
-	Method is called only if all required checks are made
+	Checks the password of the private session, public session accepts any password
 */
-func (g *Game) kill(victim string) {
-	vInfo := g.names2players[victim]
-	vInfo.isAlive = false
-	g.names2players[victim] = vInfo
+func (g *Game) CheckPassword(password string) error {
+	if !g.config.checkPassword(password) {
+		return NewError(ErrNotAuthorized, "Wrong password")
+	}
 
-	g.alivePlayers--
+	return nil
 }
 
 /*
-	Method must be called only after all players have voted.
-	Returns name of dailyVictim. If votes split, then returns ""
+	Returns the lobby view of the session
 */
-func (g *Game) determineDailyVictim() string {
-	var maxValue int32 = 0
-	dailyVictim := ""
-	isSingle := true
-	for name, value := range g.names2votes {
-		if value > maxValue {
+func (g *Game) Info() *mafia_grpc.SessionInfo {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	status := mafia_grpc.SessionStatus_WAITING
+	if g.isFinished {
+		status = mafia_grpc.SessionStatus_FINISHED
+	} else if g.isStarted {
+		status = mafia_grpc.SessionStatus_STARTED
+	}
+
+	return &mafia_grpc.SessionInfo{
+		Session:    g.session,
+		GameId:     g.id,
+		Players:    int32(len(g.names2players)),
+		Capacity:   g.config.Players,
+		Status:     status,
+		Config:     g.config.ToProto(),
+		Private:    g.config.IsPrivate(),
+		Spectators: int32(len(g.spectators)),
+		Bots:       int32(len(g.getBots())),
+	}
+}
+
+func (g *Game) IsFinished() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.isFinished
+}
+
+/*
+	Finished game expires finishedTTL after the end, any other game expires
+	after idleTTL without actions of players. Zero TTL means no expiration
+*/
+func (g *Game) IsExpired(now time.Time, finishedTTL time.Duration, idleTTL time.Duration) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.isFinished {
+		return finishedTTL != 0 && now.Sub(g.finishedAt) > finishedTTL
+	}
+
+	return idleTTL != 0 && now.Sub(g.lastActivity) > idleTTL
+}
+
+/*
+	Stops all timers and closes notification streams of the players.
+	Closed game doesn't accept players and actions anymore
+*/
+func (g *Game) Close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.apply(Event{Type: EventClose})
+}
+
+/////////////////////////////////////////////// checkers ////////////////////////////////////////////////////
+
+/*
+	Checks that the player exists, is alive and has the role, empty role means any role
+*/
+func (g *Game) checkActor(player string, role Role) error {
+	if _, isSpectator := g.spectators[player]; isSpectator {
+		return NewError(ErrNotAuthorized, "Spectators can't act")
+	}
+
+	info, exists := g.names2players[player]
+	if !exists {
+		return NewError(ErrNoPlayer, "Player doesn't exist")
+	}
+
+	if !info.isAlive {
+		return NewError(ErrPlayerDead, "Player is dead")
+	}
+
+	if role != "" && info.role != role {
+		return NewError(ErrNotAuthorized, "Player isn't "+strings.ToLower(string(role)))
+	}
+
+	return nil
+}
+
+func (g *Game) checkTarget(target string) error {
+	info, exists := g.names2players[target]
+	if !exists {
+		return NewError(ErrTargetNotFound, "Player "+target+" doesn't exist")
+	}
+
+	if !info.isAlive {
+		return NewError(ErrTargetDead, "Player "+target+" is already dead")
+	}
+
+	return nil
+}
+
+func (g *Game) checkDetective(detective string) error {
+	err := g.checkActor(detective, Detective)
+	if err != nil {
+		return err
+	}
+
+	if g.names2players[detective].hasChecked {
+		return NewError(ErrNotYourTurn, "Detective has already checked a player tonight")
+	}
+
+	return nil
+}
+
+func (g *Game) checkDoctor(doctor string) error {
+	err := g.checkActor(doctor, Doctor)
+	if err != nil {
+		return err
+	}
+
+	if g.names2players[doctor].healed != "" {
+		return NewError(ErrNotYourTurn, "Doctor has already healed a player tonight")
+	}
+
+	return nil
+}
+
+func (g *Game) isDayOver() bool {
+	if !g.isDay {
+		return false
+	}
+
+	return int32(len(g.dailyVotes)) >= g.alivePlayers
+}
+
+func (g *Game) isNightOver() bool {
+	if g.isDay {
+		return false
+	}
+
+	for name, info := range g.names2players {
+		if info.isAlive && info.role == Detective && !info.hasChecked {
+			return false
+		}
+
+		if info.isAlive && info.role == Doctor && info.healed == "" && g.hasPatients(name) {
+			return false
+		}
+	}
+
+	return g.hasMafiaDecided()
+}
+
+/*
+	Doctor may have nobody to heal: themself and the player healed last night
+	may be the only ones alive, and the rules may forbid healing both
+*/
+func (g *Game) hasPatients(doctor string) bool {
+	lastHealed := g.names2players[doctor].lastHealed
+	for name, info := range g.names2players {
+		if !info.isAlive {
+			continue
+		}
+
+		if name == doctor && !g.config.DoctorSelfHeal {
+			continue
+		}
+
+		if name == lastHealed && !g.config.DoctorRepeatHeal {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+/*
+	Mafia has decided when every alive mafia has voted,
+	with unanimity rule all votes must also be for the same player
+*/
+func (g *Game) hasMafiaDecided() bool {
+	choice := ""
+	for name, info := range g.names2players {
+		if !info.isAlive || info.role != Mafia {
+			continue
+		}
+
+		victim, voted := g.mafiaVotes[name]
+		if !voted {
+			return false
+		}
+
+		if g.config.KillRule == mafia_grpc.KillRule_UNANIMOUS && choice != "" && choice != victim {
+			return false
+		}
+		choice = victim
+	}
+
+	return true
+}
+
+//////////////////////////////////////////
+
+func (g *Game) continueIfPossible() {
+	if !g.isDayOver() && !g.isNightOver() {
+		return
+	}
+
+	g.endPhase()
+}
+
+/*
+	Ends current day or night with the actions made so far and starts the next one
+*/
+func (g *Game) endPhase() {
+	if g.isDay && g.config.Runoff && !g.isRunoff {
+		candidates := g.getVoteLeaders()
+		if len(candidates) > 1 && !containsString(candidates, "") {
+			g.startRunoff(candidates)
+			return
+		}
+	}
+
+	victims := []string{}
+	if g.isDay {
+		victims = g.determineDailyVictims()
+		g.isRunoff = false
+		g.runoffCandidates = nil
+	} else {
+		victim := g.determineNightVictim()
+		if victim != "" && !g.isHealed(victim) {
+			victims = append(victims, victim)
+		}
+	}
+
+	killedPlayers := []string{}
+	for _, victim := range victims {
+		// Player who has quit may still be the target, they are dead already
+		if !g.names2players[victim].isAlive {
+			continue
+		}
+
+		g.kill(victim)
+		g.record(Event{Type: EventPlayerKilled, Target: victim, Date: g.date})
+		killedPlayers = append(killedPlayers, victim)
+	}
+	killed := strings.Join(killedPlayers, ", ")
+
+	if g.isDay {
+		if g.checkIfFinished() {
+			return
+		}
+		g.newNight()
+		g.notifyAll(g.getNotification(mafia_grpc.NotificationType_NEW_NIGHT, &killed))
+	} else {
+		if g.checkIfFinished() {
+			return
+		}
+		g.newDay()
+		g.notifyAll(g.getNotification(mafia_grpc.NotificationType_NEW_DAY, &killed))
+	}
+}
+
+func (g *Game) checkIfFinished() bool {
+	if g.isFinished {
+		return true
+	}
+
+	aliveMafia := g.countAliveMafia()
+	if aliveMafia > 0 && aliveMafia < g.alivePlayers {
+		return false
+	}
+
+	g.isFinished = true
+	g.finishedAt = g.now()
+	g.stopPhaseTimer()
+	g.record(Event{Type: EventFinished, Date: g.date})
+	g.notifyAll(g.getNotification(mafia_grpc.NotificationType_FINISH, nil))
+
+	if g.onFinish != nil && g.replay == nil {
+		g.onFinish(g.getResult())
+	}
+	return true
+}
+
+func (g *Game) checkVotingTime() error {
+	if g.isFinished {
+		return NewError(ErrGameFinished, "Game has finished already")
+	}
+
+	if !g.isStarted {
+		return NewError(ErrNotStarted, "Game hasn't started yet")
+	}
+
+	if !g.isDay {
+		return NewError(ErrWrongPhase, "Players can't vote during night")
+	}
+
+	return nil
+}
+
+func (g *Game) checkVoter(player string) error {
+	return g.checkActor(player, "")
+}
+
+func (g *Game) hasVoted(player string) bool {
+	_, voted := g.dailyVotes[player]
+	return voted
+}
+
+func (g *Game) checkDailyVictim(victim string) error {
+	err := g.checkTarget(victim)
+	if err != nil {
+		return err
+	}
+
+	if g.isRunoff && !containsString(g.runoffCandidates, victim) {
+		return NewError(ErrRuleViolation, "Only runoff candidates can be voted for")
+	}
+
+	return nil
+}
+
+func (g *Game) checkMafia(mafia string) error {
+	return g.checkActor(mafia, Mafia)
+}
+
+//////////////////////////////////////////// Private methods ///////////////////////////////////////////////
+
+func (g *Game) init(session string, config Config, seed int64) {
+	g.id = generateID()
+	g.session = session
+	g.config = config
+	g.seed = seed
+	g.rng = rand.New(rand.NewSource(seed))
+	g.names2players = make(map[string]playerInfo)
+	g.spectators = make(map[string]spectatorInfo)
+	g.isStarted = false
+	g.isFinished = false
+	g.isClosed = false
+	g.lastActivity = g.now()
+
+	g.alivePlayers = 0
+	g.date = 0
+
+	g.isDay = true
+	g.dailyVotes = make(map[string]string)
+	g.mafiaVotes = make(map[string]string)
+	g.mafiaLeader = ""
+}
+
+/*
+	During the replay the deal is taken from the log
+*/
+func (g *Game) generateRoles() {
+	if g.replay != nil {
+		if event, found := g.recorded(EventRolesAssigned); found {
+			g.assignRoles(event.Roles, event.MafiaLeader)
+			return
+		}
+	}
+
+	roles := g.config.roles()
+
+	g.rng.Shuffle(len(roles), func(i, j int) {
+		roles[i], roles[j] = roles[j], roles[i]
+	})
+
+	names := make([]string, 0, len(g.names2players))
+	for name := range g.names2players {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		player := g.names2players[name]
+		player.role = roles[i]
+		g.names2players[name] = player
+	}
+
+	mafiaTeam := g.getMafiaTeam()
+	g.mafiaLeader = mafiaTeam[g.rng.Intn(len(mafiaTeam))]
+
+	deal := make(map[string]Role)
+	for name, info := range g.names2players {
+		deal[name] = info.role
+	}
+
+	g.record(Event{Type: EventRolesAssigned, Roles: deal, MafiaLeader: g.mafiaLeader})
+	log.Printf("Session %s: Roles assigned successfully!", g.session)
+}
+
+func (g *Game) assignRoles(deal map[string]Role, mafiaLeader string) {
+	for name, role := range deal {
+		info := g.names2players[name]
+		info.role = role
+		g.names2players[name] = info
+	}
+
+	g.mafiaLeader = mafiaLeader
+	g.record(Event{Type: EventRolesAssigned, Roles: deal, MafiaLeader: mafiaLeader})
+	log.Printf("Session %s: Roles assigned from the log", g.session)
+}
+
+func (g *Game) start() {
+	g.generateRoles()
+	g.isStarted = true
+	g.newNight()
+	g.notifyStart()
+}
+
+func (g *Game) newDay() {
+	g.isDay = true
+	g.resetVotes()
+	g.startPhaseTimer(g.config.DayDuration)
+	g.record(Event{Type: EventNewDay, Date: g.date})
+}
+
+/*
+	Runoff is a repeated vote of the same day limited to the tied candidates
+*/
+func (g *Game) startRunoff(candidates []string) {
+	g.isRunoff = true
+	g.runoffCandidates = candidates
+	g.resetVotes()
+	g.startPhaseTimer(g.config.DayDuration)
+	g.record(Event{Type: EventRunoff, Date: g.date})
+	g.notifyAll(g.getNotification(mafia_grpc.NotificationType_RUNOFF, nil))
+}
+
+func (g *Game) resetVotes() {
+	g.dailyVotes = make(map[string]string)
+}
+
+func (g *Game) onVoteCast() {
+	if g.config.OpenBallot {
+		g.notifyAll(g.getNotification(mafia_grpc.NotificationType_VOTE_CAST, nil))
+	}
+
+	g.continueIfPossible()
+}
+
+func (g *Game) newNight() {
+	g.date++
+	g.isDay = false
+	g.mafiaVotes = make(map[string]string)
+	g.startPhaseTimer(g.config.NightDuration)
+	g.record(Event{Type: EventNewNight, Date: g.date})
+
+	for name, info := range g.names2players {
+		info.hasChecked = false
+		info.lastHealed = info.healed
+		info.healed = ""
+		g.names2players[name] = info
+	}
+}
+
+/*
+	Every phase gets its own number, so the timer of a phase
+	that has already ended doesn't affect the next one
+*/
+func (g *Game) startPhaseTimer(duration time.Duration) {
+	g.stopPhaseTimer()
+	g.phase++
+
+	if duration == 0 || g.replay != nil {
+		return
+	}
+
+	g.phaseDeadline = time.Now().Add(duration)
+	g.armPhaseTimer()
+}
+
+/*
+	Ends the current phase at phaseDeadline
+*/
+func (g *Game) armPhaseTimer() {
+	phase := g.phase
+	g.phaseTimer = time.AfterFunc(time.Until(g.phaseDeadline), func() {
+		g.onPhaseTimeout(phase)
+	})
+}
+
+func (g *Game) stopPhaseTimer() {
+	if g.phaseTimer != nil {
+		g.phaseTimer.Stop()
+		g.phaseTimer = nil
+	}
+
+	g.phaseDeadline = time.Time{}
+}
+
+func (g *Game) onPhaseTimeout(phase int32) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if phase != g.phase || !g.isStarted || g.isFinished || g.isClosed {
+		return
+	}
+
+	g.apply(Event{Type: EventPhaseTimeout})
+}
+
+func (g *Game) timeOutPhase() error {
+	if !g.isStarted || g.isFinished {
+		return NewError(ErrWrongPhase, "Game isn't in progress")
+	}
+
+	log.Printf("Session %s: Time is up, phase %d is over", g.session, g.phase)
+	g.endPhase()
+	return nil
+}
+
+/*
+	Method is called only if all required checks are made
+*/
+func (g *Game) kill(victim string) {
+	vInfo := g.names2players[victim]
+	vInfo.isAlive = false
+	g.names2players[victim] = vInfo
+
+	g.alivePlayers--
+	delete(g.dailyVotes, victim)
+
+	if victim == g.mafiaLeader {
+		g.mafiaLeader = g.nextMafiaLeader()
+	}
+}
+
+/*
+	Method must be called only after all players have voted or the day is over.
+	Returns names of daily victims. If most players skipped, then nobody is killed.
+	If votes split, then nobody is killed unless the runoff fallback says otherwise
+*/
+func (g *Game) determineDailyVictims() []string {
+	leaders := g.getVoteLeaders()
+	if containsString(leaders, "") {
+		return []string{}
+	}
+
+	if len(leaders) <= 1 {
+		return leaders
+	}
+
+	if !g.isRunoff {
+		return []string{}
+	}
+
+	switch g.config.RunoffFallback {
+	case mafia_grpc.RunoffFallback_RANDOM:
+		victim := leaders[g.rng.Intn(len(leaders))]
+		if g.replay != nil {
+			if event, found := g.recorded(EventRandomVictim); found {
+				victim = event.Target
+			}
+		}
+
+		g.record(Event{Type: EventRandomVictim, Target: victim, Date: g.date})
+		return []string{victim}
+	case mafia_grpc.RunoffFallback_ALL_TIED:
+		return leaders
+	}
+
+	return []string{}
+}
+
+/*
+	Returns sorted names of players with the most votes, skipped votes are counted for ""
+*/
+func (g *Game) getVoteLeaders() []string {
+	var maxValue int32 = 0
+	leaders := []string{}
+	for name, value := range g.countVotes() {
+		if value > maxValue {
+			maxValue = value
+			leaders = []string{name}
+		} else if value == maxValue {
+			leaders = append(leaders, name)
+		}
+	}
+
+	sort.Strings(leaders)
+	return leaders
+}
+
+/*
+	Method must be called only after all alive mafia have voted or the night is over.
+	Returns name of the night victim chosen according to the kill rule.
+	If mafia didn't come to a decision, then returns ""
+*/
+func (g *Game) determineNightVictim() string {
+	aliveMafia := g.countAliveMafia()
+	names2votes := make(map[string]int32)
+	for mafia, victim := range g.mafiaVotes {
+		if g.names2players[mafia].isAlive {
+			names2votes[victim]++
+		}
+	}
+
+	var maxValue int32 = 0
+	nightVictim := ""
+	isSingle := true
+	for name, value := range names2votes {
+		if value > maxValue {
 			maxValue = value
-			dailyVictim = name
+			nightVictim = name
 			isSingle = true
 		} else if value == maxValue {
 			isSingle = false
 		}
 	}
 
-	if isSingle {
-		return dailyVictim
+	switch g.config.KillRule {
+	case mafia_grpc.KillRule_UNANIMOUS:
+		if maxValue == aliveMafia {
+			return nightVictim
+		}
+	case mafia_grpc.KillRule_MAJORITY:
+		if 2*maxValue > aliveMafia {
+			return nightVictim
+		}
+	case mafia_grpc.KillRule_LEADER:
+		if isSingle {
+			return nightVictim
+		}
+		return g.mafiaVotes[g.mafiaLeader]
 	}
 
 	return ""
 }
 
-func (g *Game) notifyStart() {
+func (g *Game) isHealed(victim string) bool {
 	for _, info := range g.names2players {
-		notification := g.getNotification(mafia_grpc.NotificationType_START, (*string)(&info.role))
-		if info.notifications != nil {
-			*info.notifications <- notification
+		if info.isAlive && info.role == Doctor && info.healed == victim {
+			return true
 		}
 	}
+
+	return false
 }
 
-func (g *Game) notifyAll(notification *mafia_grpc.Notification) {
-	for _, info := range g.names2players {
-		log.Println("Notification: ", notification.Type)
+func (g *Game) touch() {
+	g.lastActivity = g.now()
+}
+
+/*
+	Player who leaves before the start frees the seat
+*/
+func (g *Game) removeFromLobby(player string) {
+	info := g.names2players[player]
+	if info.notifications != nil {
+		close(info.notifications)
+	}
+
+	if info.graceTimer != nil {
+		info.graceTimer.Stop()
+	}
+
+	delete(g.names2players, player)
+	g.alivePlayers--
+}
+
+func (g *Game) close() {
+	if g.isClosed {
+		return
+	}
+
+	g.isClosed = true
+	g.isFinished = true
+	g.stopPhaseTimer()
+
+	for name, info := range g.names2players {
 		if info.notifications != nil {
-			*info.notifications <- notification
+			close(info.notifications)
+			info.notifications = nil
+		}
+
+		if info.graceTimer != nil {
+			info.graceTimer.Stop()
+			info.graceTimer = nil
+		}
+
+		info.isConnected = false
+		g.names2players[name] = info
+	}
+
+	for name := range g.spectators {
+		g.removeSpectator(name)
+	}
+
+	log.Printf("Session %s: Game %s closed", g.session, g.id)
+}
+
+/*
+	Notifies players about the next game of the session and closes this one.
+	Returns names of the players
+*/
+func (g *Game) offerRematch(nextID string) map[string]string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	players := make(map[string]string)
+	for name, info := range g.names2players {
+		if !info.hasQuit {
+			players[name] = info.token
 		}
 	}
+
+	g.notifyAll(g.getNotification(mafia_grpc.NotificationType_REMATCH, &nextID))
+	g.apply(Event{Type: EventClose})
+	return players
 }
 
-///////////////////////////////////////////// getters //////////////////////////////////////////////////
+/*
+	Player who leaves the started game is dead for the rest of it.
+	The phase may have waited only for them, so it ends if everyone else has acted
+*/
+func (g *Game) deletePlayer(player string) {
+	if !g.isStarted {
+		g.removeFromLobby(player)
+		return
+	}
+
+	info := g.names2players[player]
+	if info.isAlive {
+		g.kill(player)
+	}
+
+	info = g.names2players[player]
+	if info.notifications != nil {
+		close(info.notifications)
+		info.notifications = nil
+	}
+
+	if info.graceTimer != nil {
+		info.graceTimer.Stop()
+		info.graceTimer = nil
+	}
+
+	info.hasQuit = true
+	info.isConnected = false
+	g.names2players[player] = info
+
+	if !g.checkIfFinished() {
+		g.continueIfPossible()
+	}
+}
 
-func (g *Game) getMafia() string {
+/*
+	Marks player as disconnected and gives them time to reconnect.
+	The notification channel stays open to collect missed notifications.
+	Without the grace period the timer fires at once, but after the current event:
+	the player may be disconnected while the notifications of the event are sent
+*/
+func (g *Game) disconnect(player string) {
+	info := g.names2players[player]
+	info.isConnected = false
+	log.Printf("Session %s: Player %s disconnected", g.session, player)
+
+	token := info.token
+	info.graceTimer = time.AfterFunc(g.config.ReconnectGrace, func() {
+		g.onReconnectTimeout(player, token)
+	})
+	g.names2players[player] = info
+}
+
+func (g *Game) onReconnectTimeout(player string, token string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	info := g.names2players[player]
+	if info.token != token || info.isConnected || info.hasQuit || g.isClosed {
+		return
+	}
+
+	g.apply(Event{Type: EventReconnectTimeout, Player: player})
+}
+
+func (g *Game) timeOutReconnect(player string) error {
+	info, exists := g.names2players[player]
+	if !exists || info.hasQuit {
+		return NewError(ErrNoPlayer, "Player doesn't exist")
+	}
+
+	log.Printf("Session %s: Player %s didn't reconnect in time", g.session, player)
+	g.deletePlayer(player)
+	return nil
+}
+
+func (g *Game) notifyStart() {
 	for name, info := range g.names2players {
+		notification := g.getNotification(mafia_grpc.NotificationType_START, (*string)(&info.role))
 		if info.role == Mafia {
-			return name
+			notification.MafiaTeam = g.getMafiaTeam()
+			notification.MafiaLeader = g.mafiaLeader
 		}
+
+		g.push(name, notification)
 	}
 
-	return ""
+	role := ""
+	g.notifySpectators(g.getNotification(mafia_grpc.NotificationType_START, &role))
+}
+
+func (g *Game) notifyAll(notification *mafia_grpc.Notification) {
+	log.Println("Notification: ", notification.Type)
+	for name := range g.names2players {
+		g.push(name, notification)
+	}
+
+	g.notifySpectators(notification)
+}
+
+/*
+	Puts notification to the player's queue without blocking.
+	If the queue is full, then the overflow policy is applied.
+	Method must be called with mu locked: channels are written and closed
+	only under mu, so after freeing a slot the send can't block
+*/
+func (g *Game) push(player string, notification *mafia_grpc.Notification) {
+	info := g.names2players[player]
+	if info.notifications == nil || g.enqueue(player, info.notifications, notification) {
+		return
+	}
+
+	close(info.notifications)
+	info.notifications = nil
+	g.names2players[player] = info
+
+	if info.isConnected {
+		g.disconnect(player)
+	}
+}
+
+/*
+	Returns false if the queue is full and the Disconnect policy says to close it
+*/
+func (g *Game) enqueue(name string, queue chan *mafia_grpc.Notification, notification *mafia_grpc.Notification) bool {
+	select {
+	case queue <- notification:
+		return true
+	default:
+	}
+
+	if g.config.OverflowPolicy == Disconnect {
+		log.Printf("Session %s: Queue of %s is full, %s disconnected", g.session, name, name)
+		return false
+	}
+
+	select {
+	case dropped := <-queue:
+		log.Printf("Session %s: Queue of %s is full, notification %s dropped", g.session, name, dropped.Type)
+	default:
+	}
+
+	queue <- notification
+	return true
 }
 
-func (g *Game) getDetective() string {
+///////////////////////////////////////////// getters //////////////////////////////////////////////////
+
+func (g *Game) getMafiaTeam() []string {
+	mafiaTeam := []string{}
 	for name, info := range g.names2players {
-		if info.role == Detective {
+		if info.role == Mafia {
+			mafiaTeam = append(mafiaTeam, name)
+		}
+	}
+
+	sort.Strings(mafiaTeam)
+	return mafiaTeam
+}
+
+/*
+	Returns alive mafia with the smallest name, it takes the place of the dead leader
+*/
+func (g *Game) nextMafiaLeader() string {
+	for _, name := range g.getMafiaTeam() {
+		if g.names2players[name].isAlive {
 			return name
 		}
 	}
@@ -460,7 +1555,25 @@ func (g *Game) getDetective() string {
 	return ""
 }
 
+func (g *Game) countAliveMafia() int32 {
+	var aliveMafia int32 = 0
+	for _, info := range g.names2players {
+		if info.isAlive && info.role == Mafia {
+			aliveMafia++
+		}
+	}
+
+	return aliveMafia
+}
+
 func (g *Game) GetAlivePlayers() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.getAlivePlayers()
+}
+
+func (g *Game) getAlivePlayers() []string {
 	alivePlayers := []string{}
 	for name, info := range g.names2players {
 		if info.isAlive {
@@ -472,18 +1585,118 @@ func (g *Game) GetAlivePlayers() []string {
 }
 
 func (g *Game) GetGameState() *mafia_grpc.GameState {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.getGameState()
+}
+
+func (g *Game) getGameState() *mafia_grpc.GameState {
 	return &mafia_grpc.GameState{
-		Session:      g.session,
-		AlivePlayers: g.GetAlivePlayers(),
-		Date:         g.date,
-		IsDay:        g.isDay,
-		IsStarted:    g.isStarted,
-		IsFinished:   g.isFinished,
+		GameId:           g.id,
+		Session:          g.session,
+		AlivePlayers:     g.getAlivePlayers(),
+		Date:             g.date,
+		IsDay:            g.isDay,
+		IsStarted:        g.isStarted,
+		IsFinished:       g.isFinished,
+		PhaseDeadline:    g.getPhaseDeadline(),
+		Seed:             g.getRevealedSeed(),
+		Spectators:       int32(len(g.spectators)),
+		Bots:             g.getBots(),
+		IsRunoff:         g.isRunoff,
+		RunoffCandidates: g.runoffCandidates,
+	}
+}
+
+/*
+	Returns number of votes for every alive player, skipped votes are counted for ""
+*/
+func (g *Game) countVotes() map[string]int32 {
+	names2votes := make(map[string]int32)
+	for _, victim := range g.dailyVotes {
+		if victim == "" || g.names2players[victim].isAlive {
+			names2votes[victim]++
+		}
+	}
+
+	return names2votes
+}
+
+func (g *Game) getVoteTally(withVoters bool) *mafia_grpc.VoteTally {
+	tally := &mafia_grpc.VoteTally{
+		Counts: make(map[string]int32),
+		Voted:  int32(len(g.dailyVotes)),
+	}
+
+	for name, value := range g.countVotes() {
+		if name == "" {
+			tally.Skips = value
+		} else {
+			tally.Counts[name] = value
+		}
+	}
+
+	if withVoters {
+		for voter, victim := range g.dailyVotes {
+			tally.Votes = append(tally.Votes, &mafia_grpc.Vote{Voter: voter, Victim: victim})
+		}
+
+		sort.Slice(tally.Votes, func(i, j int) bool {
+			return tally.Votes[i].Voter < tally.Votes[j].Voter
+		})
+	}
+
+	return tally
+}
+
+/*
+	Seed is hidden until the end, otherwise players could compute the deal
+*/
+func (g *Game) getRevealedSeed() int64 {
+	if !g.isFinished {
+		return 0
+	}
+
+	return g.seed
+}
+
+func (g *Game) getPhaseDeadline() int64 {
+	if g.phaseDeadline.IsZero() {
+		return 0
+	}
+
+	return g.phaseDeadline.UnixMilli()
+}
+
+/*
+	Returns STATE notification with everything the player should know
+	about the game: the state, their role and the mafia team for mafia
+*/
+func (g *Game) getSnapshot(player string) *mafia_grpc.Notification {
+	info := g.names2players[player]
+	notification := &mafia_grpc.Notification{
+		Type:      mafia_grpc.NotificationType_STATE,
+		GameState: g.getGameState(),
+		Details: &mafia_grpc.Notification_Role{
+			Role: string(info.role),
+		},
+	}
+
+	if info.role == Mafia {
+		notification.MafiaTeam = g.getMafiaTeam()
+		notification.MafiaLeader = g.mafiaLeader
+	}
+
+	if g.isDay && g.isStarted {
+		notification.VoteTally = g.getVoteTally(g.config.OpenBallot)
 	}
+
+	return notification
 }
 
 func (g *Game) getNotification(nType mafia_grpc.NotificationType, detail *string) *mafia_grpc.Notification {
-	state := g.GetGameState()
+	state := g.getGameState()
 
 	switch nType {
 	case mafia_grpc.NotificationType_START:
@@ -495,12 +1708,14 @@ func (g *Game) getNotification(nType mafia_grpc.NotificationType, detail *string
 			},
 		}
 	case mafia_grpc.NotificationType_FINISH:
+		mafiaTeam := g.getMafiaTeam()
 		return &mafia_grpc.Notification{
 			Type:      nType,
 			GameState: state,
 			Details: &mafia_grpc.Notification_Mafia{
-				Mafia: g.getMafia(),
+				Mafia: strings.Join(mafiaTeam, ", "),
 			},
+			MafiaTeam: mafiaTeam,
 		}
 	case mafia_grpc.NotificationType_NEW_DAY:
 		return &mafia_grpc.Notification{
@@ -510,6 +1725,25 @@ func (g *Game) getNotification(nType mafia_grpc.NotificationType, detail *string
 				KilledPlayer: *detail,
 			},
 		}
+	case mafia_grpc.NotificationType_VOTE_CAST:
+		return &mafia_grpc.Notification{
+			Type:      nType,
+			GameState: state,
+			VoteTally: g.getVoteTally(true),
+		}
+	case mafia_grpc.NotificationType_RUNOFF:
+		return &mafia_grpc.Notification{
+			Type:      nType,
+			GameState: state,
+		}
+	case mafia_grpc.NotificationType_REMATCH:
+		return &mafia_grpc.Notification{
+			Type:      nType,
+			GameState: state,
+			Details: &mafia_grpc.Notification_RematchGameId{
+				RematchGameId: *detail,
+			},
+		}
 	case mafia_grpc.NotificationType_NEW_NIGHT:
 		return &mafia_grpc.Notification{
 			Type:      nType,
@@ -523,17 +1757,93 @@ func (g *Game) getNotification(nType mafia_grpc.NotificationType, detail *string
 	}
 }
 
+func generateToken() (string, error) {
+	bytes := make([]byte, 16)
+	_, err := crand.Read(bytes)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(bytes), nil
+}
+
+/*
+	Compares tokens in constant time, so the time doesn't tell how much of the token is right
+*/
+func equalTokens(expected string, actual string) bool {
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(actual)) == 1
+}
+
+/*
+	Game id doesn't have to be secret, so the clock is enough if there is no random
+*/
+func generateID() string {
+	bytes := make([]byte, 8)
+	_, err := crand.Read(bytes)
+	if err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+
+	return hex.EncodeToString(bytes)
+}
+
+/*
+	Logs and snapshots written before the seed was kept apart from the config have it in the config
+*/
+func savedSeed(seed int64, config Config) int64 {
+	if seed == 0 {
+		return config.Seed
+	}
+
+	return seed
+}
+
+func generateSeed() int64 {
+	bytes := make([]byte, 8)
+	_, err := crand.Read(bytes)
+	if err != nil {
+		return time.Now().UnixNano()
+	}
+
+	seed := int64(binary.LittleEndian.Uint64(bytes))
+	if seed == 0 {
+		seed = 1
+	}
+
+	return seed
+}
+
+func containsString(slice []string, str string) bool {
+	for _, value := range slice {
+		if value == str {
+			return true
+		}
+	}
+	return false
+}
+
 ////////////////////////////////////////// Pretty print ////////////////////////////////////////////////
 
 func (g *Game) String() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
 	var sb strings.Builder
 	sb.WriteString("Game Info:\n")
 	sb.WriteString("Session: " + string(g.session) + "\n")
-	sb.WriteString(g.Players2String())
+	sb.WriteString("Game: " + g.id + "\n")
+	sb.WriteString(g.players2String())
 	return sb.String()
 }
 
 func (g *Game) Players2String() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.players2String()
+}
+
+func (g *Game) players2String() string {
 	var sb strings.Builder
 
 	sb.WriteString("Players:\n")
@@ -0,0 +1,1361 @@
+package mafia_impl
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"soa_mafia/pkg/mafia_grpc"
+)
+
+/*
+	Game logs every call, it's too much for the tests
+*/
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+/*
+	Creates the game with players p1..pN, it starts when the last one joins.
+	Seed is fixed unless the config has one, so the deal is the same in every run.
+	Returns tokens of the players
+*/
+func newStartedGame(t *testing.T, config Config) (*Game, map[string]string) {
+	t.Helper()
+
+	if config.Seed == 0 {
+		config.Seed = 1
+	}
+
+	game := NewGame("test", config)
+	tokens := make(map[string]string)
+	for i := int32(1); i <= config.Players; i++ {
+		name := fmt.Sprintf("p%d", i)
+		token, err := game.AddPlayer(name)
+		if err != nil {
+			t.Fatalf("AddPlayer(%s): %v", name, err)
+		}
+		tokens[name] = token
+	}
+
+	if !game.GetGameState().GetIsStarted() {
+		t.Fatal("Game must start when the table is full")
+	}
+
+	return game, tokens
+}
+
+/*
+	Returns sorted names of the alive players with the role
+*/
+func playersWith(game *Game, role Role) []string {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	names := []string{}
+	for name, info := range game.names2players {
+		if info.isAlive && info.role == role {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+func countEvents(game *Game, eventType EventType, target string) int {
+	count := 0
+	for _, event := range game.Events() {
+		if event.Type == eventType && event.Target == target {
+			count++
+		}
+	}
+
+	return count
+}
+
+func mustSucceed(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustFail(t *testing.T, err error, kind *GameError) {
+	t.Helper()
+
+	if !errors.Is(err, kind) {
+		t.Fatalf("Error must be %s, got %v", kind.Reason, err)
+	}
+}
+
+func checkAliveCount(t *testing.T, game *Game) {
+	t.Helper()
+
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	if int(game.alivePlayers) != len(game.getAlivePlayers()) {
+		t.Fatalf("Game counts %d alive players, but %d are alive", game.alivePlayers, len(game.getAlivePlayers()))
+	}
+}
+
+func TestNightVictimWhoQuitIsNotKilledAgain(t *testing.T) {
+	config := NewConfig(6)
+	config.Roles = map[Role]int32{Mafia: 2, Detective: 1}
+	game, _ := newStartedGame(t, config)
+
+	civilians := playersWith(game, Civilian)
+	victim := civilians[0]
+	for _, mafia := range playersWith(game, Mafia) {
+		mustSucceed(t, game.KillPlayer(mafia, victim))
+	}
+
+	mustSucceed(t, game.DeletePlayer(victim))
+	_, err := game.CheckIfMafia(playersWith(game, Detective)[0], civilians[1])
+	mustSucceed(t, err)
+
+	state := game.GetGameState()
+	if !state.GetIsDay() || state.GetDate() != 1 {
+		t.Fatalf("Night must be over, state: %s", state)
+	}
+
+	if len(state.GetAlivePlayers()) != 5 {
+		t.Fatalf("Only the player who quit must be dead, alive: %v", state.GetAlivePlayers())
+	}
+
+	if count := countEvents(game, EventPlayerKilled, victim); count != 0 {
+		t.Fatalf("Player who quit was killed %d times", count)
+	}
+
+	checkAliveCount(t, game)
+}
+
+func TestPhaseEndsWhenTheLastPlayerItWaitsForLeaves(t *testing.T) {
+	config := NewConfig(6)
+	config.Roles = map[Role]int32{Mafia: 1, Detective: 1}
+	config.ReconnectGrace = 0
+	game, tokens := newStartedGame(t, config)
+
+	mafia := playersWith(game, Mafia)[0]
+	detective := playersWith(game, Detective)[0]
+	civilians := playersWith(game, Civilian)
+
+	// Detective doesn't reconnect and is removed, the night waited only for them
+	notifications, _, err := game.GetNotifications(detective, tokens[detective])
+	mustSucceed(t, err)
+	mustSucceed(t, game.KillPlayer(mafia, civilians[0]))
+	game.Disconnect(detective, notifications)
+
+	state := game.GetGameState()
+	if !state.GetIsDay() || state.GetDate() != 1 {
+		t.Fatalf("Night must be over, state: %s", state)
+	}
+
+	// The day waits for the last civilian, who quits
+	for _, voter := range []string{mafia, civilians[1], civilians[2]} {
+		mustSucceed(t, game.AddVote(voter, civilians[3]))
+	}
+	mustSucceed(t, game.DeletePlayer(civilians[3]))
+
+	state = game.GetGameState()
+	if state.GetIsDay() || state.GetDate() != 2 {
+		t.Fatalf("Day must be over, state: %s", state)
+	}
+
+	checkAliveCount(t, game)
+}
+
+/*
+	With the default rules the doctor who healed the only other survivor
+	has nobody to heal, the night must not wait for them
+*/
+func TestNightEndsWhenDoctorHasNobodyToHeal(t *testing.T) {
+	config := NewConfig(4)
+	config.Roles = map[Role]int32{Mafia: 1, Doctor: 1}
+	game, _ := newStartedGame(t, config)
+
+	mafia := playersWith(game, Mafia)[0]
+	doctor := playersWith(game, Doctor)[0]
+	civilians := playersWith(game, Civilian)
+
+	mustSucceed(t, game.HealPlayer(doctor, mafia))
+	mustSucceed(t, game.KillPlayer(mafia, civilians[0]))
+
+	for _, voter := range []string{mafia, doctor, civilians[1]} {
+		mustSucceed(t, game.AddVote(voter, civilians[1]))
+	}
+
+	state := game.GetGameState()
+	if state.GetIsDay() || state.GetDate() != 2 || len(state.GetAlivePlayers()) != 2 {
+		t.Fatalf("Second night must start with the mafia and the doctor, state: %s", state)
+	}
+
+	mustFail(t, game.HealPlayer(doctor, doctor), ErrRuleViolation)
+	mustFail(t, game.HealPlayer(doctor, mafia), ErrRuleViolation)
+	mustSucceed(t, game.KillPlayer(mafia, doctor))
+
+	result, finished := game.Result()
+	if !finished || !result.MafiaWon || result.Date != 2 {
+		t.Fatalf("Mafia must win on the second night, state: %s", game.GetGameState())
+	}
+}
+
+/*
+	Plays for the player until the game ends: acts on every new phase with random
+	targets, sometimes changes the vote and sometimes quits
+*/
+func playRandomly(game *Game, name string, token string, seed int64) {
+	rng := rand.New(rand.NewSource(seed))
+	notifications, _, err := game.GetNotifications(name, token)
+	if err != nil {
+		return
+	}
+
+	role := ""
+	for notification := range notifications {
+		state := notification.GetGameState()
+		alive := state.GetAlivePlayers()
+		if len(alive) == 0 {
+			continue
+		}
+		target := alive[rng.Intn(len(alive))]
+
+		switch notification.GetType() {
+		case mafia_grpc.NotificationType_FINISH:
+			return
+		case mafia_grpc.NotificationType_START, mafia_grpc.NotificationType_NEW_NIGHT:
+			if notification.GetType() == mafia_grpc.NotificationType_START {
+				role = notification.GetRole()
+			}
+
+			switch Role(role) {
+			case Mafia:
+				game.KillPlayer(name, target)
+			case Detective:
+				game.CheckIfMafia(name, target)
+			case Doctor:
+				game.HealPlayer(name, target)
+			}
+		case mafia_grpc.NotificationType_NEW_DAY, mafia_grpc.NotificationType_RUNOFF:
+			if rng.Intn(20) == 0 {
+				game.DeletePlayer(name)
+				return
+			}
+
+			if state.GetIsRunoff() {
+				candidates := state.GetRunoffCandidates()
+				target = candidates[rng.Intn(len(candidates))]
+			}
+
+			game.AddVote(name, target)
+			game.GetVotes(name)
+			game.ChangeVote(name, alive[rng.Intn(len(alive))])
+		}
+	}
+}
+
+/*
+	Many games are played at once, players join, act, vote and quit concurrently,
+	while the state is read from other goroutines. Run it with -race
+*/
+func TestConcurrentGames(t *testing.T) {
+	const games = 10
+	const players = 8
+
+	config := NewConfig(players)
+	config.Roles = map[Role]int32{Mafia: 2, Detective: 1, Doctor: 1}
+	config.KillRule = mafia_grpc.KillRule_MAJORITY
+	config.Runoff = true
+	config.OpenBallot = true
+	config.DayDuration = 50 * time.Millisecond
+	config.NightDuration = 50 * time.Millisecond
+
+	var wg sync.WaitGroup
+	all := []*Game{}
+	for i := 0; i < games; i++ {
+		game := NewGame(fmt.Sprintf("session%d", i), config)
+		all = append(all, game)
+
+		for j := 0; j < players; j++ {
+			wg.Add(1)
+			go func(name string, seed int64) {
+				defer wg.Done()
+
+				token, err := game.AddPlayer(name)
+				if err != nil {
+					t.Errorf("AddPlayer(%s): %v", name, err)
+					return
+				}
+
+				playRandomly(game, name, token, seed)
+			}(fmt.Sprintf("p%d", j), int64(i*players+j))
+		}
+	}
+
+	stop := make(chan struct{})
+	var readers sync.WaitGroup
+	readers.Add(1)
+	go func() {
+		defer readers.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+
+			for _, game := range all {
+				game.GetGameState()
+				game.Info()
+				game.Snapshot()
+			}
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("Games didn't finish in time")
+	}
+
+	close(stop)
+	readers.Wait()
+
+	for _, game := range all {
+		if !game.IsFinished() {
+			t.Errorf("Game %s didn't finish: %s", game.Session(), game.GetGameState())
+		}
+		checkAliveCount(t, game)
+	}
+}
+
+/*
+	Players vote at the same instant, every vote must be counted
+*/
+func TestConcurrentVotesAreCounted(t *testing.T) {
+	config := NewConfig(8)
+	config.Roles = map[Role]int32{Mafia: 2, Detective: 1}
+	game, _ := newStartedGame(t, config)
+
+	civilians := playersWith(game, Civilian)
+	for _, mafia := range playersWith(game, Mafia) {
+		mustSucceed(t, game.KillPlayer(mafia, civilians[0]))
+	}
+	_, err := game.CheckIfMafia(playersWith(game, Detective)[0], civilians[1])
+	mustSucceed(t, err)
+
+	// Everyone but the last civilian votes, so the day doesn't end
+	alive := game.GetAlivePlayers()
+	sort.Strings(alive)
+	holdout := civilians[len(civilians)-1]
+	voters := []string{}
+	for _, name := range alive {
+		if name != holdout {
+			voters = append(voters, name)
+		}
+	}
+
+	vote := func(change bool, victim string) {
+		start := make(chan struct{})
+		var wg sync.WaitGroup
+		for _, voter := range voters {
+			wg.Add(1)
+			go func(voter string) {
+				defer wg.Done()
+				<-start
+
+				var err error
+				if change {
+					err = game.ChangeVote(voter, victim)
+				} else {
+					err = game.AddVote(voter, victim)
+				}
+				if err != nil {
+					t.Errorf("Vote of %s: %v", voter, err)
+				}
+			}(voter)
+		}
+
+		close(start)
+		wg.Wait()
+	}
+
+	vote(false, holdout)
+	tally, err := game.GetVotes(holdout)
+	mustSucceed(t, err)
+	if tally.GetVoted() != int32(len(voters)) || tally.GetCounts()[holdout] != int32(len(voters)) {
+		t.Fatalf("All %d votes must be for %s, tally: %s", len(voters), holdout, tally)
+	}
+
+	vote(true, civilians[1])
+	tally, err = game.GetVotes(holdout)
+	mustSucceed(t, err)
+	if tally.GetVoted() != int32(len(voters)) || tally.GetCounts()[civilians[1]] != int32(len(voters)) || tally.GetCounts()[holdout] != 0 {
+		t.Fatalf("All %d votes must be changed to %s, tally: %s", len(voters), civilians[1], tally)
+	}
+}
+
+/*
+	Plays the first night and lets three players vote, so with the open ballot
+	the queue of a player who doesn't read gets START, NEW_DAY and three VOTE_CAST
+*/
+func startSlowReaderGame(t *testing.T, policy OverflowPolicy) (*Game, string, <-chan *mafia_grpc.Notification) {
+	t.Helper()
+
+	config := NewConfig(6)
+	config.Roles = map[Role]int32{Mafia: 1, Detective: 1}
+	config.OpenBallot = true
+	config.NotificationLimit = 2
+	config.OverflowPolicy = policy
+	game, tokens := newStartedGame(t, config)
+
+	mafia := playersWith(game, Mafia)[0]
+	detective := playersWith(game, Detective)[0]
+	civilians := playersWith(game, Civilian)
+	slow := civilians[0]
+
+	notifications, _, err := game.GetNotifications(slow, tokens[slow])
+	mustSucceed(t, err)
+
+	mustSucceed(t, game.KillPlayer(mafia, civilians[1]))
+	_, err = game.CheckIfMafia(detective, slow)
+	mustSucceed(t, err)
+
+	for _, voter := range []string{mafia, detective, civilians[2]} {
+		mustSucceed(t, game.AddVote(voter, civilians[3]))
+	}
+
+	return game, slow, notifications
+}
+
+func TestSlowReaderLosesOldestNotifications(t *testing.T) {
+	game, slow, notifications := startSlowReaderGame(t, DropOldest)
+
+	if len(notifications) != 2 {
+		t.Fatalf("Queue must be full with 2 notifications, it has %d", len(notifications))
+	}
+
+	for _, voted := range []int32{2, 3} {
+		notification := <-notifications
+		if notification.GetType() != mafia_grpc.NotificationType_VOTE_CAST || notification.GetVoteTally().GetVoted() != voted {
+			t.Fatalf("Only the latest votes must be left in the queue, got %s", notification)
+		}
+	}
+
+	game.mu.Lock()
+	isConnected := game.names2players[slow].isConnected
+	game.mu.Unlock()
+
+	if !isConnected {
+		t.Fatal("Slow reader must stay connected")
+	}
+}
+
+func TestSlowReaderIsDisconnected(t *testing.T) {
+	game, slow, notifications := startSlowReaderGame(t, Disconnect)
+
+	received := []mafia_grpc.NotificationType{}
+	for notification := range notifications {
+		received = append(received, notification.GetType())
+	}
+
+	if len(received) != 2 || received[0] != mafia_grpc.NotificationType_START || received[1] != mafia_grpc.NotificationType_NEW_DAY {
+		t.Fatalf("Queue must be closed after START and NEW_DAY, got %v", received)
+	}
+
+	game.mu.Lock()
+	info := game.names2players[slow]
+	game.mu.Unlock()
+
+	if info.isConnected || !info.isAlive {
+		t.Fatal("Slow reader must be disconnected, but stay in the game")
+	}
+
+	// Reconnected player gets the current state instead of the lost notifications
+	_, snapshot, err := game.GetNotifications(slow, info.token)
+	mustSucceed(t, err)
+
+	if !snapshot.GetGameState().GetIsDay() || snapshot.GetVoteTally().GetVoted() != 3 {
+		t.Fatalf("Snapshot must show the day with 3 votes, got %s", snapshot)
+	}
+}
+
+/*
+	Slow reader without the grace period overflows on the last vote of the day.
+	Their removal ends the day, but only after everyone got the vote
+*/
+func TestSlowReaderIsRemovedAfterTheEvent(t *testing.T) {
+	config := NewConfig(6)
+	config.Roles = map[Role]int32{Mafia: 1, Detective: 1}
+	config.OpenBallot = true
+	config.NotificationLimit = 5
+	config.OverflowPolicy = Disconnect
+	config.ReconnectGrace = 0
+	game, tokens := newStartedGame(t, config)
+	defer game.Close()
+
+	mafia := playersWith(game, Mafia)[0]
+	detective := playersWith(game, Detective)[0]
+	civilians := playersWith(game, Civilian)
+	slow := civilians[0]
+	voters := []string{mafia, detective, civilians[2], civilians[3]}
+
+	readers := make(map[string]<-chan *mafia_grpc.Notification)
+	for _, name := range append(voters, slow) {
+		notifications, _, err := game.GetNotifications(name, tokens[name])
+		mustSucceed(t, err)
+		readers[name] = notifications
+	}
+
+	drain := func() {
+		for _, voter := range voters {
+			for len(readers[voter]) > 0 {
+				<-readers[voter]
+			}
+		}
+	}
+
+	mustSucceed(t, game.KillPlayer(mafia, civilians[1]))
+	_, err := game.CheckIfMafia(detective, slow)
+	mustSucceed(t, err)
+
+	// START, NEW_DAY and three VOTE_CAST fill the queue of the slow reader
+	for _, voter := range voters[:3] {
+		drain()
+		mustSucceed(t, game.AddVote(voter, civilians[2]))
+	}
+	drain()
+	mustSucceed(t, game.AddVote(voters[3], civilians[2]))
+
+	for _, voter := range voters[:2] {
+		cast := <-readers[voter]
+		if cast.GetType() != mafia_grpc.NotificationType_VOTE_CAST || cast.GetVoteTally().GetVoted() != 4 {
+			t.Fatalf("%s must get the last vote before the night, got %s", voter, cast)
+		}
+
+		night := <-readers[voter]
+		if night.GetType() != mafia_grpc.NotificationType_NEW_NIGHT {
+			t.Fatalf("%s must get NEW_NIGHT after the slow reader is removed, got %s", voter, night)
+		}
+	}
+
+	game.mu.Lock()
+	info := game.names2players[slow]
+	game.mu.Unlock()
+	if !info.hasQuit || info.isAlive {
+		t.Fatal("Slow reader without the grace period must be removed")
+	}
+
+	_, err = Verify(game.Events())
+	mustSucceed(t, err)
+}
+
+func TestTableIsDealtByTheConfig(t *testing.T) {
+	big := NewConfig(12)
+	big.Roles = map[Role]int32{Mafia: 3, Detective: 1, Doctor: 1}
+
+	tables := []struct {
+		config Config
+		roles  map[Role]int
+	}{
+		{DefaultConfig(), map[Role]int{Mafia: 1, Detective: 1, Civilian: 2}},
+		{NewConfig(8), map[Role]int{Mafia: 2, Detective: 1, Civilian: 5}},
+		{big, map[Role]int{Mafia: 3, Detective: 1, Doctor: 1, Civilian: 7}},
+	}
+
+	for _, table := range tables {
+		game, _ := newStartedGame(t, table.config)
+
+		for _, role := range knownRoles {
+			if count := len(playersWith(game, role)); count != table.roles[role] {
+				t.Errorf("Table of %d must have %d %s, got %d", table.config.Players, table.roles[role], role, count)
+			}
+		}
+
+		game.Close()
+	}
+}
+
+func TestGameStartsWhenTheTableIsFull(t *testing.T) {
+	game := NewGame("test", NewConfig(6))
+	defer game.Close()
+
+	for i := 1; i <= 5; i++ {
+		_, err := game.AddPlayer(fmt.Sprintf("p%d", i))
+		mustSucceed(t, err)
+	}
+
+	if game.GetGameState().GetIsStarted() {
+		t.Fatal("Game must wait for the sixth player")
+	}
+
+	_, err := game.AddPlayer("p6")
+	mustSucceed(t, err)
+
+	state := game.GetGameState()
+	if !state.GetIsStarted() || state.GetIsDay() || state.GetDate() != 1 {
+		t.Fatalf("Game must start with the first night, state: %s", state)
+	}
+
+	_, err = game.AddPlayer("p7")
+	mustFail(t, err, ErrSessionFull)
+}
+
+func TestInvalidTableIsRejected(t *testing.T) {
+	tables := map[string]func(*Config){
+		"too few players":     func(c *Config) { c.Players = MinPlayers - 1 },
+		"too many players":    func(c *Config) { c.Players = MaxPlayers + 1 },
+		"no mafia":            func(c *Config) { c.Roles = map[Role]int32{Detective: 1} },
+		"half are mafia":      func(c *Config) { c.Roles = map[Role]int32{Mafia: 4} },
+		"more roles":          func(c *Config) { c.Roles = map[Role]int32{Mafia: 3, Detective: 3, Doctor: 3} },
+		"unknown role":        func(c *Config) { c.Roles = map[Role]int32{Mafia: 1, "Sheriff": 1} },
+		"civilians too few":   func(c *Config) { c.Roles = map[Role]int32{Mafia: 1, Civilian: 2} },
+		"negative role count": func(c *Config) { c.Roles = map[Role]int32{Mafia: 1, Doctor: -1} },
+	}
+
+	for name, change := range tables {
+		config := NewConfig(8)
+		change(&config)
+		if err := config.Validate(); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("Config with %s must be rejected, got %v", name, err)
+		}
+	}
+
+	config := NewConfig(MaxPlayers)
+	config.Roles = map[Role]int32{Mafia: 4, Detective: 2, Doctor: 2, Civilian: 12}
+	mustSucceed(t, config.Validate())
+}
+
+/*
+	Game ends only when all mafia are dead or nobody else is alive
+*/
+func TestBigTableFinishesWithTheLastPlayerOfTheTeam(t *testing.T) {
+	civiliansWin, _ := newStartedGame(t, NewConfig(10))
+	defer civiliansWin.Close()
+
+	mafia := playersWith(civiliansWin, Mafia)
+	mustSucceed(t, civiliansWin.DeletePlayer(mafia[0]))
+	if civiliansWin.IsFinished() {
+		t.Fatal("Game must go on while one mafia is alive")
+	}
+
+	mustSucceed(t, civiliansWin.DeletePlayer(mafia[1]))
+	result, finished := civiliansWin.Result()
+	if !finished || result.MafiaWon {
+		t.Fatalf("Civilians must win when all mafia are dead, state: %s", civiliansWin.GetGameState())
+	}
+
+	mafiaWins, _ := newStartedGame(t, NewConfig(10))
+	defer mafiaWins.Close()
+
+	others := append(playersWith(mafiaWins, Civilian), playersWith(mafiaWins, Detective)...)
+	for i, player := range others {
+		if mafiaWins.IsFinished() {
+			t.Fatalf("Game must go on while %d players are alive besides mafia", len(others)-i)
+		}
+		mustSucceed(t, mafiaWins.DeletePlayer(player))
+	}
+
+	result, finished = mafiaWins.Result()
+	if !finished || !result.MafiaWon {
+		t.Fatalf("Mafia must win when only mafia is alive, state: %s", mafiaWins.GetGameState())
+	}
+}
+
+/*
+	Starts the first night of 12 players with 3 mafia, the detective has already checked,
+	so the night waits only for the mafia. Mafia are returned with the leader first
+*/
+func startMafiaNight(t *testing.T, rule mafia_grpc.KillRule) (*Game, []string, []string) {
+	t.Helper()
+
+	config := NewConfig(12)
+	config.Roles = map[Role]int32{Mafia: 3, Detective: 1}
+	config.KillRule = rule
+	game, _ := newStartedGame(t, config)
+
+	civilians := playersWith(game, Civilian)
+	_, err := game.CheckIfMafia(playersWith(game, Detective)[0], civilians[0])
+	mustSucceed(t, err)
+
+	game.mu.Lock()
+	mafia := []string{game.mafiaLeader}
+	game.mu.Unlock()
+	for _, name := range playersWith(game, Mafia) {
+		if name != mafia[0] {
+			mafia = append(mafia, name)
+		}
+	}
+
+	return game, mafia, civilians
+}
+
+/*
+	Returns players killed on the given date
+*/
+func killedOn(game *Game, date int32) []string {
+	killed := []string{}
+	for _, event := range game.Events() {
+		if event.Type == EventPlayerKilled && event.Date == date {
+			killed = append(killed, event.Target)
+		}
+	}
+
+	sort.Strings(killed)
+	return killed
+}
+
+func TestKillRules(t *testing.T) {
+	cases := []struct {
+		name   string
+		rule   mafia_grpc.KillRule
+		votes  []int
+		victim int
+	}{
+		{"leader breaks the tie", mafia_grpc.KillRule_LEADER, []int{1, 2, 3}, 1},
+		{"leader is outvoted", mafia_grpc.KillRule_LEADER, []int{1, 2, 2}, 2},
+		{"majority kills", mafia_grpc.KillRule_MAJORITY, []int{1, 2, 2}, 2},
+		{"no majority", mafia_grpc.KillRule_MAJORITY, []int{1, 2, 3}, -1},
+		{"unanimous kill", mafia_grpc.KillRule_UNANIMOUS, []int{2, 2, 2}, 2},
+	}
+
+	for _, c := range cases {
+		game, mafia, civilians := startMafiaNight(t, c.rule)
+
+		for i, target := range c.votes {
+			if game.GetGameState().GetIsDay() {
+				t.Fatalf("%s: night must wait for every mafia", c.name)
+			}
+			mustSucceed(t, game.KillPlayer(mafia[i], civilians[target]))
+		}
+
+		state := game.GetGameState()
+		if !state.GetIsDay() || state.GetDate() != 1 {
+			t.Fatalf("%s: night must end when every mafia has voted, state: %s", c.name, state)
+		}
+
+		expected := []string{}
+		if c.victim >= 0 {
+			expected = []string{civilians[c.victim]}
+		}
+		if killed := killedOn(game, 1); fmt.Sprint(killed) != fmt.Sprint(expected) {
+			t.Errorf("%s: %v must be killed, got %v", c.name, expected, killed)
+		}
+
+		game.Close()
+	}
+}
+
+func TestUnanimousNightWaitsForAgreement(t *testing.T) {
+	game, mafia, civilians := startMafiaNight(t, mafia_grpc.KillRule_UNANIMOUS)
+	defer game.Close()
+
+	mustSucceed(t, game.KillPlayer(mafia[0], civilians[1]))
+	mustSucceed(t, game.KillPlayer(mafia[1], civilians[1]))
+	mustSucceed(t, game.KillPlayer(mafia[2], civilians[2]))
+	if game.GetGameState().GetIsDay() {
+		t.Fatal("Night must go on while mafia disagree")
+	}
+
+	// Mafia may change the choice until everyone agrees
+	mustSucceed(t, game.KillPlayer(mafia[2], civilians[1]))
+	if !game.GetGameState().GetIsDay() {
+		t.Fatal("Night must end when mafia agree")
+	}
+	if killed := killedOn(game, 1); len(killed) != 1 || killed[0] != civilians[1] {
+		t.Fatalf("%s must be killed, got %v", civilians[1], killed)
+	}
+}
+
+func TestMafiaKnowTheirTeam(t *testing.T) {
+	game, mafia, civilians := startMafiaNight(t, mafia_grpc.KillRule_LEADER)
+	defer game.Close()
+
+	game.mu.Lock()
+	tokens := map[string]string{mafia[1]: game.names2players[mafia[1]].token, civilians[1]: game.names2players[civilians[1]].token}
+	game.mu.Unlock()
+
+	team := playersWith(game, Mafia)
+	notifications, _, err := game.GetNotifications(mafia[1], tokens[mafia[1]])
+	mustSucceed(t, err)
+	start := <-notifications
+	if start.GetType() != mafia_grpc.NotificationType_START || fmt.Sprint(start.GetMafiaTeam()) != fmt.Sprint(team) || start.GetMafiaLeader() != mafia[0] {
+		t.Fatalf("Mafia must learn the team %v and the leader %s, got %s", team, mafia[0], start)
+	}
+
+	notifications, _, err = game.GetNotifications(civilians[1], tokens[civilians[1]])
+	mustSucceed(t, err)
+	start = <-notifications
+	if len(start.GetMafiaTeam()) != 0 || start.GetMafiaLeader() != "" {
+		t.Fatalf("Civilian must not learn the mafia, got %s", start)
+	}
+
+	// Civilian can't take part in the night kill
+	mustFail(t, game.KillPlayer(civilians[1], civilians[2]), ErrNotAuthorized)
+}
+
+func TestDeadLeaderIsReplaced(t *testing.T) {
+	game, mafia, civilians := startMafiaNight(t, mafia_grpc.KillRule_LEADER)
+	defer game.Close()
+
+	mustSucceed(t, game.DeletePlayer(mafia[0]))
+	next := playersWith(game, Mafia)[0]
+
+	game.mu.Lock()
+	leader := game.mafiaLeader
+	game.mu.Unlock()
+	if leader != next {
+		t.Fatalf("Alive mafia with the smallest name must lead, got %s", leader)
+	}
+
+	other := playersWith(game, Mafia)[1]
+	mustSucceed(t, game.KillPlayer(next, civilians[1]))
+	mustSucceed(t, game.KillPlayer(other, civilians[2]))
+	if killed := killedOn(game, 1); len(killed) != 1 || killed[0] != civilians[1] {
+		t.Fatalf("New leader must break the tie, got %v", killed)
+	}
+}
+
+/*
+	Starts the first night of 6 players with the mafia, the detective and the doctor,
+	the detective has already checked. Returns the mafia, the doctor and the civilians
+*/
+func startDoctorNight(t *testing.T, selfHeal bool, repeatHeal bool) (*Game, string, string, []string) {
+	t.Helper()
+
+	config := NewConfig(6)
+	config.Roles = map[Role]int32{Mafia: 1, Detective: 1, Doctor: 1}
+	config.DoctorSelfHeal = selfHeal
+	config.DoctorRepeatHeal = repeatHeal
+	game, _ := newStartedGame(t, config)
+
+	civilians := playersWith(game, Civilian)
+	_, err := game.CheckIfMafia(playersWith(game, Detective)[0], civilians[0])
+	mustSucceed(t, err)
+
+	return game, playersWith(game, Mafia)[0], playersWith(game, Doctor)[0], civilians
+}
+
+/*
+	Every alive player votes for the victim
+*/
+func voteOut(t *testing.T, game *Game, victim string) {
+	t.Helper()
+
+	for _, voter := range game.GetAlivePlayers() {
+		mustSucceed(t, game.AddVote(voter, victim))
+	}
+}
+
+func TestHealedVictimSurvives(t *testing.T) {
+	game, mafia, doctor, civilians := startDoctorNight(t, false, false)
+	defer game.Close()
+
+	game.mu.Lock()
+	token := game.names2players[civilians[1]].token
+	game.mu.Unlock()
+	notifications, _, err := game.GetNotifications(civilians[1], token)
+	mustSucceed(t, err)
+
+	mustSucceed(t, game.KillPlayer(mafia, civilians[0]))
+	if game.GetGameState().GetIsDay() {
+		t.Fatal("Night must wait for the doctor")
+	}
+
+	mustSucceed(t, game.HealPlayer(doctor, civilians[0]))
+	if !game.GetGameState().GetIsDay() {
+		t.Fatal("Night must end when the doctor has healed")
+	}
+
+	if killed := killedOn(game, 1); len(killed) != 0 {
+		t.Fatalf("Healed victim must survive, killed: %v", killed)
+	}
+
+	<-notifications
+	newDay := <-notifications
+	if newDay.GetType() != mafia_grpc.NotificationType_NEW_DAY || newDay.GetKilledPlayer() != "" {
+		t.Fatalf("NEW_DAY must say nobody died, got %s", newDay)
+	}
+}
+
+func TestDoctorHealsOncePerNight(t *testing.T) {
+	game, mafia, doctor, civilians := startDoctorNight(t, false, false)
+	defer game.Close()
+
+	mustFail(t, game.HealPlayer(mafia, civilians[0]), ErrNotAuthorized)
+	mustSucceed(t, game.HealPlayer(doctor, civilians[0]))
+	mustFail(t, game.HealPlayer(doctor, civilians[1]), ErrNotYourTurn)
+
+	// The heal is taken, the kill of another player goes through
+	mustSucceed(t, game.KillPlayer(mafia, civilians[1]))
+	if killed := killedOn(game, 1); len(killed) != 1 || killed[0] != civilians[1] {
+		t.Fatalf("%s must be killed, got %v", civilians[1], killed)
+	}
+
+	mustFail(t, game.HealPlayer(doctor, civilians[0]), ErrWrongPhase)
+}
+
+func TestDoctorHealRules(t *testing.T) {
+	for _, allowed := range []bool{false, true} {
+		game, mafia, doctor, civilians := startDoctorNight(t, allowed, allowed)
+
+		err := game.HealPlayer(doctor, doctor)
+		if allowed {
+			mustSucceed(t, err)
+		} else {
+			mustFail(t, err, ErrRuleViolation)
+			mustSucceed(t, game.HealPlayer(doctor, civilians[1]))
+		}
+		mustSucceed(t, game.KillPlayer(mafia, civilians[0]))
+
+		voteOut(t, game, civilians[2])
+		if state := game.GetGameState(); state.GetIsDay() || state.GetDate() != 2 {
+			t.Fatalf("Second night must start, state: %s", state)
+		}
+
+		patient := civilians[1]
+		if allowed {
+			patient = doctor
+		}
+
+		err = game.HealPlayer(doctor, patient)
+		if allowed {
+			mustSucceed(t, err)
+		} else {
+			mustFail(t, err, ErrRuleViolation)
+		}
+
+		game.Close()
+	}
+}
+
+/*
+	Fires the timer of the current phase at once, as if the time is up
+*/
+func timeOut(game *Game) {
+	game.mu.Lock()
+	phase := game.phase
+	game.mu.Unlock()
+
+	game.onPhaseTimeout(phase)
+}
+
+func startTimedGame(t *testing.T) (*Game, string, string, []string) {
+	t.Helper()
+
+	config := NewConfig(6)
+	config.Roles = map[Role]int32{Mafia: 1, Detective: 1}
+	config.DayDuration = time.Hour
+	config.NightDuration = time.Hour
+	game, _ := newStartedGame(t, config)
+
+	return game, playersWith(game, Mafia)[0], playersWith(game, Detective)[0], playersWith(game, Civilian)
+}
+
+func checkDeadline(t *testing.T, state *mafia_grpc.GameState, duration time.Duration) {
+	t.Helper()
+
+	deadline := time.UnixMilli(state.GetPhaseDeadline())
+	if deadline.Before(time.Now()) || deadline.After(time.Now().Add(duration)) {
+		t.Fatalf("Phase must end in %s, deadline: %s", duration, deadline)
+	}
+}
+
+func TestNightTimeoutResolvesWithTheActionsMade(t *testing.T) {
+	game, mafia, _, civilians := startTimedGame(t)
+	defer game.Close()
+
+	game.mu.Lock()
+	token := game.names2players[civilians[1]].token
+	game.mu.Unlock()
+	notifications, snapshot, err := game.GetNotifications(civilians[1], token)
+	mustSucceed(t, err)
+	checkDeadline(t, snapshot.GetGameState(), time.Hour)
+
+	// Detective doesn't check anybody
+	mustSucceed(t, game.KillPlayer(mafia, civilians[0]))
+	timeOut(game)
+
+	state := game.GetGameState()
+	if !state.GetIsDay() || state.GetDate() != 1 {
+		t.Fatalf("Day must start when the night times out, state: %s", state)
+	}
+	if killed := killedOn(game, 1); len(killed) != 1 || killed[0] != civilians[0] {
+		t.Fatalf("Mafia victim must be killed, got %v", killed)
+	}
+	if countEvents(game, EventPhaseTimeout, "") != 1 {
+		t.Fatal("Timeout must be in the log")
+	}
+
+	<-notifications
+	newDay := <-notifications
+	if newDay.GetType() != mafia_grpc.NotificationType_NEW_DAY {
+		t.Fatalf("NEW_DAY must follow START, got %s", newDay)
+	}
+	checkDeadline(t, newDay.GetGameState(), time.Hour)
+}
+
+func TestDayTimeoutResolvesWithTheVotesCast(t *testing.T) {
+	game, mafia, detective, civilians := startTimedGame(t)
+	defer game.Close()
+
+	mustSucceed(t, game.KillPlayer(mafia, civilians[0]))
+	_, err := game.CheckIfMafia(detective, civilians[1])
+	mustSucceed(t, err)
+
+	mustSucceed(t, game.AddVote(mafia, civilians[1]))
+	mustSucceed(t, game.AddVote(civilians[2], civilians[1]))
+	mustSucceed(t, game.AddVote(detective, civilians[2]))
+	timeOut(game)
+
+	state := game.GetGameState()
+	if state.GetIsDay() || state.GetDate() != 2 {
+		t.Fatalf("Night must start when the day times out, state: %s", state)
+	}
+	if killed := killedOn(game, 1); fmt.Sprint(killed) != fmt.Sprint([]string{civilians[0], civilians[1]}) {
+		t.Fatalf("Night victim and %s must be killed, got %v", civilians[1], killed)
+	}
+}
+
+func TestTimerOfTheEndedPhaseIsIgnored(t *testing.T) {
+	game, mafia, detective, civilians := startTimedGame(t)
+	defer game.Close()
+
+	game.mu.Lock()
+	night := game.phase
+	game.mu.Unlock()
+
+	mustSucceed(t, game.KillPlayer(mafia, civilians[0]))
+	_, err := game.CheckIfMafia(detective, civilians[1])
+	mustSucceed(t, err)
+
+	game.onPhaseTimeout(night)
+	state := game.GetGameState()
+	if !state.GetIsDay() || state.GetDate() != 1 {
+		t.Fatalf("Timer of the first night must not end the day, state: %s", state)
+	}
+	if countEvents(game, EventPhaseTimeout, "") != 0 {
+		t.Fatal("Stale timeout must not get to the log")
+	}
+}
+
+func TestPhaseEndsWhenTheTimeIsUp(t *testing.T) {
+	config := NewConfig(4)
+	config.NightDuration = 10 * time.Millisecond
+	game, _ := newStartedGame(t, config)
+	defer game.Close()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !game.GetGameState().GetIsDay() {
+		if time.Now().After(deadline) {
+			t.Fatal("Night must end when its time is up")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// The day has no limit
+	state := game.GetGameState()
+	if state.GetPhaseDeadline() != 0 {
+		t.Fatalf("Day without duration must have no deadline, state: %s", state)
+	}
+
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	if game.phaseTimer != nil {
+		t.Fatal("Day without duration must not arm the timer")
+	}
+}
+
+/*
+	Plays the first night of 6 players and splits the day vote between
+	the second and the third civilian, one player skips. Returns the civilians
+*/
+func startTiedDay(t *testing.T, runoff bool, fallback mafia_grpc.RunoffFallback) (*Game, []string) {
+	t.Helper()
+
+	config := NewConfig(6)
+	config.Roles = map[Role]int32{Mafia: 1, Detective: 1}
+	config.Runoff = runoff
+	config.RunoffFallback = fallback
+	game, _ := newStartedGame(t, config)
+
+	mafia := playersWith(game, Mafia)[0]
+	detective := playersWith(game, Detective)[0]
+	civilians := playersWith(game, Civilian)
+	mustSucceed(t, game.KillPlayer(mafia, civilians[0]))
+	_, err := game.CheckIfMafia(detective, civilians[1])
+	mustSucceed(t, err)
+
+	tieVotes(t, game, civilians)
+	return game, civilians
+}
+
+/*
+	Two votes for each of the candidates, the third civilian skips
+*/
+func tieVotes(t *testing.T, game *Game, civilians []string) {
+	t.Helper()
+
+	mafia := playersWith(game, Mafia)[0]
+	detective := playersWith(game, Detective)[0]
+	mustSucceed(t, game.AddVote(mafia, civilians[1]))
+	mustSucceed(t, game.AddVote(detective, civilians[1]))
+	mustSucceed(t, game.AddVote(civilians[1], civilians[2]))
+	mustSucceed(t, game.AddVote(civilians[2], civilians[2]))
+	mustSucceed(t, game.SkipVote(civilians[3]))
+}
+
+func TestTieWithoutRunoffKillsNobody(t *testing.T) {
+	game, civilians := startTiedDay(t, false, mafia_grpc.RunoffFallback_ALL_TIED)
+	defer game.Close()
+
+	state := game.GetGameState()
+	if state.GetIsDay() || state.GetDate() != 2 {
+		t.Fatalf("Night must start after the tie, state: %s", state)
+	}
+	if killed := killedOn(game, 1); len(killed) != 1 || killed[0] != civilians[0] {
+		t.Fatalf("Only the night victim must be killed, got %v", killed)
+	}
+}
+
+func TestRunoffDecidesTheTie(t *testing.T) {
+	game, civilians := startTiedDay(t, true, mafia_grpc.RunoffFallback_NO_KILL)
+	defer game.Close()
+
+	state := game.GetGameState()
+	candidates := []string{civilians[1], civilians[2]}
+	if !state.GetIsDay() || !state.GetIsRunoff() || fmt.Sprint(state.GetRunoffCandidates()) != fmt.Sprint(candidates) {
+		t.Fatalf("Runoff between %v must start, state: %s", candidates, state)
+	}
+	if countEvents(game, EventRunoff, "") != 1 {
+		t.Fatal("Runoff must be in the log")
+	}
+
+	tally, err := game.GetVotes(civilians[1])
+	mustSucceed(t, err)
+	if tally.GetVoted() != 0 {
+		t.Fatalf("Runoff must count the votes anew, tally: %s", tally)
+	}
+
+	mafia := playersWith(game, Mafia)[0]
+	mustFail(t, game.AddVote(mafia, civilians[3]), ErrRuleViolation)
+
+	for _, voter := range game.GetAlivePlayers() {
+		if voter != civilians[1] {
+			mustSucceed(t, game.AddVote(voter, civilians[1]))
+		} else {
+			mustSucceed(t, game.AddVote(voter, civilians[2]))
+		}
+	}
+
+	state = game.GetGameState()
+	if state.GetIsDay() || state.GetIsRunoff() || state.GetDate() != 2 {
+		t.Fatalf("Night must start after the runoff, state: %s", state)
+	}
+	if killed := killedOn(game, 1); fmt.Sprint(killed) != fmt.Sprint([]string{civilians[0], civilians[1]}) {
+		t.Fatalf("Runoff must kill %s, got %v", civilians[1], killed)
+	}
+}
+
+func TestRunoffFallbacks(t *testing.T) {
+	for _, fallback := range []mafia_grpc.RunoffFallback{mafia_grpc.RunoffFallback_NO_KILL, mafia_grpc.RunoffFallback_RANDOM, mafia_grpc.RunoffFallback_ALL_TIED} {
+		game, civilians := startTiedDay(t, true, fallback)
+
+		// The runoff is tied the same way
+		tieVotes(t, game, civilians)
+
+		state := game.GetGameState()
+		if state.GetIsDay() || state.GetDate() != 2 {
+			t.Fatalf("%s: night must start after the tied runoff, state: %s", fallback, state)
+		}
+
+		killed := killedOn(game, 1)
+		switch fallback {
+		case mafia_grpc.RunoffFallback_NO_KILL:
+			if len(killed) != 1 {
+				t.Errorf("%s: nobody must be killed by the vote, got %v", fallback, killed)
+			}
+		case mafia_grpc.RunoffFallback_RANDOM:
+			random := countEvents(game, EventRandomVictim, civilians[1]) + countEvents(game, EventRandomVictim, civilians[2])
+			if len(killed) != 2 || random != 1 {
+				t.Errorf("%s: one of the tied must be killed and logged, got %v", fallback, killed)
+			}
+
+			_, err := Verify(game.Events())
+			if err != nil {
+				t.Errorf("%s: replay must repeat the random victim: %v", fallback, err)
+			}
+		case mafia_grpc.RunoffFallback_ALL_TIED:
+			if fmt.Sprint(killed) != fmt.Sprint(civilians[:3]) {
+				t.Errorf("%s: both tied must be killed, got %v", fallback, killed)
+			}
+		}
+
+		game.Close()
+	}
+}
+
+/*
+	Plays the first night of 6 players, the first civilian is killed
+*/
+func startDay(t *testing.T, config Config) (*Game, map[string]string, string, string, []string) {
+	t.Helper()
+
+	config.Roles = map[Role]int32{Mafia: 1, Detective: 1}
+	game, tokens := newStartedGame(t, config)
+
+	mafia := playersWith(game, Mafia)[0]
+	detective := playersWith(game, Detective)[0]
+	civilians := playersWith(game, Civilian)
+	mustFail(t, game.AddVote(mafia, civilians[0]), ErrWrongPhase)
+	mustSucceed(t, game.KillPlayer(mafia, civilians[0]))
+	_, err := game.CheckIfMafia(detective, civilians[1])
+	mustSucceed(t, err)
+
+	return game, tokens, mafia, detective, civilians
+}
+
+func TestVotesCanBeChangedWithdrawnAndSkipped(t *testing.T) {
+	game, _, mafia, detective, civilians := startDay(t, NewConfig(6))
+	defer game.Close()
+
+	mustFail(t, game.ChangeVote(mafia, civilians[1]), ErrNotYourTurn)
+	mustFail(t, game.WithdrawVote(mafia), ErrNotYourTurn)
+	mustFail(t, game.AddVote(civilians[0], mafia), ErrPlayerDead)
+	mustFail(t, game.AddVote(mafia, civilians[0]), ErrTargetDead)
+
+	mustSucceed(t, game.AddVote(mafia, civilians[1]))
+	mustFail(t, game.AddVote(mafia, civilians[2]), ErrNotYourTurn)
+	mustFail(t, game.SkipVote(mafia), ErrNotYourTurn)
+	mustSucceed(t, game.AddVote(detective, civilians[1]))
+	mustSucceed(t, game.ChangeVote(mafia, civilians[2]))
+	mustSucceed(t, game.SkipVote(civilians[1]))
+
+	tally, err := game.GetVotes(civilians[3])
+	mustSucceed(t, err)
+	if tally.GetVoted() != 3 || tally.GetSkips() != 1 || tally.GetCounts()[civilians[1]] != 1 || tally.GetCounts()[civilians[2]] != 1 {
+		t.Fatalf("Tally must have one vote for each and one skip, got %s", tally)
+	}
+
+	mustSucceed(t, game.WithdrawVote(detective))
+	tally, err = game.GetVotes(civilians[3])
+	mustSucceed(t, err)
+	if tally.GetVoted() != 2 || tally.GetCounts()[civilians[1]] != 0 {
+		t.Fatalf("Withdrawn vote must not be counted, tally: %s", tally)
+	}
+
+	// Withdrawn voter may vote again
+	mustSucceed(t, game.AddVote(detective, civilians[2]))
+	mustSucceed(t, game.AddVote(civilians[2], civilians[3]))
+	mustSucceed(t, game.AddVote(civilians[3], civilians[2]))
+	if killed := killedOn(game, 1); fmt.Sprint(killed) != fmt.Sprint([]string{civilians[0], civilians[2]}) {
+		t.Fatalf("%s must be voted out, got %v", civilians[2], killed)
+	}
+}
+
+func TestSkipsKillNobody(t *testing.T) {
+	config := NewConfig(6)
+	config.Runoff = true
+	game, _, mafia, detective, civilians := startDay(t, config)
+	defer game.Close()
+
+	mustSucceed(t, game.AddVote(mafia, civilians[1]))
+	mustSucceed(t, game.AddVote(detective, civilians[1]))
+	for _, voter := range civilians[1:] {
+		mustSucceed(t, game.SkipVote(voter))
+	}
+
+	state := game.GetGameState()
+	if state.GetIsDay() || state.GetDate() != 2 {
+		t.Fatalf("Night must start without the runoff, state: %s", state)
+	}
+	if killed := killedOn(game, 1); len(killed) != 1 {
+		t.Fatalf("Most players skipped, nobody must be voted out, got %v", killed)
+	}
+}
+
+func TestBallotShowsVotersOnlyWhenOpen(t *testing.T) {
+	for _, open := range []bool{false, true} {
+		config := NewConfig(6)
+		config.OpenBallot = open
+		game, tokens, mafia, detective, civilians := startDay(t, config)
+
+		notifications, _, err := game.GetNotifications(civilians[3], tokens[civilians[3]])
+		mustSucceed(t, err)
+		for len(notifications) > 0 {
+			<-notifications
+		}
+
+		mustSucceed(t, game.AddVote(mafia, civilians[1]))
+		mustSucceed(t, game.SkipVote(detective))
+
+		tally, err := game.GetVotes(civilians[3])
+		mustSucceed(t, err)
+		votes := []string{}
+		for _, vote := range tally.GetVotes() {
+			votes = append(votes, vote.GetVoter()+"->"+vote.GetVictim())
+		}
+		expected := []string{}
+		if open {
+			expected = []string{detective + "->", mafia + "->" + civilians[1]}
+			sort.Strings(expected)
+		}
+		if fmt.Sprint(votes) != fmt.Sprint(expected) {
+			t.Errorf("Open ballot %v: votes must be %v, got %v", open, expected, votes)
+		}
+
+		casts := len(notifications)
+		if open && casts != 2 || !open && casts != 0 {
+			t.Errorf("Open ballot %v: got %d VOTE_CAST notifications", open, casts)
+		}
+		for len(notifications) > 0 {
+			cast := <-notifications
+			if cast.GetType() != mafia_grpc.NotificationType_VOTE_CAST || len(cast.GetVoteTally().GetVotes()) == 0 {
+				t.Errorf("VOTE_CAST must show who voted, got %s", cast)
+			}
+		}
+
+		game.Close()
+	}
+}
+
+func TestOnlyGameFinishedByTheRulesHasResult(t *testing.T) {
+	closed, _ := newStartedGame(t, NewConfig(4))
+	closed.Close()
+	if !closed.IsFinished() {
+		t.Fatal("Closed game must count as finished")
+	}
+
+	stopped, _ := newStartedGame(t, NewConfig(4))
+	defer stopped.Close()
+	mustSucceed(t, stopped.ForceFinish("test"))
+
+	for _, game := range []*Game{closed, stopped} {
+		if result, ok := game.Result(); ok {
+			t.Errorf("Game without a winner must have no result, got %+v", result)
+		}
+	}
+
+	finished, _ := newStartedGame(t, NewConfig(4))
+	defer finished.Close()
+	mustSucceed(t, finished.DeletePlayer(playersWith(finished, Mafia)[0]))
+	if result, ok := finished.Result(); !ok || result.MafiaWon {
+		t.Fatalf("Civilians must win when the mafia leaves, got %+v", result)
+	}
+}
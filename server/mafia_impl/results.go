@@ -0,0 +1,112 @@
+package mafia_impl
+
+import (
+	"sort"
+	"time"
+)
+
+/*
+	Outcome of the game for one player. Player wins with their team,
+	players who quit count too. Checks and MafiaFound are the checks
+	of the detective and how many of them found mafia
+*/
+type PlayerResult struct {
+	Name       string
+	Role       Role
+	IsBot      bool
+	Won        bool
+	Survived   bool
+	Checks     int32
+	MafiaFound int32
+}
+
+/*
+	Outcome of the finished game, players are sorted by name
+*/
+type GameResult struct {
+	GameID   string
+	Session  string
+	MafiaWon bool
+	Date     int32
+	Finished time.Time
+	Players  []PlayerResult
+}
+
+/*
+	Handler is called once when the game finishes, while the game is locked,
+	so it must not call the game back. Replayed games don't call it
+*/
+func (g *Game) OnFinish(handler func(GameResult)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.onFinish = handler
+}
+
+/*
+	Returns the outcome of the game, false if it hasn't finished by the rules:
+	it's in progress, was closed before the end or stopped by the administrator
+*/
+func (g *Game) Result() (GameResult, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	hasWinner := false
+	for _, event := range g.events {
+		switch event.Type {
+		case EventForceFinish:
+			return GameResult{}, false
+		case EventFinished:
+			hasWinner = true
+		}
+	}
+
+	if !hasWinner {
+		return GameResult{}, false
+	}
+
+	return g.getResult(), true
+}
+
+func (g *Game) getResult() GameResult {
+	mafiaWon := g.countAliveMafia() > 0
+
+	checks := make(map[string]int32)
+	mafiaFound := make(map[string]int32)
+	for _, event := range g.events {
+		if event.Type != EventCheckIfMafia {
+			continue
+		}
+
+		checks[event.Player]++
+		if g.names2players[event.Target].role == Mafia {
+			mafiaFound[event.Player]++
+		}
+	}
+
+	players := []PlayerResult{}
+	for name, info := range g.names2players {
+		players = append(players, PlayerResult{
+			Name:       name,
+			Role:       info.role,
+			IsBot:      info.isBot,
+			Won:        (info.role == Mafia) == mafiaWon,
+			Survived:   info.isAlive,
+			Checks:     checks[name],
+			MafiaFound: mafiaFound[name],
+		})
+	}
+
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].Name < players[j].Name
+	})
+
+	return GameResult{
+		GameID:   g.id,
+		Session:  g.session,
+		MafiaWon: mafiaWon,
+		Date:     g.date,
+		Finished: g.finishedAt,
+		Players:  players,
+	}
+}
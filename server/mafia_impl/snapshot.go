@@ -0,0 +1,174 @@
+package mafia_impl
+
+import (
+	"log"
+	"soa_mafia/pkg/mafia_grpc"
+	"sort"
+	"time"
+)
+
+type PlayerSnapshot struct {
+	Name       string
+	IsBot      bool `json:",omitempty"`
+	Role       Role
+	IsAlive    bool
+	HasChecked bool
+	Healed     string
+	LastHealed string
+	Token      string
+	HasQuit    bool
+}
+
+/*
+	Snapshot holds everything needed to continue the game after a restart.
+	Connections and queued notifications aren't saved: players have to reconnect
+	and get the current state with the STATE notification
+*/
+type Snapshot struct {
+	ID         string
+	Session    string
+	Config     Config
+	Seed       int64
+	Players    []PlayerSnapshot
+	IsStarted  bool
+	IsFinished bool
+
+	Date             int32
+	IsDay            bool
+	IsRunoff         bool
+	RunoffCandidates []string
+	DailyVotes       map[string]string
+	MafiaVotes       map[string]string
+	MafiaLeader      string
+	PhaseDeadline    time.Time
+
+	PreviousTokens map[string]string
+	LastActivity   time.Time
+	FinishedAt     time.Time
+
+	Events []Event
+}
+
+func (g *Game) Snapshot() Snapshot {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	players := []PlayerSnapshot{}
+	for name, info := range g.names2players {
+		players = append(players, PlayerSnapshot{
+			Name:       name,
+			IsBot:      info.isBot,
+			Role:       info.role,
+			IsAlive:    info.isAlive,
+			HasChecked: info.hasChecked,
+			Healed:     info.healed,
+			LastHealed: info.lastHealed,
+			Token:      info.token,
+			HasQuit:    info.hasQuit,
+		})
+	}
+
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].Name < players[j].Name
+	})
+
+	events := make([]Event, len(g.events))
+	copy(events, g.events)
+
+	return Snapshot{
+		ID:               g.id,
+		Session:          g.session,
+		Config:           g.config,
+		Seed:             g.seed,
+		Players:          players,
+		IsStarted:        g.isStarted,
+		IsFinished:       g.isFinished,
+		Date:             g.date,
+		IsDay:            g.isDay,
+		IsRunoff:         g.isRunoff,
+		RunoffCandidates: g.runoffCandidates,
+		DailyVotes:       copyVotes(g.dailyVotes),
+		MafiaVotes:       copyVotes(g.mafiaVotes),
+		MafiaLeader:      g.mafiaLeader,
+		PhaseDeadline:    g.phaseDeadline,
+		PreviousTokens:   g.previousTokens,
+		LastActivity:     g.lastActivity,
+		FinishedAt:       g.finishedAt,
+		Events:           events,
+	}
+}
+
+/*
+	Creates game from the snapshot. All players are disconnected
+	and have the reconnect grace period to come back, the phase timer
+	continues with the time that was left. Random generator starts again from the seed
+*/
+func RestoreGame(snapshot Snapshot) *Game {
+	game := &Game{}
+	game.init(snapshot.Session, snapshot.Config, savedSeed(snapshot.Seed, snapshot.Config))
+
+	game.id = snapshot.ID
+	game.isStarted = snapshot.IsStarted
+	game.isFinished = snapshot.IsFinished
+	game.date = snapshot.Date
+	game.isDay = snapshot.IsDay
+	game.isRunoff = snapshot.IsRunoff
+	game.runoffCandidates = snapshot.RunoffCandidates
+	game.dailyVotes = copyVotes(snapshot.DailyVotes)
+	game.mafiaVotes = copyVotes(snapshot.MafiaVotes)
+	game.mafiaLeader = snapshot.MafiaLeader
+	game.previousTokens = snapshot.PreviousTokens
+	game.lastActivity = snapshot.LastActivity
+	game.finishedAt = snapshot.FinishedAt
+	game.events = snapshot.Events
+
+	for _, player := range snapshot.Players {
+		info := playerInfo{
+			isBot:      player.IsBot,
+			role:       player.Role,
+			isAlive:    player.IsAlive,
+			hasChecked: player.HasChecked,
+			healed:     player.Healed,
+			lastHealed: player.LastHealed,
+			token:      player.Token,
+			hasQuit:    player.HasQuit,
+		}
+
+		if !info.hasQuit {
+			info.notifications = make(chan *mafia_grpc.Notification, game.config.NotificationLimit)
+		}
+
+		if info.isAlive {
+			game.alivePlayers++
+		}
+
+		game.names2players[player.Name] = info
+	}
+
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	for name, info := range game.names2players {
+		if !info.hasQuit {
+			game.disconnect(name)
+		}
+	}
+
+	if game.isStarted && !game.isFinished && !snapshot.PhaseDeadline.IsZero() {
+		game.phase++
+		game.phaseDeadline = snapshot.PhaseDeadline
+		game.armPhaseTimer()
+	}
+
+	log.Printf("Session %s: Game %s restored", game.session, game.id)
+	return game
+}
+
+func copyVotes(votes map[string]string) map[string]string {
+	result := make(map[string]string)
+	for voter, victim := range votes {
+		result[voter] = victim
+	}
+
+	return result
+}
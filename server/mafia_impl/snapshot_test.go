@@ -0,0 +1,123 @@
+package mafia_impl
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"soa_mafia/pkg/mafia_grpc"
+)
+
+/*
+	Snapshot goes through JSON as it does in the storage
+*/
+func saveAndLoad(t *testing.T, snapshot Snapshot) Snapshot {
+	t.Helper()
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var loaded Snapshot
+	err = json.Unmarshal(data, &loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return loaded
+}
+
+func TestRestoredGameContinues(t *testing.T) {
+	config := NewConfig(6)
+	config.Roles = map[Role]int32{Mafia: 2, Detective: 1}
+	config.KillRule = mafia_grpc.KillRule_UNANIMOUS
+	config.DayDuration = time.Hour
+	config.NightDuration = time.Hour
+	game, tokens := newStartedGame(t, config)
+	defer game.Close()
+
+	mafia := playersWith(game, Mafia)
+	detective := playersWith(game, Detective)[0]
+	victim := playersWith(game, Civilian)[0]
+	mustSucceed(t, game.KillPlayer(mafia[0], victim))
+
+	snapshot := game.Snapshot()
+	restored := RestoreGame(saveAndLoad(t, snapshot))
+	defer restored.Close()
+
+	before, err := json.Marshal(snapshot)
+	mustSucceed(t, err)
+	after, err := json.Marshal(restored.Snapshot())
+	mustSucceed(t, err)
+	if !bytes.Equal(before, after) {
+		t.Fatalf("Restored game differs from the snapshot:\n%s\n%s", before, after)
+	}
+
+	for _, player := range []string{mafia[1], detective} {
+		_, _, err = restored.GetNotifications(player, tokens[player])
+		if err != nil {
+			t.Fatalf("%s must reconnect with the token issued before the restart: %v", player, err)
+		}
+	}
+
+	mustSucceed(t, restored.KillPlayer(mafia[1], victim))
+	_, err = restored.CheckIfMafia(detective, mafia[0])
+	mustSucceed(t, err)
+
+	state := restored.GetGameState()
+	if !state.GetIsDay() || state.GetDate() != 1 || len(state.GetAlivePlayers()) != 5 {
+		t.Fatalf("Night must end with the victim chosen before the restart, state: %s", state)
+	}
+	if countEvents(restored, EventPlayerKilled, victim) != 1 {
+		t.Fatalf("%s must be killed once", victim)
+	}
+
+	checkAliveCount(t, restored)
+}
+
+func TestRestoredPhaseTimerKeepsDeadline(t *testing.T) {
+	config := NewConfig(4)
+	config.DayDuration = time.Hour
+	config.NightDuration = time.Hour
+	game, _ := newStartedGame(t, config)
+	defer game.Close()
+
+	snapshot := saveAndLoad(t, game.Snapshot())
+	snapshot.PhaseDeadline = time.Now().Add(100 * time.Millisecond)
+	restored := RestoreGame(snapshot)
+	defer restored.Close()
+
+	if restored.GetGameState().GetIsDay() {
+		t.Fatal("Night must go on after the restore")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !restored.GetGameState().GetIsDay() {
+		if time.Now().After(deadline) {
+			t.Fatal("Night must end when the saved deadline passes")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestFinishedGameIsRestoredWithoutTimers(t *testing.T) {
+	config := NewConfig(4)
+	config.NightDuration = time.Hour
+	game, _ := newStartedGame(t, config)
+	mustSucceed(t, game.ForceFinish("test"))
+
+	restored := RestoreGame(saveAndLoad(t, game.Snapshot()))
+	defer restored.Close()
+
+	if !restored.IsFinished() {
+		t.Fatal("Restored game must stay finished")
+	}
+
+	restored.mu.Lock()
+	defer restored.mu.Unlock()
+	if restored.phaseTimer != nil {
+		t.Fatal("Finished game must not arm the phase timer")
+	}
+}
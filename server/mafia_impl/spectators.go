@@ -0,0 +1,198 @@
+package mafia_impl
+
+import (
+	"log"
+	"soa_mafia/pkg/mafia_grpc"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+)
+
+/*
+	Spectator gets notifications of the game, but takes no seat and can't act.
+	Omniscient spectator also sees roles of all players.
+	Spectators aren't a part of the game state: they aren't logged or saved.
+	Spectator who isn't connected is removed after the reconnect grace period
+*/
+type spectatorInfo struct {
+	token         string
+	omniscient    bool
+	isConnected   bool
+	notifications chan *mafia_grpc.Notification
+	graceTimer    *time.Timer
+}
+
+/*
+	Spectators can join at any time until the game is closed.
+	Returns token of the spectator, it is required to get notifications
+*/
+func (g *Game) AddSpectator(name string, omniscient bool) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.isClosed {
+		return "", NewError(ErrGameClosed, "Game is closed")
+	}
+
+	if name == "" {
+		return "", NewError(ErrInvalidArgument, "Name is empty")
+	}
+
+	_, isPlayer := g.names2players[name]
+	_, isSpectator := g.spectators[name]
+	if isPlayer || isSpectator {
+		return "", NewError(ErrPlayerExists, "Name is already taken")
+	}
+
+	if omniscient && !g.config.OmniscientSpectators {
+		return "", NewError(ErrNotAuthorized, "Omniscient spectators aren't allowed in this session")
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+
+	g.spectators[name] = spectatorInfo{
+		token:         token,
+		omniscient:    omniscient,
+		notifications: make(chan *mafia_grpc.Notification, g.config.NotificationLimit),
+	}
+
+	if g.config.ReconnectGrace != 0 {
+		g.armSpectatorTimer(name)
+	}
+
+	log.Printf("Session %s: Spectator %s joined", g.session, name)
+	return token, nil
+}
+
+func (g *Game) connectSpectator(spectator string, token string) (<-chan *mafia_grpc.Notification, *mafia_grpc.Notification, error) {
+	info := g.spectators[spectator]
+	if !equalTokens(info.token, token) {
+		return nil, nil, NewError(ErrInvalidToken, "Invalid token")
+	}
+
+	notifications := make(chan *mafia_grpc.Notification, g.config.NotificationLimit)
+	if info.notifications != nil {
+		close(info.notifications)
+		for notification := range info.notifications {
+			notifications <- notification
+		}
+	}
+
+	if info.graceTimer != nil {
+		info.graceTimer.Stop()
+		info.graceTimer = nil
+	}
+
+	info.notifications = notifications
+	info.isConnected = true
+	g.spectators[spectator] = info
+
+	snapshot := &mafia_grpc.Notification{
+		Type:      mafia_grpc.NotificationType_STATE,
+		GameState: g.getGameState(),
+	}
+
+	if g.isDay && g.isStarted {
+		snapshot.VoteTally = g.getVoteTally(g.config.OpenBallot)
+	}
+
+	return notifications, g.forSpectator(spectator, snapshot), nil
+}
+
+/*
+	Spectator whose stream has ended has the reconnect grace period to come back,
+	otherwise spectators who left would stay in the game forever
+*/
+func (g *Game) disconnectSpectator(spectator string) {
+	info := g.spectators[spectator]
+	info.isConnected = false
+	g.spectators[spectator] = info
+	log.Printf("Session %s: Spectator %s disconnected", g.session, spectator)
+
+	if g.config.ReconnectGrace == 0 {
+		g.removeSpectator(spectator)
+		return
+	}
+
+	g.armSpectatorTimer(spectator)
+}
+
+func (g *Game) armSpectatorTimer(spectator string) {
+	info := g.spectators[spectator]
+	if info.graceTimer != nil {
+		info.graceTimer.Stop()
+	}
+
+	token := info.token
+	info.graceTimer = time.AfterFunc(g.config.ReconnectGrace, func() {
+		g.onSpectatorTimeout(spectator, token)
+	})
+	g.spectators[spectator] = info
+}
+
+func (g *Game) onSpectatorTimeout(spectator string, token string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	info, exists := g.spectators[spectator]
+	if !exists || info.token != token || info.isConnected {
+		return
+	}
+
+	log.Printf("Session %s: Spectator %s didn't come back", g.session, spectator)
+	g.removeSpectator(spectator)
+}
+
+func (g *Game) removeSpectator(spectator string) {
+	info := g.spectators[spectator]
+	if info.notifications != nil {
+		close(info.notifications)
+	}
+
+	if info.graceTimer != nil {
+		info.graceTimer.Stop()
+	}
+
+	delete(g.spectators, spectator)
+}
+
+func (g *Game) notifySpectators(notification *mafia_grpc.Notification) {
+	for name := range g.spectators {
+		g.pushSpectator(name, g.forSpectator(name, notification))
+	}
+}
+
+func (g *Game) pushSpectator(spectator string, notification *mafia_grpc.Notification) {
+	info := g.spectators[spectator]
+	if info.notifications == nil || g.enqueue(spectator, info.notifications, notification) {
+		return
+	}
+
+	close(info.notifications)
+	info.notifications = nil
+	g.spectators[spectator] = info
+
+	if info.isConnected {
+		g.disconnectSpectator(spectator)
+	}
+}
+
+/*
+	Omniscient spectator gets the copy of the notification with roles of all players
+*/
+func (g *Game) forSpectator(spectator string, notification *mafia_grpc.Notification) *mafia_grpc.Notification {
+	if !g.spectators[spectator].omniscient || !g.isStarted {
+		return notification
+	}
+
+	result := proto.Clone(notification).(*mafia_grpc.Notification)
+	result.Roles = make(map[string]string)
+	for name, info := range g.names2players {
+		result.Roles[name] = string(info.role)
+	}
+
+	return result
+}
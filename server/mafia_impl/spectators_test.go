@@ -0,0 +1,107 @@
+package mafia_impl
+
+import (
+	"testing"
+	"time"
+)
+
+func countSpectators(game *Game) int32 {
+	return game.Info().GetSpectators()
+}
+
+func waitSpectators(t *testing.T, game *Game, count int32) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for countSpectators(game) != count {
+		if time.Now().After(deadline) {
+			t.Fatalf("Game must have %d spectators, it has %d", count, countSpectators(game))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSpectatorIsRemovedWhenStreamEnds(t *testing.T) {
+	config := NewConfig(4)
+	config.ReconnectGrace = 50 * time.Millisecond
+	game, _ := newStartedGame(t, config)
+	defer game.Close()
+
+	token, err := game.AddSpectator("watcher", false)
+	mustSucceed(t, err)
+	notifications, _, err := game.GetNotifications("watcher", token)
+	mustSucceed(t, err)
+
+	game.Disconnect("watcher", notifications)
+	if countSpectators(game) != 1 {
+		t.Fatal("Spectator must have time to reconnect")
+	}
+
+	waitSpectators(t, game, 0)
+	_, _, err = game.GetNotifications("watcher", token)
+	if err == nil {
+		t.Fatal("Removed spectator must not reconnect")
+	}
+}
+
+func TestSpectatorReconnectsDuringGrace(t *testing.T) {
+	config := NewConfig(4)
+	config.ReconnectGrace = 50 * time.Millisecond
+	game, _ := newStartedGame(t, config)
+	defer game.Close()
+
+	token, err := game.AddSpectator("watcher", false)
+	mustSucceed(t, err)
+	first, _, err := game.GetNotifications("watcher", token)
+	mustSucceed(t, err)
+	second, _, err := game.GetNotifications("watcher", token)
+	mustSucceed(t, err)
+
+	// The first stream ends after the spectator has reconnected, it must not disconnect the second one
+	game.Disconnect("watcher", first)
+	time.Sleep(4 * config.ReconnectGrace)
+	if countSpectators(game) != 1 {
+		t.Fatal("Connected spectator must stay")
+	}
+
+	game.Disconnect("watcher", second)
+	_, _, err = game.GetNotifications("watcher", token)
+	mustSucceed(t, err)
+	time.Sleep(4 * config.ReconnectGrace)
+	if countSpectators(game) != 1 {
+		t.Fatal("Spectator who reconnected must stay")
+	}
+}
+
+func TestSpectatorWithoutStreamIsRemoved(t *testing.T) {
+	config := NewConfig(4)
+	config.ReconnectGrace = 50 * time.Millisecond
+	game, _ := newStartedGame(t, config)
+	defer game.Close()
+
+	_, err := game.AddSpectator("watcher", false)
+	mustSucceed(t, err)
+	waitSpectators(t, game, 0)
+}
+
+func TestSlowSpectatorIsDisconnected(t *testing.T) {
+	config := NewConfig(4)
+	config.ReconnectGrace = 0
+	config.NotificationLimit = 1
+	config.OverflowPolicy = Disconnect
+	game := NewGame("test", config)
+	defer game.Close()
+
+	token, err := game.AddSpectator("watcher", false)
+	mustSucceed(t, err)
+	_, _, err = game.GetNotifications("watcher", token)
+	mustSucceed(t, err)
+
+	for i := 0; i < 3; i++ {
+		mustSucceed(t, game.Notice("notice"))
+	}
+
+	if countSpectators(game) != 0 {
+		t.Fatal("Spectator whose queue overflowed must be removed")
+	}
+}
@@ -0,0 +1,264 @@
+package mafia_metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"soa_mafia/pkg/mafia_grpc"
+	mafia_impl "soa_mafia/server/mafia_impl"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+/*
+	Upper bounds of the RPC latency buckets in seconds
+*/
+var latencyBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+/*
+	Phases the players are counted in
+*/
+var phases = []string{"waiting", "night", "day", "runoff", "finished"}
+
+type rpcKey struct {
+	method string
+	code   string
+}
+
+type latency struct {
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+/*
+	Metrics counts RPCs with the interceptors and finished games with RecordGame,
+	state of the sessions is read from the games on every scrape.
+	Metrics is safe for concurrent use
+*/
+type Metrics struct {
+	games func() []*mafia_impl.Game
+
+	mu        sync.Mutex
+	requests  map[rpcKey]uint64
+	latencies map[string]*latency
+	streams   int64
+	wins      map[string]uint64
+	days      float64
+	finished  uint64
+}
+
+/*
+	games returns the games of all sessions the server keeps, it's called on every scrape
+*/
+func NewMetrics(games func() []*mafia_impl.Game) *Metrics {
+	return &Metrics{
+		games:     games,
+		requests:  make(map[rpcKey]uint64),
+		latencies: make(map[string]*latency),
+		wins:      make(map[string]uint64),
+	}
+}
+
+func (m *Metrics) UnaryInterceptor(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	response, err := handler(ctx, request)
+	m.recordCall(path.Base(info.FullMethod), err, time.Since(start))
+	return response, err
+}
+
+/*
+	Streams last for the whole game, so only their number is counted, not their latency
+*/
+func (m *Metrics) StreamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	m.mu.Lock()
+	m.streams++
+	m.mu.Unlock()
+
+	err := handler(srv, stream)
+
+	m.mu.Lock()
+	m.streams--
+	m.requests[rpcKey{path.Base(info.FullMethod), codeOf(err)}]++
+	m.mu.Unlock()
+
+	return err
+}
+
+/*
+	Counts the winner and the length of the finished game, see Game.OnFinish
+*/
+func (m *Metrics) RecordGame(result mafia_impl.GameResult) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	winner := "civilians"
+	if result.MafiaWon {
+		winner = "mafia"
+	}
+
+	m.wins[winner]++
+	m.days += float64(result.Date)
+	m.finished++
+}
+
+func (m *Metrics) recordCall(method string, err error, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[rpcKey{method, codeOf(err)}]++
+
+	stats, exists := m.latencies[method]
+	if !exists {
+		stats = &latency{buckets: make([]uint64, len(latencyBuckets))}
+		m.latencies[method] = stats
+	}
+
+	seconds := duration.Seconds()
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			stats.buckets[i]++
+		}
+	}
+	stats.sum += seconds
+	stats.count++
+}
+
+/*
+	Serves the metrics in the Prometheus text format
+*/
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	fmt.Fprint(w, m.sessionsText())
+	fmt.Fprint(w, m.countersText())
+}
+
+/*
+	Sessions by status and players by the phase of their game
+*/
+func (m *Metrics) sessionsText() string {
+	sessions := map[string]int{"waiting": 0, "started": 0, "finished": 0}
+	players := make(map[string]int32)
+	for _, phase := range phases {
+		players[phase] = 0
+	}
+
+	for _, game := range m.games() {
+		phase := phaseOf(game.GetGameState())
+		switch phase {
+		case "waiting", "finished":
+			sessions[phase]++
+		default:
+			sessions["started"]++
+		}
+		players[phase] += game.Info().Players
+	}
+
+	var sb strings.Builder
+	writeHeader(&sb, "mafia_sessions", "gauge", "Sessions the server keeps by status of their game")
+	for _, status := range []string{"waiting", "started", "finished"} {
+		sb.WriteString(fmt.Sprintf("mafia_sessions{status=%q} %d\n", status, sessions[status]))
+	}
+
+	writeHeader(&sb, "mafia_players", "gauge", "Players by the phase of their game")
+	for _, phase := range phases {
+		sb.WriteString(fmt.Sprintf("mafia_players{phase=%q} %d\n", phase, players[phase]))
+	}
+
+	return sb.String()
+}
+
+func (m *Metrics) countersText() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var sb strings.Builder
+	writeHeader(&sb, "mafia_notification_streams", "gauge", "Connected notification streams")
+	sb.WriteString(fmt.Sprintf("mafia_notification_streams %d\n", m.streams))
+
+	writeHeader(&sb, "mafia_games_won_total", "counter", "Finished games by the winning team")
+	for _, team := range []string{"civilians", "mafia"} {
+		sb.WriteString(fmt.Sprintf("mafia_games_won_total{team=%q} %d\n", team, m.wins[team]))
+	}
+
+	writeHeader(&sb, "mafia_game_length_days", "summary", "Length of the finished games in days")
+	sb.WriteString(fmt.Sprintf("mafia_game_length_days_sum %g\n", m.days))
+	sb.WriteString(fmt.Sprintf("mafia_game_length_days_count %d\n", m.finished))
+
+	keys := make([]rpcKey, 0, len(m.requests))
+	for key := range m.requests {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].method != keys[j].method {
+			return keys[i].method < keys[j].method
+		}
+		return keys[i].code < keys[j].code
+	})
+
+	writeHeader(&sb, "mafia_rpc_requests_total", "counter", "Finished RPCs by method and status code")
+	for _, key := range keys {
+		sb.WriteString(fmt.Sprintf("mafia_rpc_requests_total{method=%q,code=%q} %d\n", key.method, key.code, m.requests[key]))
+	}
+
+	methods := make([]string, 0, len(m.latencies))
+	for method := range m.latencies {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	writeHeader(&sb, "mafia_rpc_duration_seconds", "histogram", "Latency of the unary RPCs by method")
+	for _, method := range methods {
+		stats := m.latencies[method]
+		for i, bound := range latencyBuckets {
+			sb.WriteString(fmt.Sprintf("mafia_rpc_duration_seconds_bucket{method=%q,le=\"%g\"} %d\n", method, bound, stats.buckets[i]))
+		}
+		sb.WriteString(fmt.Sprintf("mafia_rpc_duration_seconds_bucket{method=%q,le=\"+Inf\"} %d\n", method, stats.count))
+		sb.WriteString(fmt.Sprintf("mafia_rpc_duration_seconds_sum{method=%q} %g\n", method, stats.sum))
+		sb.WriteString(fmt.Sprintf("mafia_rpc_duration_seconds_count{method=%q} %d\n", method, stats.count))
+	}
+
+	return sb.String()
+}
+
+/*
+	Streams closed by the client end with the error of the context, it's counted as Canceled
+*/
+func codeOf(err error) string {
+	switch err {
+	case context.Canceled:
+		return codes.Canceled.String()
+	case context.DeadlineExceeded:
+		return codes.DeadlineExceeded.String()
+	}
+
+	return status.Code(err).String()
+}
+
+func phaseOf(state *mafia_grpc.GameState) string {
+	switch {
+	case state.GetIsFinished():
+		return "finished"
+	case !state.GetIsStarted():
+		return "waiting"
+	case state.GetIsRunoff():
+		return "runoff"
+	case state.GetIsDay():
+		return "day"
+	}
+
+	return "night"
+}
+
+func writeHeader(sb *strings.Builder, name string, metricType string, help string) {
+	sb.WriteString(fmt.Sprintf("# HELP %s %s\n", name, help))
+	sb.WriteString(fmt.Sprintf("# TYPE %s %s\n", name, metricType))
+}
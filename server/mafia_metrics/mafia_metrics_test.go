@@ -0,0 +1,114 @@
+package mafia_metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	mafia_impl "soa_mafia/server/mafia_impl"
+
+	"google.golang.org/grpc"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+/*
+	Creates the game of 4 players and seats the given number of them
+*/
+func newGame(t *testing.T, session string, players int) *mafia_impl.Game {
+	t.Helper()
+
+	game := mafia_impl.NewGame(session, mafia_impl.NewConfig(4))
+	for i := 1; i <= players; i++ {
+		if _, err := game.AddPlayer(fmt.Sprintf("p%d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return game
+}
+
+func scrape(metrics *Metrics) string {
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	return recorder.Body.String()
+}
+
+func checkLines(t *testing.T, text string, lines ...string) {
+	t.Helper()
+
+	for _, line := range lines {
+		if !strings.Contains(text, line+"\n") {
+			t.Errorf("Scrape has no line %q:\n%s", line, text)
+		}
+	}
+}
+
+func TestScrapeHasSessionsAndGames(t *testing.T) {
+	waiting := newGame(t, "waiting", 2)
+	started := newGame(t, "started", 4)
+	finished := newGame(t, "finished", 4)
+	if err := finished.ForceFinish("test"); err != nil {
+		t.Fatal(err)
+	}
+	for _, game := range []*mafia_impl.Game{waiting, started, finished} {
+		defer game.Close()
+	}
+
+	metrics := NewMetrics(func() []*mafia_impl.Game {
+		return []*mafia_impl.Game{waiting, started, finished}
+	})
+	metrics.RecordGame(mafia_impl.GameResult{MafiaWon: true, Date: 3})
+	metrics.RecordGame(mafia_impl.GameResult{MafiaWon: false, Date: 2})
+
+	phase := "night"
+	if started.GetGameState().GetIsDay() {
+		phase = "day"
+	}
+
+	checkLines(t, scrape(metrics),
+		`mafia_sessions{status="waiting"} 1`,
+		`mafia_sessions{status="started"} 1`,
+		`mafia_sessions{status="finished"} 1`,
+		`mafia_players{phase="waiting"} 2`,
+		fmt.Sprintf(`mafia_players{phase=%q} 4`, phase),
+		`mafia_players{phase="finished"} 4`,
+		`mafia_players{phase="runoff"} 0`,
+		`mafia_games_won_total{team="mafia"} 1`,
+		`mafia_games_won_total{team="civilians"} 1`,
+		`mafia_game_length_days_sum 5`,
+		`mafia_game_length_days_count 2`,
+		`mafia_notification_streams 0`,
+	)
+}
+
+func TestScrapeCountsCallsByCode(t *testing.T) {
+	metrics := NewMetrics(func() []*mafia_impl.Game { return nil })
+	info := &grpc.UnaryServerInfo{FullMethod: "/mafia.Mafia/Kill"}
+
+	succeed := func(ctx context.Context, request interface{}) (interface{}, error) {
+		return nil, nil
+	}
+	fail := func(ctx context.Context, request interface{}) (interface{}, error) {
+		return nil, mafia_impl.NewError(mafia_impl.ErrWrongPhase, "Mafia kill only at night")
+	}
+
+	metrics.UnaryInterceptor(context.Background(), nil, info, succeed)
+	metrics.UnaryInterceptor(context.Background(), nil, info, fail)
+	metrics.UnaryInterceptor(context.Background(), nil, info, fail)
+
+	checkLines(t, scrape(metrics),
+		`mafia_rpc_requests_total{method="Kill",code="OK"} 1`,
+		`mafia_rpc_requests_total{method="Kill",code="FailedPrecondition"} 2`,
+		`mafia_rpc_duration_seconds_count{method="Kill"} 3`,
+		`mafia_sessions{status="waiting"} 0`,
+	)
+}
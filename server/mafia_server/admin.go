@@ -0,0 +1,151 @@
+package mafia_server
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log"
+
+	"soa_mafia/pkg/mafia_grpc"
+	mafia_impl "soa_mafia/server/mafia_impl"
+)
+
+/*
+	AdminServer implements the MafiaAdmin service on top of the games of the server.
+	If the token isn't empty, every call must have it in the metadata
+*/
+type AdminServer struct {
+	mafia_grpc.UnimplementedMafiaAdminServer
+
+	server *Server
+	token  string
+}
+
+func NewAdminServer(server *Server, token string) *AdminServer {
+	return &AdminServer{server: server, token: token}
+}
+
+func (a *AdminServer) authorize(ctx context.Context) error {
+	if a.token == "" {
+		return nil
+	}
+
+	token := mafia_grpc.AdminTokenFromContext(ctx)
+	if subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) != 1 {
+		return mafia_impl.NewError(mafia_impl.ErrInvalidToken, "Invalid admin token")
+	}
+
+	return nil
+}
+
+/*
+	Lists games of all sessions sorted by name with the players and their roles
+*/
+func (a *AdminServer) ListGames(ctx context.Context, request *mafia_grpc.ListSessionsRequest) (*mafia_grpc.AdminGameList, error) {
+	log.Println(fmt.Sprintf("Admin ListGames: %s", request))
+	err := a.authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &mafia_grpc.AdminGameList{}
+	for _, game := range a.server.listGames(request.GetStatuses()) {
+		list.Games = append(list.Games, &mafia_grpc.AdminGame{Info: game.Info(), Details: game.String()})
+	}
+
+	return list, nil
+}
+
+func (a *AdminServer) FinishGame(ctx context.Context, request *mafia_grpc.AdminSessionRequest) (*mafia_grpc.Response, error) {
+	log.Println(fmt.Sprintf("Admin FinishGame: %s", request))
+	err := a.authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	game, err := a.server.getGame(request.GetSession())
+	if err != nil {
+		return nil, err
+	}
+
+	err = game.ForceFinish(request.GetReason())
+	return &mafia_grpc.Response{Ok: err == nil}, err
+}
+
+/*
+	Removes the session with its game, connected players get the reason before their streams close
+*/
+func (a *AdminServer) DeleteSession(ctx context.Context, request *mafia_grpc.AdminSessionRequest) (*mafia_grpc.Response, error) {
+	log.Println(fmt.Sprintf("Admin DeleteSession: %s", request))
+	err := a.authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	game, err := a.server.removeGame(request.GetSession())
+	if err != nil {
+		return nil, err
+	}
+
+	if request.GetReason() != "" {
+		game.Notice("Session is deleted: " + request.GetReason())
+	}
+	game.Close()
+
+	log.Printf("Session %s deleted, game %s removed", game.Session(), game.ID())
+	return &mafia_grpc.Response{Ok: true}, nil
+}
+
+func (a *AdminServer) KickPlayer(ctx context.Context, request *mafia_grpc.KickRequest) (*mafia_grpc.Response, error) {
+	log.Println(fmt.Sprintf("Admin KickPlayer: %s", request))
+	err := a.authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	game, err := a.server.getGame(request.GetSession())
+	if err != nil {
+		return nil, err
+	}
+
+	err = game.Kick(request.GetPlayer(), request.GetReason())
+	return &mafia_grpc.Response{Ok: err == nil}, err
+}
+
+/*
+	Sends the notice to one session or to all sessions that are open
+*/
+func (a *AdminServer) Broadcast(ctx context.Context, request *mafia_grpc.NoticeRequest) (*mafia_grpc.BroadcastResponse, error) {
+	log.Println(fmt.Sprintf("Admin Broadcast: %s", request))
+	err := a.authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.GetText() == "" {
+		return nil, mafia_impl.NewError(mafia_impl.ErrInvalidArgument, "Notice is empty")
+	}
+
+	if request.GetSession() != "" {
+		game, err := a.server.getGame(request.GetSession())
+		if err != nil {
+			return nil, err
+		}
+
+		err = game.Notice(request.GetText())
+		if err != nil {
+			return nil, err
+		}
+
+		return &mafia_grpc.BroadcastResponse{Sessions: 1}, nil
+	}
+
+	var sessions int32
+	for _, game := range a.server.listGames(nil) {
+		if game.Notice(request.GetText()) == nil {
+			sessions++
+		}
+	}
+
+	return &mafia_grpc.BroadcastResponse{Sessions: sessions}, nil
+}
@@ -0,0 +1,161 @@
+package mafia_server
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"soa_mafia/pkg/mafia_grpc"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func newTestAdminClient(t *testing.T, server *Server, token string) mafia_grpc.MafiaAdminClient {
+	t.Helper()
+
+	register := func(srv *grpc.Server) {
+		mafia_grpc.RegisterMafiaAdminServer(srv, NewAdminServer(server, token))
+	}
+
+	return mafia_grpc.NewMafiaAdminClient(serveTest(t, register, insecure.NewCredentials()))
+}
+
+func listSessions(t *testing.T, admin mafia_grpc.MafiaAdminClient, ctx context.Context, statuses ...mafia_grpc.SessionStatus) []string {
+	t.Helper()
+
+	list, err := admin.ListGames(ctx, &mafia_grpc.ListSessionsRequest{Statuses: statuses})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessions := []string{}
+	for _, game := range list.GetGames() {
+		sessions = append(sessions, game.GetInfo().GetSession())
+	}
+
+	return sessions
+}
+
+func TestAdminListsFinishesAndDeletesSessions(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	client := newTestClient(t, server)
+	admin := newTestAdminClient(t, server, "secret")
+	ctx := mafia_grpc.WithAdminToken(context.Background(), "secret")
+
+	for _, name := range []string{"p1", "p2", "p3", "p4"} {
+		_, _, err := join(client, "game", name, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	waiting, waitingCtx, err := join(client, "lobby", "w", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = admin.ListGames(context.Background(), &mafia_grpc.ListSessionsRequest{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("Admin call without the token must be rejected, got %v", err)
+	}
+
+	list, err := admin.ListGames(ctx, &mafia_grpc.ListSessionsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.GetGames()) != 2 || list.GetGames()[0].GetInfo().GetSession() != "game" || !strings.Contains(list.GetGames()[0].GetDetails(), "Mafia") {
+		t.Fatalf("Both sessions must be listed with the roles, got %s", list)
+	}
+
+	if sessions := listSessions(t, admin, ctx, mafia_grpc.SessionStatus_STARTED); len(sessions) != 1 || sessions[0] != "game" {
+		t.Fatalf("Only the started game must be listed, got %v", sessions)
+	}
+
+	_, err = admin.FinishGame(ctx, &mafia_grpc.AdminSessionRequest{Session: "game", Reason: "stuck"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sessions := listSessions(t, admin, ctx, mafia_grpc.SessionStatus_FINISHED); len(sessions) != 1 || sessions[0] != "game" {
+		t.Fatalf("Stopped game must be finished, got %v", sessions)
+	}
+
+	stream, err := client.GetNotifications(waitingCtx, &mafia_grpc.SubscribeRequest{Player: waiting})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = admin.DeleteSession(ctx, &mafia_grpc.AdminSessionRequest{Session: "lobby", Reason: "maintenance"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notice, err := stream.Recv()
+	if err != nil || notice.GetType() != mafia_grpc.NotificationType_NOTICE || !strings.Contains(notice.GetNotice(), "maintenance") {
+		t.Fatalf("Player must get the reason of the deletion, got %s, %v", notice, err)
+	}
+	if _, err = stream.Recv(); err != io.EOF {
+		t.Fatalf("Stream of the deleted session must end, got %v", err)
+	}
+
+	_, err = client.GetSessionInfo(context.Background(), &mafia_grpc.SessionRequest{Session: "lobby"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("Deleted session must be gone, got %v", err)
+	}
+	if sessions := listSessions(t, admin, ctx); len(sessions) != 1 {
+		t.Fatalf("Only the finished game must be left, got %v", sessions)
+	}
+}
+
+func TestAdminKicksAndBroadcasts(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	client := newTestClient(t, server)
+	admin := newTestAdminClient(t, server, "")
+	ctx := context.Background()
+
+	players := make(map[string]*mafia_grpc.PlayerInfo)
+	contexts := make(map[string]context.Context)
+	for _, name := range []string{"p1", "p2", "p3", "p4", "p5", "p6"} {
+		player, playerCtx, err := join(client, "game", name, &mafia_grpc.SessionConfig{Players: 6})
+		if err != nil {
+			t.Fatal(err)
+		}
+		players[name], contexts[name] = player, playerCtx
+	}
+	_, _, err := join(client, "lobby", "w", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response, err := admin.Broadcast(ctx, &mafia_grpc.NoticeRequest{Text: "restart at noon"})
+	if err != nil || response.GetSessions() != 2 {
+		t.Fatalf("Notice must be sent to both sessions, got %s, %v", response, err)
+	}
+
+	_, err = admin.KickPlayer(ctx, &mafia_grpc.KickRequest{Session: "game", Player: "p2", Reason: "cheating"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := client.GetState(contexts["p1"], players["p1"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, alive := range state.GetAlivePlayers() {
+		if alive == "p2" {
+			t.Fatalf("Kicked player must be out of the game, state: %s", state)
+		}
+	}
+
+	_, err = admin.KickPlayer(ctx, &mafia_grpc.KickRequest{Session: "game", Player: "p2"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("Player can't be kicked twice, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package mafia_server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"soa_mafia/pkg/mafia_grpc"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func checkShutdownNotice(t *testing.T, client mafia_grpc.MafiaClient, player *mafia_grpc.PlayerInfo, ctx context.Context) {
+	t.Helper()
+
+	streamCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	stream, err := client.GetNotifications(streamCtx, &mafia_grpc.SubscribeRequest{Player: player})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for {
+		notification, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Player %s got no SHUTDOWN: %v", player.GetName(), err)
+		}
+		if notification.GetType() == mafia_grpc.NotificationType_SHUTDOWN {
+			if notification.GetNotice() != "maintenance" {
+				t.Fatalf("SHUTDOWN must have the text of the drain, got %q", notification.GetNotice())
+			}
+			return
+		}
+	}
+}
+
+/*
+	Draining server refuses new players, but the games in progress
+	are played to the end and WaitGames returns when they finish
+*/
+func TestDrainRefusesJoinsWhileGamesFinish(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	client := newTestClient(t, server)
+	config := &mafia_grpc.SessionConfig{Players: 4, DayDuration: 1, NightDuration: 1}
+
+	players := []*mafia_grpc.PlayerInfo{}
+	contexts := []context.Context{}
+	for i := 1; i <= 4; i++ {
+		player, ctx, err := join(client, "game", fmt.Sprintf("p%d", i), config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		players, contexts = append(players, player), append(contexts, ctx)
+	}
+	_, _, err := join(client, "lobby", "w1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server.Drain("maintenance")
+
+	for _, session := range []string{"lobby", "new"} {
+		_, _, err = join(client, session, "late", nil)
+		if status.Code(err) != codes.Unavailable || errorReason(err) != mafia_grpc.ErrorReason_SHUTTING_DOWN.String() {
+			t.Fatalf("Join to %s must be refused while draining, got %v", session, err)
+		}
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if server.WaitGames(cancelled) {
+		t.Fatal("WaitGames must give up while the game is in progress")
+	}
+
+	checkShutdownNotice(t, client, players[0], contexts[0])
+
+	var wg sync.WaitGroup
+	for i, player := range players {
+		wg.Add(1)
+		go func(player *mafia_grpc.PlayerInfo, ctx context.Context, seed int64) {
+			defer wg.Done()
+			if err := playRandomly(ctx, client, player, seed); err != nil {
+				t.Errorf("Player %s: %v", player.GetName(), err)
+			}
+		}(player, contexts[i], int64(i))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if !server.WaitGames(ctx) {
+		t.Fatal("Game in progress didn't finish while draining")
+	}
+	wg.Wait()
+
+	for _, game := range server.Games() {
+		if game.Session() == "game" && !game.IsFinished() {
+			t.Fatalf("Game must be played to the end, state: %s", game.GetGameState())
+		}
+	}
+}
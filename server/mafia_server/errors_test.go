@@ -0,0 +1,161 @@
+package mafia_server
+
+import (
+	"context"
+	"testing"
+
+	"soa_mafia/pkg/mafia_grpc"
+	mafia_impl "soa_mafia/server/mafia_impl"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+/*
+	Returns the reason of the error from its ErrorInfo details, empty if there is none
+*/
+func errorReason(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if ok && info.GetDomain() == mafia_impl.ErrorDomain {
+			return info.GetReason()
+		}
+	}
+
+	return ""
+}
+
+/*
+	Every kind of the game errors reaches the client with its code and reason.
+	Cases run in order on the same game: 4 players are dealt mafia, detective,
+	doctor and civilian, it's the first night
+*/
+func TestGameErrorsReachTheClient(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	config := &mafia_grpc.SessionConfig{Players: 4, Roles: map[string]int32{"Mafia": 1, "Detective": 1, "Doctor": 1}}
+	players := make(map[string]*mafia_grpc.PlayerInfo)
+	contexts := make(map[string]context.Context)
+	for _, name := range []string{"p1", "p2", "p3", "p4"} {
+		player, ctx, err := join(client, "s", name, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		players[name], contexts[name] = player, ctx
+	}
+
+	game, err := server.getGame("s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	role2name := make(map[mafia_impl.Role]string)
+	for _, player := range game.Snapshot().Players {
+		role2name[player.Role] = player.Name
+	}
+
+	act := func(role mafia_impl.Role) (*mafia_grpc.PlayerInfo, context.Context) {
+		name := role2name[role]
+		return players[name], contexts[name]
+	}
+	request := func(role mafia_impl.Role, target string) (context.Context, *mafia_grpc.SetVictimRequest) {
+		player, ctx := act(role)
+		return ctx, &mafia_grpc.SetVictimRequest{Player: player, Victim: target}
+	}
+
+	waiting, waitingCtx, err := join(client, "w", "a", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name   string
+		call   func() error
+		code   codes.Code
+		reason mafia_grpc.ErrorReason
+	}{
+		{"unknown session", func() error {
+			_, err := client.GetSessionInfo(context.Background(), &mafia_grpc.SessionRequest{Session: "none"})
+			return err
+		}, codes.NotFound, mafia_grpc.ErrorReason_NO_SESSION},
+		{"invalid config", func() error {
+			_, _, err := join(client, "small", "a", &mafia_grpc.SessionConfig{Players: 3})
+			return err
+		}, codes.InvalidArgument, mafia_grpc.ErrorReason_INVALID_ARGUMENT},
+		{"name is taken", func() error {
+			_, _, err := join(client, "w", "a", nil)
+			return err
+		}, codes.AlreadyExists, mafia_grpc.ErrorReason_PLAYER_EXISTS},
+		{"game hasn't started", func() error {
+			_, err := client.Vote(waitingCtx, &mafia_grpc.SetVictimRequest{Player: waiting, Victim: "a"})
+			return err
+		}, codes.FailedPrecondition, mafia_grpc.ErrorReason_NOT_STARTED},
+		{"table is full", func() error {
+			_, _, err := join(client, "s", "p5", nil)
+			return err
+		}, codes.ResourceExhausted, mafia_grpc.ErrorReason_SESSION_FULL},
+		{"wrong token", func() error {
+			_, err := client.GetState(mafia_grpc.WithToken(context.Background(), "wrong"), players["p1"])
+			return err
+		}, codes.Unauthenticated, mafia_grpc.ErrorReason_INVALID_TOKEN},
+		{"unknown player", func() error {
+			_, err := client.GetState(contexts["p1"], &mafia_grpc.PlayerInfo{Session: "s", Name: "p9"})
+			return err
+		}, codes.NotFound, mafia_grpc.ErrorReason_NO_PLAYER},
+		{"civilian kills", func() error {
+			_, err := client.Kill(request(mafia_impl.Civilian, role2name[mafia_impl.Doctor]))
+			return err
+		}, codes.PermissionDenied, mafia_grpc.ErrorReason_NOT_AUTHORIZED},
+		{"vote at night", func() error {
+			_, err := client.Vote(request(mafia_impl.Civilian, role2name[mafia_impl.Doctor]))
+			return err
+		}, codes.FailedPrecondition, mafia_grpc.ErrorReason_WRONG_PHASE},
+		{"unknown target", func() error {
+			_, err := client.Kill(request(mafia_impl.Mafia, "nobody"))
+			return err
+		}, codes.NotFound, mafia_grpc.ErrorReason_TARGET_NOT_FOUND},
+		{"doctor heals themself", func() error {
+			_, err := client.Heal(request(mafia_impl.Doctor, role2name[mafia_impl.Doctor]))
+			return err
+		}, codes.FailedPrecondition, mafia_grpc.ErrorReason_RULE_VIOLATION},
+		{"second check", func() error {
+			_, err := client.CheckIfMafia(request(mafia_impl.Detective, role2name[mafia_impl.Civilian]))
+			if err != nil {
+				return err
+			}
+			_, err = client.CheckIfMafia(request(mafia_impl.Detective, role2name[mafia_impl.Mafia]))
+			return err
+		}, codes.FailedPrecondition, mafia_grpc.ErrorReason_NOT_YOUR_TURN},
+		{"player has quit", func() error {
+			player, ctx := act(mafia_impl.Civilian)
+			_, err := client.Quit(ctx, player)
+			if err != nil {
+				return err
+			}
+			stream, err := client.GetNotifications(ctx, &mafia_grpc.SubscribeRequest{Player: player})
+			if err != nil {
+				return err
+			}
+			_, err = stream.Recv()
+			return err
+		}, codes.FailedPrecondition, mafia_grpc.ErrorReason_PLAYER_LEFT},
+		{"dead target", func() error {
+			_, err := client.Kill(request(mafia_impl.Mafia, role2name[mafia_impl.Civilian]))
+			return err
+		}, codes.FailedPrecondition, mafia_grpc.ErrorReason_TARGET_DEAD},
+		{"server is draining", func() error {
+			server.Drain("")
+			_, _, err := join(client, "new", "a", nil)
+			return err
+		}, codes.Unavailable, mafia_grpc.ErrorReason_SHUTTING_DOWN},
+	}
+
+	for _, c := range cases {
+		err := c.call()
+		if status.Code(err) != c.code || errorReason(err) != c.reason.String() {
+			t.Errorf("%s: error must have code %s and reason %s, got %v (reason %q)", c.name, c.code, c.reason, err, errorReason(err))
+		}
+	}
+}
@@ -0,0 +1,928 @@
+package mafia_server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"soa_mafia/pkg/mafia_grpc"
+	mafia_bot "soa_mafia/server/mafia_bot"
+	mafia_impl "soa_mafia/server/mafia_impl"
+	mafia_stats "soa_mafia/server/mafia_stats"
+	mafia_storage "soa_mafia/server/mafia_storage"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+/*
+	Server implements the Mafia service: it keeps the current game of every session
+	and the statistics of the players, which every finished game adds to.
+	Background work (snapshots, cleanup, bots) is started by the Run methods
+	and stops on Close
+*/
+type Server struct {
+	mafia_grpc.UnimplementedMafiaServer
+
+	mu           sync.RWMutex
+	session2game map[string]*mafia_impl.Game
+	isDraining   bool
+	keepLogs     bool
+	retired      []*mafia_impl.Game
+
+	saveMu   sync.Mutex
+	isClosed bool
+	done     chan struct{}
+
+	stats      *mafia_stats.Stats
+	onFinish   []func(mafia_impl.GameResult)
+	botOptions mafia_bot.Options
+	defaults   mafia_impl.Config
+}
+
+/*
+	New sessions get the defaults for the values their config leaves out, see ConfigWithDefaults
+*/
+func NewServer(botOptions mafia_bot.Options, defaults mafia_impl.Config) *Server {
+	return &Server{
+		session2game: make(map[string]*mafia_impl.Game),
+		stats:        mafia_stats.NewStats(),
+		done:         make(chan struct{}),
+		botOptions:   botOptions,
+		defaults:     defaults,
+	}
+}
+
+/*
+	Adds the handler of the results of finished games besides the statistics,
+	handlers must be added before the server restores or creates any game
+*/
+func (s *Server) OnFinish(handler func(mafia_impl.GameResult)) {
+	s.onFinish = append(s.onFinish, handler)
+}
+
+/*
+	Game is added under the session, its result goes to the statistics when it finishes.
+	Previous game of the session is retired. Must be called with mu locked
+*/
+func (s *Server) addGame(game *mafia_impl.Game) {
+	if previous, exists := s.session2game[game.Session()]; exists && previous != game {
+		s.retire(previous)
+	}
+
+	game.OnFinish(s.recordResult)
+	s.session2game[game.Session()] = game
+}
+
+/*
+	Game that leaves the server waits for Save to keep its log, see Restore.
+	Must be called with mu locked
+*/
+func (s *Server) retire(game *mafia_impl.Game) {
+	if s.keepLogs {
+		s.retired = append(s.retired, game)
+	}
+}
+
+func (s *Server) recordResult(result mafia_impl.GameResult) {
+	s.stats.Record(result)
+	for _, handler := range s.onFinish {
+		handler(result)
+	}
+}
+
+func (s *Server) getGame(session string) (*mafia_impl.Game, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	game, exists := s.session2game[session]
+	if !exists {
+		return nil, mafia_impl.NewError(mafia_impl.ErrNoSession, "No game session: "+session)
+	}
+
+	return game, nil
+}
+
+/*
+	Returns games sorted by session, only with the given statuses if there are any
+*/
+func (s *Server) listGames(statuses []mafia_grpc.SessionStatus) []*mafia_impl.Game {
+	wanted := make(map[mafia_grpc.SessionStatus]bool)
+	for _, status := range statuses {
+		wanted[status] = true
+	}
+
+	s.mu.RLock()
+	games := make([]*mafia_impl.Game, 0, len(s.session2game))
+	for _, game := range s.session2game {
+		games = append(games, game)
+	}
+	s.mu.RUnlock()
+
+	result := []*mafia_impl.Game{}
+	for _, game := range games {
+		if len(wanted) == 0 || wanted[game.Info().Status] {
+			result = append(result, game)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Session() < result[j].Session()
+	})
+
+	return result
+}
+
+/*
+	Returns games of all sessions sorted by session
+*/
+func (s *Server) Games() []*mafia_impl.Game {
+	return s.listGames(nil)
+}
+
+/*
+	Removes the session, its game is returned to be closed
+*/
+func (s *Server) removeGame(session string) (*mafia_impl.Game, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	game, exists := s.session2game[session]
+	if !exists {
+		return nil, mafia_impl.NewError(mafia_impl.ErrNoSession, "No game session: "+session)
+	}
+
+	delete(s.session2game, session)
+	s.retire(game)
+	return game, nil
+}
+
+/*
+	New players, spectators and bots aren't accepted while the server is draining
+*/
+func (s *Server) checkAccepting() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.isDraining {
+		return mafia_impl.NewError(mafia_impl.ErrShuttingDown, "Server is shutting down, no new players are accepted")
+	}
+
+	return nil
+}
+
+/*
+	With mutual TLS the client certificate identifies the player:
+	the name in the request must be the common name of the certificate
+*/
+func checkPeerName(ctx context.Context, name string) error {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.PeerCertificates) == 0 {
+		return nil
+	}
+
+	commonName := info.State.PeerCertificates[0].Subject.CommonName
+	if commonName != name {
+		return mafia_impl.NewError(mafia_impl.ErrNotAuthorized, fmt.Sprintf("Client certificate is issued to %s, not to %s", commonName, name))
+	}
+
+	return nil
+}
+
+/*
+	Returns game of the player if the call has the token of the player in the metadata,
+	so nobody can act on behalf of another player
+*/
+func (s *Server) getAuthorizedGame(ctx context.Context, player *mafia_grpc.PlayerInfo) (*mafia_impl.Game, error) {
+	err := checkPeerName(ctx, player.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	game, err := s.getGame(player.GetSession())
+	if err != nil {
+		return nil, err
+	}
+
+	err = game.Authenticate(player.GetName(), mafia_grpc.TokenFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
+
+/*
+	Returns game of the session, new game is created with the given config
+	if the session doesn't exist yet or its game has finished.
+	Password of the new game makes it private
+*/
+func (s *Server) getOrCreateGame(session string, config *mafia_grpc.SessionConfig, password string) (*mafia_impl.Game, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	game, exists := s.session2game[session]
+	if exists && !game.IsFinished() {
+		return game, game.CheckPassword(password)
+	}
+
+	gameConfig, err := mafia_impl.ConfigWithDefaults(config, s.defaults)
+	if err != nil {
+		return nil, err
+	}
+	gameConfig.SetPassword(password)
+
+	if exists {
+		game = mafia_impl.NewRematch(game, gameConfig)
+	} else {
+		game = mafia_impl.NewGame(session, gameConfig)
+	}
+
+	s.addGame(game)
+	return game, nil
+}
+
+/*
+	Returns the next game of the session with the config of the finished one.
+	The config has the seed only if the organizer set it, otherwise the rematch gets a new one
+*/
+func (s *Server) getRematchGame(session string) (*mafia_impl.Game, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	game, exists := s.session2game[session]
+	if !exists {
+		return nil, mafia_impl.NewError(mafia_impl.ErrNoSession, "No game session: "+session)
+	}
+
+	if game.IsFinished() {
+		game = mafia_impl.NewRematch(game, game.Config())
+		s.addGame(game)
+	}
+
+	return game, nil
+}
+
+/*
+	Removes expired sessions, see Game.IsExpired
+*/
+func (s *Server) cleanup(finishedTTL time.Duration, idleTTL time.Duration) {
+	now := time.Now()
+	expired := []*mafia_impl.Game{}
+
+	s.mu.Lock()
+	for session, game := range s.session2game {
+		if game.IsExpired(now, finishedTTL, idleTTL) {
+			delete(s.session2game, session)
+			s.retire(game)
+			expired = append(expired, game)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, game := range expired {
+		log.Printf("Session %s expired, game %s removed", game.Session(), game.ID())
+		game.Close()
+	}
+}
+
+/*
+	Restores statistics and unfinished games from the storage, snapshots of finished games are dropped,
+	but their logs are kept. After that games leaving the server are kept until Save writes their logs
+*/
+func (s *Server) Restore(storage *mafia_storage.Storage) error {
+	players, err := storage.LoadStats()
+	if err != nil {
+		return err
+	}
+	s.stats.Restore(players)
+
+	snapshots, err := storage.Load()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keepLogs = true
+	for _, snapshot := range snapshots {
+		if snapshot.IsFinished {
+			if !storage.HasLog(snapshot.ID) {
+				err = storage.SaveLog(snapshot.ID, snapshot.Events)
+				if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		game := mafia_impl.RestoreGame(snapshot)
+		s.addGame(game)
+
+		for name, token := range game.Bots() {
+			err = mafia_bot.Start(game, name, token, s.botOptions)
+			if err != nil {
+				log.Printf("Session %s: Bot %s can't be restarted: %v", game.Session(), name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) snapshots() []mafia_impl.Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshots := []mafia_impl.Snapshot{}
+	for _, game := range s.session2game {
+		snapshots = append(snapshots, game.Snapshot())
+	}
+
+	return snapshots
+}
+
+/*
+	Saves snapshots of the games and the statistics. Closed games are finished,
+	so nothing is saved after Close to keep the last snapshots restorable
+*/
+func (s *Server) Save(storage *mafia_storage.Storage) error {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	if s.isClosed {
+		return nil
+	}
+
+	// Logs go first, so the log of a finished game is kept before its snapshot is deleted
+	err := s.saveLogs(storage)
+	if err != nil {
+		return fmt.Errorf("failed to save logs: %w", err)
+	}
+
+	err = storage.Sync(s.snapshots())
+	if err != nil {
+		return fmt.Errorf("failed to save snapshots: %w", err)
+	}
+
+	err = storage.SaveStats(s.stats.Players())
+	if err != nil {
+		return fmt.Errorf("failed to save statistics: %w", err)
+	}
+
+	return nil
+}
+
+/*
+	Writes logs of the finished games and of the games that have left the server
+*/
+func (s *Server) saveLogs(storage *mafia_storage.Storage) error {
+	s.mu.Lock()
+	retired := s.retired
+	s.retired = nil
+	current := make([]*mafia_impl.Game, 0, len(s.session2game))
+	for _, game := range s.session2game {
+		current = append(current, game)
+	}
+	s.mu.Unlock()
+
+	for i, game := range retired {
+		err := saveLog(storage, game)
+		if err != nil {
+			s.mu.Lock()
+			s.retired = append(s.retired, retired[i:]...)
+			s.mu.Unlock()
+			return err
+		}
+	}
+
+	for _, game := range current {
+		if !game.IsFinished() {
+			continue
+		}
+
+		err := saveLog(storage, game)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func saveLog(storage *mafia_storage.Storage, game *mafia_impl.Game) error {
+	if storage.HasLog(game.ID()) {
+		return nil
+	}
+
+	return storage.SaveLog(game.ID(), game.Events())
+}
+
+func (s *Server) RunSnapshots(storage *mafia_storage.Storage, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			err := s.Save(storage)
+			if err != nil {
+				log.Println(err)
+			}
+		}
+	}
+}
+
+/*
+	Starts the drain before the stop: new players aren't accepted anymore
+	and everyone in the games gets SHUTDOWN with the text
+*/
+func (s *Server) Drain(text string) {
+	s.mu.Lock()
+	s.isDraining = true
+	s.mu.Unlock()
+
+	for _, game := range s.listGames(nil) {
+		game.Shutdown(text)
+	}
+}
+
+/*
+	Waits until no game is in progress, returns false if the context is done first
+*/
+func (s *Server) WaitGames(ctx context.Context) bool {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		started := len(s.listGames([]mafia_grpc.SessionStatus{mafia_grpc.SessionStatus_STARTED}))
+		if started == 0 {
+			return true
+		}
+
+		log.Printf("Waiting for %d games in progress", started)
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+/*
+	Stops the background work and closes all games, so notification streams end
+	and the gRPC server can stop gracefully. Must be called once
+*/
+func (s *Server) Close() {
+	s.saveMu.Lock()
+	s.isClosed = true
+	s.saveMu.Unlock()
+
+	close(s.done)
+	for _, game := range s.listGames(nil) {
+		game.Close()
+	}
+}
+
+/*
+	Gives empty seats to bots in the sessions that have waited long enough, see Game.SeatsToFill
+*/
+func (s *Server) fillWithBots() {
+	if s.checkAccepting() != nil {
+		return
+	}
+
+	now := time.Now()
+
+	s.mu.RLock()
+	games := make([]*mafia_impl.Game, 0, len(s.session2game))
+	for _, game := range s.session2game {
+		games = append(games, game)
+	}
+	s.mu.RUnlock()
+
+	for _, game := range games {
+		seats := game.SeatsToFill(now)
+		if seats == 0 {
+			continue
+		}
+
+		names, err := mafia_bot.Fill(game, seats, s.botOptions)
+		if len(names) > 0 {
+			log.Printf("Session %s: Bots %v took the empty seats", game.Session(), names)
+		}
+
+		if err != nil {
+			log.Printf("Session %s: Failed to add bots: %v", game.Session(), err)
+		}
+	}
+}
+
+func (s *Server) RunBotFill(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.fillWithBots()
+		}
+	}
+}
+
+func (s *Server) RunCleanup(interval time.Duration, finishedTTL time.Duration, idleTTL time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.cleanup(finishedTTL, idleTTL)
+		}
+	}
+}
+
+func (s *Server) Join(ctx context.Context, request *mafia_grpc.JoinRequest) (*mafia_grpc.JoinResponse, error) {
+	// Request isn't logged as a whole to keep the password out of the log
+	log.Println(fmt.Sprintf("Join: %s config: %s", request.GetPlayer(), request.GetConfig()))
+	err := s.checkAccepting()
+	if err != nil {
+		return &mafia_grpc.JoinResponse{Ok: false}, err
+	}
+
+	player := request.GetPlayer()
+	session := player.GetSession()
+	name := player.GetName()
+
+	err = checkPeerName(ctx, name)
+	if err != nil {
+		return &mafia_grpc.JoinResponse{Ok: false}, err
+	}
+
+	game, err := s.getOrCreateGame(session, request.GetConfig(), request.GetPassword())
+	if err != nil {
+		return &mafia_grpc.JoinResponse{Ok: false}, err
+	}
+
+	token, err := game.AddPlayer(name)
+	return &mafia_grpc.JoinResponse{Ok: err == nil, Token: token, GameId: game.ID()}, err
+}
+
+func (s *Server) Rematch(ctx context.Context, player *mafia_grpc.PlayerInfo) (*mafia_grpc.JoinResponse, error) {
+	log.Println(fmt.Sprintf("Rematch: %s", player))
+	err := s.checkAccepting()
+	if err != nil {
+		return &mafia_grpc.JoinResponse{Ok: false}, err
+	}
+
+	session := player.GetSession()
+	name := player.GetName()
+
+	err = checkPeerName(ctx, name)
+	if err != nil {
+		return &mafia_grpc.JoinResponse{Ok: false}, err
+	}
+
+	game, err := s.getRematchGame(session)
+	if err != nil {
+		return &mafia_grpc.JoinResponse{Ok: false}, err
+	}
+
+	token, err := game.AddRematchPlayer(name, mafia_grpc.TokenFromContext(ctx))
+	return &mafia_grpc.JoinResponse{Ok: err == nil, Token: token, GameId: game.ID()}, err
+}
+
+func (s *Server) Spectate(ctx context.Context, request *mafia_grpc.SpectateRequest) (*mafia_grpc.JoinResponse, error) {
+	log.Println(fmt.Sprintf("Spectate: %s omniscient: %t", request.GetPlayer(), request.GetOmniscient()))
+	err := s.checkAccepting()
+	if err != nil {
+		return &mafia_grpc.JoinResponse{Ok: false}, err
+	}
+
+	player := request.GetPlayer()
+	session := player.GetSession()
+	name := player.GetName()
+
+	err = checkPeerName(ctx, name)
+	if err != nil {
+		return &mafia_grpc.JoinResponse{Ok: false}, err
+	}
+
+	game, err := s.getGame(session)
+	if err != nil {
+		return &mafia_grpc.JoinResponse{Ok: false}, err
+	}
+
+	err = game.CheckPassword(request.GetPassword())
+	if err != nil {
+		return &mafia_grpc.JoinResponse{Ok: false}, err
+	}
+
+	token, err := game.AddSpectator(name, request.GetOmniscient())
+	return &mafia_grpc.JoinResponse{Ok: err == nil, Token: token, GameId: game.ID()}, err
+}
+
+/*
+	Lists sessions sorted by name, only with the given statuses if there are any
+*/
+func (s *Server) ListSessions(ctx context.Context, request *mafia_grpc.ListSessionsRequest) (*mafia_grpc.SessionList, error) {
+	log.Println(fmt.Sprintf("ListSessions: %s", request))
+
+	sessions := []*mafia_grpc.SessionInfo{}
+	for _, game := range s.listGames(request.GetStatuses()) {
+		sessions = append(sessions, game.Info())
+	}
+
+	return &mafia_grpc.SessionList{Sessions: sessions}, nil
+}
+
+func (s *Server) GetSessionInfo(ctx context.Context, request *mafia_grpc.SessionRequest) (*mafia_grpc.SessionInfo, error) {
+	log.Println(fmt.Sprintf("GetSessionInfo: %s", request))
+
+	game, err := s.getGame(request.GetSession())
+	if err != nil {
+		return nil, err
+	}
+
+	return game.Info(), nil
+}
+
+/*
+	Adds the given number of bots, all empty seats are taken if the count is 0
+*/
+func (s *Server) AddBots(ctx context.Context, request *mafia_grpc.AddBotsRequest) (*mafia_grpc.Response, error) {
+	log.Println(fmt.Sprintf("AddBots: %s", request))
+	err := s.checkAccepting()
+	if err != nil {
+		return nil, err
+	}
+
+	player := request.GetPlayer()
+	game, err := s.getAuthorizedGame(ctx, player)
+	if err != nil {
+		return nil, err
+	}
+
+	seats, err := game.SeatsForBots(player.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	count := request.GetCount()
+	if count < 0 {
+		return nil, mafia_impl.NewError(mafia_impl.ErrInvalidArgument, "Number of bots is negative")
+	}
+
+	if count > seats {
+		return nil, mafia_impl.NewError(mafia_impl.ErrSessionFull, fmt.Sprintf("Only %d seats are empty", seats))
+	}
+
+	if count == 0 {
+		count = seats
+	}
+
+	_, err = mafia_bot.Fill(game, count, s.botOptions)
+	return &mafia_grpc.Response{Ok: err == nil}, err
+}
+
+func (s *Server) GetPlayerStats(ctx context.Context, request *mafia_grpc.PlayerStatsRequest) (*mafia_grpc.PlayerStats, error) {
+	log.Println(fmt.Sprintf("GetPlayerStats: %s", request))
+
+	stats, rank, err := s.stats.Player(request.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	return stats.ToProto(rank), nil
+}
+
+/*
+	Returns the best players by rating, ten of them if the limit isn't given
+*/
+func (s *Server) GetLeaderboard(ctx context.Context, request *mafia_grpc.LeaderboardRequest) (*mafia_grpc.Leaderboard, error) {
+	log.Println(fmt.Sprintf("GetLeaderboard: %s", request))
+
+	limit := request.GetLimit()
+	if limit < 0 || request.GetMinGames() < 0 {
+		return nil, mafia_impl.NewError(mafia_impl.ErrInvalidArgument, "Limit and number of games can't be negative")
+	}
+
+	if limit == 0 {
+		limit = mafia_stats.DefaultLeaderboardLimit
+	}
+
+	players, total := s.stats.Leaderboard(limit, request.GetMinGames())
+	leaderboard := &mafia_grpc.Leaderboard{Total: total}
+	for i, player := range players {
+		leaderboard.Players = append(leaderboard.Players, player.ToProto(int32(i+1)))
+	}
+
+	return leaderboard, nil
+}
+
+func (s *Server) Vote(ctx context.Context, request *mafia_grpc.SetVictimRequest) (*mafia_grpc.Response, error) {
+	log.Println(fmt.Sprintf("Vote: %s", request))
+	player := request.GetPlayer()
+	victim := request.GetVictim()
+	name := player.GetName()
+
+	game, err := s.getAuthorizedGame(ctx, player)
+	if err != nil {
+		return nil, err
+	}
+
+	err = game.AddVote(name, victim)
+	return &mafia_grpc.Response{Ok: err == nil}, err
+}
+
+func (s *Server) ChangeVote(ctx context.Context, request *mafia_grpc.SetVictimRequest) (*mafia_grpc.Response, error) {
+	log.Println(fmt.Sprintf("ChangeVote: %s", request))
+	player := request.GetPlayer()
+	victim := request.GetVictim()
+	name := player.GetName()
+
+	game, err := s.getAuthorizedGame(ctx, player)
+	if err != nil {
+		return nil, err
+	}
+
+	err = game.ChangeVote(name, victim)
+	return &mafia_grpc.Response{Ok: err == nil}, err
+}
+
+func (s *Server) WithdrawVote(ctx context.Context, player *mafia_grpc.PlayerInfo) (*mafia_grpc.Response, error) {
+	log.Println(fmt.Sprintf("WithdrawVote: %s", player))
+	name := player.GetName()
+
+	game, err := s.getAuthorizedGame(ctx, player)
+	if err != nil {
+		return nil, err
+	}
+
+	err = game.WithdrawVote(name)
+	return &mafia_grpc.Response{Ok: err == nil}, err
+}
+
+func (s *Server) SkipVote(ctx context.Context, player *mafia_grpc.PlayerInfo) (*mafia_grpc.Response, error) {
+	log.Println(fmt.Sprintf("SkipVote: %s", player))
+	name := player.GetName()
+
+	game, err := s.getAuthorizedGame(ctx, player)
+	if err != nil {
+		return nil, err
+	}
+
+	err = game.SkipVote(name)
+	return &mafia_grpc.Response{Ok: err == nil}, err
+}
+
+func (s *Server) GetVotes(ctx context.Context, player *mafia_grpc.PlayerInfo) (*mafia_grpc.VoteTally, error) {
+	log.Println(fmt.Sprintf("GetVotes: %s", player))
+	name := player.GetName()
+
+	game, err := s.getAuthorizedGame(ctx, player)
+	if err != nil {
+		return nil, err
+	}
+
+	return game.GetVotes(name)
+}
+
+func (s *Server) Kill(ctx context.Context, request *mafia_grpc.SetVictimRequest) (*mafia_grpc.Response, error) {
+	log.Println(fmt.Sprintf("Kill: %s", request))
+	player := request.GetPlayer()
+	victim := request.GetVictim()
+	name := player.GetName()
+
+	game, err := s.getAuthorizedGame(ctx, player)
+	if err != nil {
+		return nil, err
+	}
+
+	err = game.KillPlayer(name, victim)
+	return &mafia_grpc.Response{Ok: err == nil}, err
+}
+
+func (s *Server) CheckIfMafia(ctx context.Context, request *mafia_grpc.SetVictimRequest) (*mafia_grpc.CheckMafiaResponse, error) {
+	log.Println(fmt.Sprintf("CheckIfMafia: %s", request))
+	player := request.GetPlayer()
+	victim := request.GetVictim()
+	name := player.GetName()
+
+	game, err := s.getAuthorizedGame(ctx, player)
+	if err != nil {
+		return nil, err
+	}
+
+	isMafia, err := game.CheckIfMafia(name, victim)
+	return &mafia_grpc.CheckMafiaResponse{IsMafia: isMafia}, err
+}
+
+func (s *Server) Heal(ctx context.Context, request *mafia_grpc.SetVictimRequest) (*mafia_grpc.Response, error) {
+	log.Println(fmt.Sprintf("Heal: %s", request))
+	player := request.GetPlayer()
+	patient := request.GetVictim()
+	name := player.GetName()
+
+	game, err := s.getAuthorizedGame(ctx, player)
+	if err != nil {
+		return nil, err
+	}
+
+	err = game.HealPlayer(name, patient)
+	return &mafia_grpc.Response{Ok: err == nil}, err
+}
+
+func (s *Server) GetState(ctx context.Context, player *mafia_grpc.PlayerInfo) (*mafia_grpc.GameState, error) {
+	log.Println(fmt.Sprintf("GetState: %s", player))
+
+	game, err := s.getAuthorizedGame(ctx, player)
+	if err != nil {
+		return nil, err
+	}
+
+	state := game.GetGameState()
+	return state, nil
+}
+
+func (s *Server) CanChat(ctx context.Context, player *mafia_grpc.PlayerInfo) (*mafia_grpc.ChatResponse, error) {
+	log.Println(fmt.Sprintf("CanChat: %s", player))
+	name := player.GetName()
+
+	game, err := s.getAuthorizedGame(ctx, player)
+	if err != nil {
+		return nil, err
+	}
+
+	canChat, err := game.CanChat(name)
+	return &mafia_grpc.ChatResponse{CanChat: canChat}, err
+}
+
+func (s *Server) Quit(ctx context.Context, player *mafia_grpc.PlayerInfo) (*mafia_grpc.Response, error) {
+	log.Println(fmt.Sprintf("Quit: %s", player))
+	name := player.GetName()
+
+	game, err := s.getAuthorizedGame(ctx, player)
+	if err != nil {
+		return nil, err
+	}
+
+	err = game.DeletePlayer(name)
+	return &mafia_grpc.Response{Ok: err == nil}, nil
+}
+
+func (s *Server) GetNotifications(request *mafia_grpc.SubscribeRequest, stream mafia_grpc.Mafia_GetNotificationsServer) error {
+	log.Println(fmt.Sprintf("GetNotifications: %s", request.GetPlayer()))
+	player := request.GetPlayer()
+	session := player.GetSession()
+	name := player.GetName()
+
+	err := checkPeerName(stream.Context(), name)
+	if err != nil {
+		return err
+	}
+
+	game, err := s.getGame(session)
+	if err != nil {
+		return err
+	}
+
+	notifications, snapshot, err := game.GetNotifications(name, mafia_grpc.TokenFromContext(stream.Context()))
+	if err != nil {
+		return err
+	}
+
+	err = stream.Send(snapshot)
+	for err == nil {
+		select {
+		case <-stream.Context().Done():
+			err = stream.Context().Err()
+		case notification, ok := <-notifications:
+			if !ok {
+				return nil
+			}
+
+			log.Printf("Sending to %s notification %s", name, notification.Type)
+			err = stream.Send(notification)
+		}
+	}
+
+	log.Printf("Player %s: Notifications connection lost\n", name)
+	game.Disconnect(name, notifications)
+	return err
+}
@@ -0,0 +1,737 @@
+package mafia_server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"soa_mafia/pkg/mafia_grpc"
+	mafia_bot "soa_mafia/server/mafia_bot"
+	mafia_impl "soa_mafia/server/mafia_impl"
+	mafia_storage "soa_mafia/server/mafia_storage"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+/*
+	Server logs every call, it's too much for the tests
+*/
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+/*
+	Serves the server over the in-memory connection, it's stopped at the end of the test
+*/
+func newTestClient(t *testing.T, server *Server, options ...grpc.ServerOption) mafia_grpc.MafiaClient {
+	t.Helper()
+
+	return dialTestServer(t, server, insecure.NewCredentials(), options...)
+}
+
+func dialTestServer(t *testing.T, server *Server, creds credentials.TransportCredentials, options ...grpc.ServerOption) mafia_grpc.MafiaClient {
+	t.Helper()
+
+	register := func(srv *grpc.Server) {
+		mafia_grpc.RegisterMafiaServer(srv, server)
+	}
+
+	return mafia_grpc.NewMafiaClient(serveTest(t, register, creds, options...))
+}
+
+/*
+	Serves the services the register function adds and returns the connection to them
+*/
+func serveTest(t *testing.T, register func(*grpc.Server), creds credentials.TransportCredentials, options ...grpc.ServerOption) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(options...)
+	register(srv)
+	go srv.Serve(lis)
+
+	dialer := func(ctx context.Context, address string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	}
+
+	conn, err := grpc.Dial("bufconn", grpc.WithContextDialer(dialer), grpc.WithTransportCredentials(creds))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		srv.Stop()
+	})
+
+	return conn
+}
+
+func newTestServer() *Server {
+	return NewServer(mafia_bot.Options{}, mafia_impl.DefaultConfig())
+}
+
+func join(client mafia_grpc.MafiaClient, session string, name string, config *mafia_grpc.SessionConfig) (*mafia_grpc.PlayerInfo, context.Context, error) {
+	player := &mafia_grpc.PlayerInfo{Session: session, Name: name}
+	response, err := client.Join(context.Background(), &mafia_grpc.JoinRequest{Player: player, Config: config})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return player, mafia_grpc.WithToken(context.Background(), response.GetToken()), nil
+}
+
+/*
+	Plays for the player over gRPC until the game ends, see playRandomly in the engine tests
+*/
+func playRandomly(ctx context.Context, client mafia_grpc.MafiaClient, player *mafia_grpc.PlayerInfo, seed int64) error {
+	rng := rand.New(rand.NewSource(seed))
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.GetNotifications(streamCtx, &mafia_grpc.SubscribeRequest{Player: player})
+	if err != nil {
+		return err
+	}
+
+	role := ""
+	for {
+		notification, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if notification.GetType() == mafia_grpc.NotificationType_FINISH {
+			return nil
+		}
+
+		state := notification.GetGameState()
+		alive := state.GetAlivePlayers()
+		if len(alive) == 0 {
+			continue
+		}
+
+		request := &mafia_grpc.SetVictimRequest{Player: player, Victim: alive[rng.Intn(len(alive))]}
+		switch notification.GetType() {
+		case mafia_grpc.NotificationType_START, mafia_grpc.NotificationType_NEW_NIGHT:
+			if notification.GetType() == mafia_grpc.NotificationType_START {
+				role = notification.GetRole()
+			}
+
+			switch mafia_impl.Role(role) {
+			case mafia_impl.Mafia:
+				client.Kill(ctx, request)
+			case mafia_impl.Detective:
+				client.CheckIfMafia(ctx, request)
+			case mafia_impl.Doctor:
+				client.Heal(ctx, request)
+			}
+		case mafia_grpc.NotificationType_NEW_DAY, mafia_grpc.NotificationType_RUNOFF:
+			if rng.Intn(20) == 0 {
+				_, err = client.Quit(ctx, player)
+				return err
+			}
+
+			if state.GetIsRunoff() {
+				candidates := state.GetRunoffCandidates()
+				request.Victim = candidates[rng.Intn(len(candidates))]
+			}
+
+			client.Vote(ctx, request)
+			client.GetVotes(ctx, player)
+			client.ChangeVote(ctx, &mafia_grpc.SetVictimRequest{Player: player, Victim: alive[rng.Intn(len(alive))]})
+		}
+	}
+}
+
+/*
+	Many clients join several sessions at once and play them to the end,
+	while the lobby is listed from other goroutines. Run it with -race
+*/
+func TestConcurrentClients(t *testing.T) {
+	const sessions = 8
+	const players = 8
+
+	server := newTestServer()
+	client := newTestClient(t, server)
+	config := &mafia_grpc.SessionConfig{
+		Players:          players,
+		Roles:            map[string]int32{"Mafia": 2, "Detective": 1, "Doctor": 1},
+		KillRule:         mafia_grpc.KillRule_MAJORITY,
+		DoctorSelfHeal:   true,
+		DoctorRepeatHeal: true,
+		Runoff:           true,
+		OpenBallot:       true,
+		DayDuration:      1,
+		NightDuration:    1,
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < sessions; i++ {
+		for j := 0; j < players; j++ {
+			wg.Add(1)
+			go func(session string, name string, seed int64) {
+				defer wg.Done()
+
+				player, ctx, err := join(client, session, name, config)
+				if err != nil {
+					t.Errorf("Join of %s to %s: %v", name, session, err)
+					return
+				}
+
+				err = playRandomly(ctx, client, player, seed)
+				if err != nil {
+					t.Errorf("Player %s of %s: %v", name, session, err)
+				}
+			}(fmt.Sprintf("session%d", i), fmt.Sprintf("p%d", j), int64(i*players+j))
+		}
+	}
+
+	stop := make(chan struct{})
+	var readers sync.WaitGroup
+	readers.Add(1)
+	go func() {
+		defer readers.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+
+			_, err := client.ListSessions(context.Background(), &mafia_grpc.ListSessionsRequest{})
+			if err != nil {
+				t.Errorf("ListSessions: %v", err)
+				return
+			}
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(60 * time.Second):
+		t.Fatal("Games didn't finish in time")
+	}
+
+	close(stop)
+	readers.Wait()
+
+	for _, game := range server.Games() {
+		if !game.IsFinished() {
+			t.Errorf("Game of %s didn't finish: %s", game.Session(), game.GetGameState())
+		}
+	}
+
+	if len(server.Games()) != sessions {
+		t.Errorf("Server must have %d sessions, it has %d", sessions, len(server.Games()))
+	}
+}
+
+/*
+	More players than seats join the same new session at once:
+	the session is created once and only the seats are taken
+*/
+func TestConcurrentJoinFillsTheSession(t *testing.T) {
+	const players = 6
+	const candidates = 20
+
+	server := newTestServer()
+	client := newTestClient(t, server)
+	config := &mafia_grpc.SessionConfig{Players: players}
+
+	var mu sync.Mutex
+	joined := 0
+	gameIDs := make(map[string]bool)
+
+	var wg sync.WaitGroup
+	for i := 0; i < candidates; i++ {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+
+			player := &mafia_grpc.PlayerInfo{Session: "crowded", Name: name}
+			response, err := client.Join(context.Background(), &mafia_grpc.JoinRequest{Player: player, Config: config})
+			if err != nil {
+				if status.Code(err) != codes.ResourceExhausted {
+					t.Errorf("Join of %s: %v", name, err)
+				}
+				return
+			}
+
+			mu.Lock()
+			joined++
+			gameIDs[response.GetGameId()] = true
+			mu.Unlock()
+		}(fmt.Sprintf("p%d", i))
+	}
+	wg.Wait()
+
+	if joined != players || len(gameIDs) != 1 {
+		t.Fatalf("%d players must join one game, %d joined %d games", players, joined, len(gameIDs))
+	}
+}
+
+/*
+	Sessions leave the server when they expire or are deleted,
+	their logs are saved and replay the same games
+*/
+func TestLogsOfRemovedGamesAreKept(t *testing.T) {
+	const players = 4
+
+	storage, err := mafia_storage.NewStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := newTestServer()
+	err = server.Restore(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := newTestClient(t, server)
+	config := &mafia_grpc.SessionConfig{Players: players, DayDuration: 1, NightDuration: 1}
+
+	var wg sync.WaitGroup
+	for i := 0; i < players; i++ {
+		player, ctx, err := join(client, "finished", fmt.Sprintf("p%d", i), config)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			playRandomly(ctx, client, player, seed)
+		}(int64(i))
+	}
+
+	_, _, err = join(client, "deleted", "p0", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	finished, err := server.getGame("finished")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deleted, err := server.getGame("deleted")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wg.Wait()
+	if !finished.IsFinished() {
+		t.Fatalf("Game must finish: %s", finished.GetGameState())
+	}
+
+	_, err = server.removeGame("deleted")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.cleanup(time.Nanosecond, 0)
+	if len(server.Games()) != 0 {
+		t.Fatalf("All sessions must be removed, %d are left", len(server.Games()))
+	}
+
+	err = server.Save(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshots, err := storage.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 0 {
+		t.Errorf("Snapshots of removed games must be deleted, %d are left", len(snapshots))
+	}
+
+	for _, original := range []*mafia_impl.Game{finished, deleted} {
+		events, err := storage.LoadLog(original.ID())
+		if err != nil {
+			t.Fatalf("Log of %s must be saved: %v", original.Session(), err)
+		}
+
+		replayed, err := mafia_impl.Verify(events)
+		if err != nil {
+			t.Fatalf("Log of %s: %v", original.Session(), err)
+		}
+
+		// Alive players come from a map, their order differs between the games
+		replayedState, originalState := replayed.GetGameState(), original.GetGameState()
+		sort.Strings(replayedState.AlivePlayers)
+		sort.Strings(originalState.AlivePlayers)
+		if !proto.Equal(replayedState, originalState) {
+			t.Errorf("Replay of %s differs:\n%s\n%s", original.Session(), replayedState, originalState)
+		}
+
+		result, isFinished := original.Result()
+		replayedResult, replayedIsFinished := replayed.Result()
+		if isFinished != replayedIsFinished || replayedResult.MafiaWon != result.MafiaWon || !reflect.DeepEqual(replayedResult.Players, result.Players) {
+			t.Errorf("Replay of %s must have the same result: %+v, %+v", original.Session(), replayedResult, result)
+		}
+	}
+}
+
+/*
+	Session waiting for players is saved, the restarted server restores it,
+	the player keeps the token and the rest of the players can join
+*/
+func TestSessionSurvivesRestart(t *testing.T) {
+	const players = 4
+
+	dir := t.TempDir()
+	storage, err := mafia_storage.NewStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := newTestServer()
+	err = server.Restore(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := newTestClient(t, server)
+	config := &mafia_grpc.SessionConfig{Players: players}
+	first, firstCtx, err := join(client, "restart", "p0", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = server.Save(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+
+	storage, err = mafia_storage.NewStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restarted := newTestServer()
+	err = restarted.Restore(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restarted.Close()
+
+	client = newTestClient(t, restarted)
+	_, err = client.GetState(firstCtx, first)
+	if err != nil {
+		t.Fatalf("Player must keep the token after the restart: %v", err)
+	}
+
+	for i := 1; i < players; i++ {
+		_, _, err = join(client, "restart", fmt.Sprintf("p%d", i), config)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	state, err := client.GetState(firstCtx, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.GetIsStarted() || len(state.GetAlivePlayers()) != players {
+		t.Fatalf("Restored session must start when the table is full, state: %s", state)
+	}
+}
+
+/*
+	Finishes the game of the session and brings all its players to the rematch,
+	their contexts get the new tokens. Returns the new game
+*/
+func rematch(t *testing.T, server *Server, client mafia_grpc.MafiaClient, players map[*mafia_grpc.PlayerInfo]context.Context) *mafia_impl.Game {
+	t.Helper()
+
+	var session string
+	for player := range players {
+		session = player.GetSession()
+	}
+
+	game, err := server.getGame(session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = game.ForceFinish("rematch")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for player, ctx := range players {
+		response, err := client.Rematch(ctx, player)
+		if err != nil {
+			t.Fatalf("Rematch of %s: %v", player.GetName(), err)
+		}
+		players[player] = mafia_grpc.WithToken(context.Background(), response.GetToken())
+	}
+
+	next, err := server.getGame(session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next == game || !next.GetGameState().GetIsStarted() {
+		t.Fatalf("Rematch must start a new game, state: %s", next.GetGameState())
+	}
+
+	return next
+}
+
+func deal(game *mafia_impl.Game) map[string]mafia_impl.Role {
+	roles := make(map[string]mafia_impl.Role)
+	for _, player := range game.Snapshot().Players {
+		roles[player.Name] = player.Role
+	}
+
+	return roles
+}
+
+func TestRematchDealsNewRoles(t *testing.T) {
+	for _, seed := range []int64{0, 42} {
+		server := newTestServer()
+		client := newTestClient(t, server)
+		config := &mafia_grpc.SessionConfig{Players: 8, Roles: map[string]int32{"Mafia": 2, "Detective": 1, "Doctor": 1}, Seed: seed}
+
+		players := make(map[*mafia_grpc.PlayerInfo]context.Context)
+		for i := 0; i < 8; i++ {
+			player, ctx, err := join(client, "rematch", fmt.Sprintf("p%d", i), config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			players[player] = ctx
+		}
+
+		game, err := server.getGame("rematch")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		seeds := map[int64]bool{game.Snapshot().Seed: true}
+		dealChanged := false
+		for i := 0; i < 5; i++ {
+			next := rematch(t, server, client, players)
+			if next.Config().Seed != seed {
+				t.Fatalf("Rematch must keep the seed of the organizer %d, got %d", seed, next.Config().Seed)
+			}
+
+			seeds[next.Snapshot().Seed] = true
+			if !reflect.DeepEqual(deal(next), deal(game)) {
+				dealChanged = true
+			}
+			game = next
+		}
+
+		if seed == 0 && (len(seeds) != 6 || !dealChanged) {
+			t.Errorf("Every rematch must get a new seed and deal new roles, seeds: %v", seeds)
+		}
+		if seed != 0 && (len(seeds) != 1 || dealChanged) {
+			t.Errorf("Rematch of the seeded session must deal the same roles, seeds: %v", seeds)
+		}
+
+		server.Close()
+	}
+}
+
+func TestSpectatorLeavesWhenStreamEnds(t *testing.T) {
+	defaults := mafia_impl.DefaultConfig()
+	defaults.ReconnectGrace = 0
+	server := NewServer(mafia_bot.Options{}, defaults)
+	client := newTestClient(t, server)
+
+	_, _, err := join(client, "watched", "p0", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spectator := &mafia_grpc.PlayerInfo{Session: "watched", Name: "watcher"}
+	response, err := client.Spectate(context.Background(), &mafia_grpc.SpectateRequest{Player: spectator})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(mafia_grpc.WithToken(context.Background(), response.GetToken()))
+	stream, err := client.GetNotifications(ctx, &mafia_grpc.SubscribeRequest{Player: spectator})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spectators := func() int32 {
+		info, err := client.GetSessionInfo(context.Background(), &mafia_grpc.SessionRequest{Session: "watched"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return info.GetSpectators()
+	}
+
+	if spectators() != 1 {
+		t.Fatal("Connected spectator must be counted")
+	}
+
+	cancel()
+	deadline := time.Now().Add(5 * time.Second)
+	for spectators() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Spectator must be removed when the stream ends")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestOnlyHostAddsBotsOverGRPC(t *testing.T) {
+	server := NewServer(mafia_bot.Options{Delay: time.Millisecond}, mafia_impl.DefaultConfig())
+	defer server.Close()
+	client := newTestClient(t, server)
+	config := &mafia_grpc.SessionConfig{Players: 6}
+
+	host, hostCtx, err := join(client, "bots", "host", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	guest, guestCtx, err := join(client, "bots", "guest", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.AddBots(guestCtx, &mafia_grpc.AddBotsRequest{Player: guest})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("Guest must not add bots, got %v", err)
+	}
+
+	_, err = client.AddBots(hostCtx, &mafia_grpc.AddBotsRequest{Player: host, Count: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := client.GetSessionInfo(context.Background(), &mafia_grpc.SessionRequest{Session: "bots"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.GetBots() != 2 || info.GetPlayers() != 4 {
+		t.Fatalf("Host must add 2 bots, session: %s", info)
+	}
+}
+
+/*
+	Nobody can kill, check or quit on behalf of another player:
+	the call must carry the token of the player in the metadata
+*/
+func TestCallsRequireTheTokenOfThePlayer(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	players := make(map[string]*mafia_grpc.PlayerInfo)
+	contexts := make(map[string]context.Context)
+	for _, name := range []string{"p1", "p2", "p3", "p4"} {
+		player, ctx, err := join(client, "tokens", name, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		players[name], contexts[name] = player, ctx
+	}
+
+	game, err := server.getGame("tokens")
+	if err != nil {
+		t.Fatal(err)
+	}
+	role2name := make(map[mafia_impl.Role]string)
+	for _, player := range game.Snapshot().Players {
+		role2name[player.Role] = player.Name
+	}
+	mafia := players[role2name[mafia_impl.Mafia]]
+	detective := players[role2name[mafia_impl.Detective]]
+	civilian := role2name[mafia_impl.Civilian]
+	events := len(game.Events())
+
+	calls := map[string]func(ctx context.Context) error{
+		"Kill": func(ctx context.Context) error {
+			_, err := client.Kill(ctx, &mafia_grpc.SetVictimRequest{Player: mafia, Victim: civilian})
+			return err
+		},
+		"CheckIfMafia": func(ctx context.Context) error {
+			_, err := client.CheckIfMafia(ctx, &mafia_grpc.SetVictimRequest{Player: detective, Victim: mafia.GetName()})
+			return err
+		},
+		"Quit": func(ctx context.Context) error {
+			_, err := client.Quit(ctx, players[civilian])
+			return err
+		},
+		"GetNotifications": func(ctx context.Context) error {
+			stream, err := client.GetNotifications(ctx, &mafia_grpc.SubscribeRequest{Player: players[civilian]})
+			if err != nil {
+				return err
+			}
+			_, err = stream.Recv()
+			return err
+		},
+	}
+
+	// The civilian's token is used only for the calls of other players
+	stolen := contexts[civilian]
+	tokens := map[string]func(call string) context.Context{
+		"no token":    func(string) context.Context { return context.Background() },
+		"wrong token": func(string) context.Context { return mafia_grpc.WithToken(context.Background(), "wrong") },
+		"token of another player": func(call string) context.Context {
+			if call == "Quit" || call == "GetNotifications" {
+				return contexts[mafia.GetName()]
+			}
+			return stolen
+		},
+	}
+
+	for call, do := range calls {
+		for description, token := range tokens {
+			err := do(token(call))
+			if status.Code(err) != codes.Unauthenticated {
+				t.Errorf("%s with %s must be rejected as unauthenticated, got %v", call, description, err)
+			}
+		}
+	}
+
+	state := game.GetGameState()
+	if len(state.GetAlivePlayers()) != 4 || len(game.Events()) != events {
+		t.Fatalf("Rejected calls must not change the game, state: %s", state)
+	}
+
+	// With their own tokens the players act as usual
+	_, err = client.Kill(contexts[mafia.GetName()], &mafia_grpc.SetVictimRequest{Player: mafia, Victim: civilian})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Quit(contexts[civilian], players[civilian])
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,111 @@
+package mafia_server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"soa_mafia/pkg/mafia_grpc"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+/*
+	Issues the certificate for the name signed by the parent, or a self-signed CA without the parent
+*/
+func issue(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, tls.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert, key, tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}
+}
+
+func TestPlayerMustMatchClientCertificate(t *testing.T) {
+	ca, caKey, _ := issue(t, "ca", nil, nil)
+	_, _, serverCert := issue(t, "mafia", ca, caKey)
+	_, _, aliceCert := issue(t, "alice", ca, caKey)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+
+	serverCreds := credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})
+	clientCreds := credentials.NewTLS(&tls.Config{
+		ServerName:   "mafia",
+		RootCAs:      pool,
+		Certificates: []tls.Certificate{aliceCert},
+	})
+
+	server := newTestServer()
+	defer server.Close()
+	client := dialTestServer(t, server, clientCreds, grpc.Creds(serverCreds))
+
+	_, _, err := join(client, "s", "bob", nil)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("Join under another name than in the certificate must be denied, got %v", err)
+	}
+
+	player, ctx, err := join(client, "s", "alice", nil)
+	if err != nil {
+		t.Fatalf("Join under the name from the certificate must succeed: %v", err)
+	}
+
+	_, err = client.GetState(ctx, player)
+	if err != nil {
+		t.Fatalf("Player must act under the name from the certificate: %v", err)
+	}
+
+	_, err = client.GetState(ctx, &mafia_grpc.PlayerInfo{Session: "s", Name: "bob"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("Player must not act under another name, got %v", err)
+	}
+
+	_, err = client.Spectate(context.Background(), &mafia_grpc.SpectateRequest{Player: &mafia_grpc.PlayerInfo{Session: "s", Name: "carol"}})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("Spectator must watch under the name from the certificate, got %v", err)
+	}
+}
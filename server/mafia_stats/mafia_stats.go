@@ -0,0 +1,285 @@
+package mafia_stats
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"soa_mafia/pkg/mafia_grpc"
+	mafia_impl "soa_mafia/server/mafia_impl"
+)
+
+const (
+	InitialRating = 1500.0
+	// Largest change of the rating of the team in one game
+	ratingK = 32.0
+
+	DefaultLeaderboardLimit = 10
+)
+
+/*
+	History of a player across games. Players are known by name,
+	so a rematch or a new session under the same name adds to the same history.
+	The name is an identity only with mutual TLS, where it must match the client
+	certificate. Otherwise anyone can play under any free name and change its stats
+*/
+type PlayerStats struct {
+	Name        string
+	Rating      float64
+	Games       int32
+	Wins        int32
+	GamesByRole map[mafia_impl.Role]int32
+	WinsByRole  map[mafia_impl.Role]int32
+	Survived    int32
+	Checks      int32
+	MafiaFound  int32
+	LastGame    time.Time
+}
+
+/*
+	Stats keeps the statistics and Elo ratings of the players.
+	Bots aren't rated, in the team rating they count with the initial rating.
+	Stats is safe for concurrent use
+*/
+type Stats struct {
+	mu      sync.Mutex
+	players map[string]*PlayerStats
+}
+
+func NewStats() *Stats {
+	return &Stats{players: make(map[string]*PlayerStats)}
+}
+
+/*
+	Replaces all statistics with the saved ones
+*/
+func (s *Stats) Restore(players []PlayerStats) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.players = make(map[string]*PlayerStats)
+	for i := range players {
+		player := copyStats(&players[i])
+		s.players[player.Name] = &player
+	}
+}
+
+/*
+	Adds the finished game to the history of its players and updates their ratings.
+	Elo is counted for the teams by their average ratings: the mafia and the rest.
+	Teams gain and lose the same number of points, split among their players,
+	so the ratings don't grow just because the bigger team wins more often
+*/
+func (s *Stats) Record(result mafia_impl.GameResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	mafiaRating, civilianRating, mafiaCount, civilianCount := s.teams(result)
+	expectedMafiaScore := 1 / (1 + math.Pow(10, (civilianRating-mafiaRating)/400))
+	mafiaScore := 0.0
+	if result.MafiaWon {
+		mafiaScore = 1
+	}
+
+	mafiaChange := ratingK * (mafiaScore - expectedMafiaScore)
+	civilianChange := 0.0
+	if civilianCount > 0 {
+		civilianChange = -mafiaChange * float64(mafiaCount) / float64(civilianCount)
+	}
+
+	for _, player := range result.Players {
+		if player.IsBot {
+			continue
+		}
+
+		stats := s.get(player.Name)
+		stats.Games++
+		stats.GamesByRole[player.Role]++
+		stats.Checks += player.Checks
+		stats.MafiaFound += player.MafiaFound
+		stats.LastGame = result.Finished
+
+		if player.Survived {
+			stats.Survived++
+		}
+
+		if player.Won {
+			stats.Wins++
+			stats.WinsByRole[player.Role]++
+		}
+
+		if player.Role == mafia_impl.Mafia {
+			stats.Rating += mafiaChange
+		} else {
+			stats.Rating += civilianChange
+		}
+	}
+}
+
+/*
+	Returns statistics of the player and their place in the leaderboard
+*/
+func (s *Stats) Player(name string) (PlayerStats, int32, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats, exists := s.players[name]
+	if !exists {
+		return PlayerStats{}, 0, mafia_impl.NewError(mafia_impl.ErrNoPlayer, "No finished games of player "+name)
+	}
+
+	rank := int32(1)
+	for _, player := range s.sorted(0) {
+		if player.Name == name {
+			break
+		}
+		rank++
+	}
+
+	return copyStats(stats), rank, nil
+}
+
+/*
+	Returns at most limit best players with at least minGames games
+	and the number of such players
+*/
+func (s *Stats) Leaderboard(limit int32, minGames int32) ([]PlayerStats, int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	players := s.sorted(minGames)
+	total := int32(len(players))
+	if limit > 0 && int32(len(players)) > limit {
+		players = players[:limit]
+	}
+
+	return players, total
+}
+
+/*
+	Returns statistics of all players to save them
+*/
+func (s *Stats) Players() []PlayerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sorted(0)
+}
+
+func (p PlayerStats) ToProto(rank int32) *mafia_grpc.PlayerStats {
+	return &mafia_grpc.PlayerStats{
+		Name:        p.Name,
+		Rank:        rank,
+		Rating:      math.Round(p.Rating*10) / 10,
+		Games:       p.Games,
+		Wins:        p.Wins,
+		GamesByRole: rolesToProto(p.GamesByRole),
+		WinsByRole:  rolesToProto(p.WinsByRole),
+		Survived:    p.Survived,
+		Checks:      p.Checks,
+		MafiaFound:  p.MafiaFound,
+	}
+}
+
+func (s *Stats) get(name string) *PlayerStats {
+	stats, exists := s.players[name]
+	if !exists {
+		stats = &PlayerStats{
+			Name:        name,
+			Rating:      InitialRating,
+			GamesByRole: make(map[mafia_impl.Role]int32),
+			WinsByRole:  make(map[mafia_impl.Role]int32),
+		}
+		s.players[name] = stats
+	}
+
+	return stats
+}
+
+func (s *Stats) rating(player mafia_impl.PlayerResult) float64 {
+	stats, exists := s.players[player.Name]
+	if player.IsBot || !exists {
+		return InitialRating
+	}
+
+	return stats.Rating
+}
+
+/*
+	Returns average ratings and sizes of the mafia and of the rest
+*/
+func (s *Stats) teams(result mafia_impl.GameResult) (float64, float64, int, int) {
+	var mafiaSum, civilianSum float64
+	var mafiaCount, civilianCount int
+	for _, player := range result.Players {
+		if player.Role == mafia_impl.Mafia {
+			mafiaSum += s.rating(player)
+			mafiaCount++
+		} else {
+			civilianSum += s.rating(player)
+			civilianCount++
+		}
+	}
+
+	mafiaRating, civilianRating := InitialRating, InitialRating
+	if mafiaCount > 0 {
+		mafiaRating = mafiaSum / float64(mafiaCount)
+	}
+
+	if civilianCount > 0 {
+		civilianRating = civilianSum / float64(civilianCount)
+	}
+
+	return mafiaRating, civilianRating, mafiaCount, civilianCount
+}
+
+/*
+	Players with at least minGames games by rating, then by wins and name
+*/
+func (s *Stats) sorted(minGames int32) []PlayerStats {
+	players := []PlayerStats{}
+	for _, stats := range s.players {
+		if stats.Games >= minGames {
+			players = append(players, copyStats(stats))
+		}
+	}
+
+	sort.Slice(players, func(i, j int) bool {
+		if players[i].Rating != players[j].Rating {
+			return players[i].Rating > players[j].Rating
+		}
+
+		if players[i].Wins != players[j].Wins {
+			return players[i].Wins > players[j].Wins
+		}
+
+		return players[i].Name < players[j].Name
+	})
+
+	return players
+}
+
+func copyStats(stats *PlayerStats) PlayerStats {
+	result := *stats
+	result.GamesByRole = make(map[mafia_impl.Role]int32)
+	result.WinsByRole = make(map[mafia_impl.Role]int32)
+	for role, count := range stats.GamesByRole {
+		result.GamesByRole[role] = count
+	}
+
+	for role, count := range stats.WinsByRole {
+		result.WinsByRole[role] = count
+	}
+
+	return result
+}
+
+func rolesToProto(counts map[mafia_impl.Role]int32) map[string]int32 {
+	result := make(map[string]int32)
+	for role, count := range counts {
+		result[string(role)] = count
+	}
+
+	return result
+}
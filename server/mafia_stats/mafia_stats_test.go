@@ -0,0 +1,101 @@
+package mafia_stats
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	mafia_impl "soa_mafia/server/mafia_impl"
+)
+
+/*
+	Game of four: alice is the mafia, bob is the detective who found her,
+	carol is a civilian and the bot is a civilian too
+*/
+func gameResult(mafiaWon bool) mafia_impl.GameResult {
+	return mafia_impl.GameResult{
+		GameID:   "game",
+		MafiaWon: mafiaWon,
+		Finished: time.Now(),
+		Players: []mafia_impl.PlayerResult{
+			{Name: "alice", Role: mafia_impl.Mafia, Won: mafiaWon, Survived: mafiaWon},
+			{Name: "bob", Role: mafia_impl.Detective, Won: !mafiaWon, Survived: true, Checks: 2, MafiaFound: 1},
+			{Name: "carol", Role: mafia_impl.Civilian, Won: !mafiaWon},
+			{Name: "Bot-1", Role: mafia_impl.Civilian, IsBot: true, Won: !mafiaWon},
+		},
+	}
+}
+
+func TestRecordCountsGamesOfPlayers(t *testing.T) {
+	stats := NewStats()
+	stats.Record(gameResult(true))
+	stats.Record(gameResult(false))
+
+	alice, _, err := stats.Player("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alice.Games != 2 || alice.Wins != 1 || alice.Survived != 1 || alice.GamesByRole[mafia_impl.Mafia] != 2 || alice.WinsByRole[mafia_impl.Mafia] != 1 {
+		t.Errorf("Wrong statistics of alice: %+v", alice)
+	}
+
+	bob, _, err := stats.Player("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bob.Checks != 4 || bob.MafiaFound != 2 || bob.Survived != 2 {
+		t.Errorf("Wrong statistics of bob: %+v", bob)
+	}
+
+	_, _, err = stats.Player("Bot-1")
+	if !errors.Is(err, mafia_impl.ErrNoPlayer) {
+		t.Errorf("Bots must have no statistics, got %v", err)
+	}
+}
+
+func TestRatingChangesAreBalanced(t *testing.T) {
+	stats := NewStats()
+	stats.Record(gameResult(true))
+
+	total := 0.0
+	for _, player := range stats.Players() {
+		total += player.Rating - InitialRating
+	}
+
+	// The bot isn't rated, its share of the civilian loss is the same as the share of carol
+	carol, _, _ := stats.Player("carol")
+	total += carol.Rating - InitialRating
+	if math.Abs(total) > 1e-9 {
+		t.Fatalf("Teams must gain and lose the same number of points, the sum is %f", total)
+	}
+
+	alice, rank, _ := stats.Player("alice")
+	if alice.Rating != InitialRating+ratingK/2 || rank != 1 {
+		t.Fatalf("Mafia of equal teams must gain half of K and lead, got %f, rank %d", alice.Rating, rank)
+	}
+}
+
+func TestLeaderboard(t *testing.T) {
+	stats := NewStats()
+	stats.Record(gameResult(true))
+	stats.Record(gameResult(true))
+
+	players, total := stats.Leaderboard(2, 0)
+	if total != 3 || len(players) != 2 || players[0].Name != "alice" {
+		t.Fatalf("Leaderboard must show 2 of 3 players starting with alice, got %d: %+v", total, players)
+	}
+
+	players, total = stats.Leaderboard(0, 3)
+	if total != 0 || len(players) != 0 {
+		t.Fatalf("Nobody has 3 games, got %+v", players)
+	}
+
+	restored := NewStats()
+	restored.Restore(stats.Players())
+	alice, _, _ := stats.Player("alice")
+	restoredAlice, _, err := restored.Player("alice")
+	if err != nil || restoredAlice.Rating != alice.Rating || restoredAlice.Games != alice.Games {
+		t.Fatalf("Restored statistics differ: %+v, %+v", restoredAlice, alice)
+	}
+}
@@ -0,0 +1,251 @@
+package mafia_storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	mafia_impl "soa_mafia/server/mafia_impl"
+	mafia_stats "soa_mafia/server/mafia_stats"
+	"strings"
+)
+
+const snapshotExt = ".json"
+
+/*
+	Logs of the finished and removed games are kept in this subdirectory after their snapshots are deleted
+*/
+const logsDir = "logs"
+
+/*
+	Statistics of the players are kept next to the snapshots, under a name no game id can have
+*/
+const statsID = "stats"
+
+/*
+	Storage keeps JSON snapshots of the games in a directory, one file per game,
+	and event logs of the finished games in its logs subdirectory.
+	Storage isn't safe for concurrent use
+*/
+type Storage struct {
+	dir        string
+	saved      map[string][]byte
+	savedStats []byte
+	savedLogs  map[string]bool
+}
+
+func NewStorage(dir string) (*Storage, error) {
+	err := os.MkdirAll(filepath.Join(dir, logsDir), 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Storage{dir: dir, saved: make(map[string][]byte), savedLogs: make(map[string]bool)}, nil
+}
+
+/*
+	Returns all snapshots from the directory
+*/
+func (s *Storage) Load() ([]mafia_impl.Snapshot, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := []mafia_impl.Snapshot{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), snapshotExt) || entry.Name() == statsID+snapshotExt {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var snapshot mafia_impl.Snapshot
+		err = json.Unmarshal(data, &snapshot)
+		if err != nil {
+			return nil, err
+		}
+
+		s.saved[snapshot.ID] = data
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, nil
+}
+
+/*
+	Writes snapshots that have changed since the last call
+	and deletes snapshots of the games that aren't in the list
+*/
+func (s *Storage) Sync(snapshots []mafia_impl.Snapshot) error {
+	current := make(map[string]bool)
+	for _, snapshot := range snapshots {
+		current[snapshot.ID] = true
+
+		data, err := json.Marshal(snapshot)
+		if err != nil {
+			return err
+		}
+
+		if bytes.Equal(s.saved[snapshot.ID], data) {
+			continue
+		}
+
+		err = s.write(s.path(snapshot.ID), data)
+		if err != nil {
+			return err
+		}
+		s.saved[snapshot.ID] = data
+	}
+
+	for id := range s.saved {
+		if current[id] {
+			continue
+		}
+
+		err := os.Remove(s.path(id))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		delete(s.saved, id)
+	}
+
+	return nil
+}
+
+/*
+	Returns saved statistics of the players, none if they haven't been saved yet
+*/
+func (s *Storage) LoadStats() ([]mafia_stats.PlayerStats, error) {
+	data, err := os.ReadFile(s.path(statsID))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var players []mafia_stats.PlayerStats
+	err = json.Unmarshal(data, &players)
+	if err != nil {
+		return nil, err
+	}
+
+	s.savedStats = data
+	return players, nil
+}
+
+/*
+	Writes statistics of the players if they have changed since the last call
+*/
+func (s *Storage) SaveStats(players []mafia_stats.PlayerStats) error {
+	if len(players) == 0 && s.savedStats == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(players)
+	if err != nil {
+		return err
+	}
+
+	if bytes.Equal(s.savedStats, data) {
+		return nil
+	}
+
+	err = s.write(s.path(statsID), data)
+	if err != nil {
+		return err
+	}
+
+	s.savedStats = data
+	return nil
+}
+
+/*
+	Log of the game is written once, when the game has finished or left the server
+*/
+func (s *Storage) SaveLog(id string, events []mafia_impl.Event) error {
+	data, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+
+	err = s.write(s.logPath(id), data)
+	if err != nil {
+		return err
+	}
+
+	s.savedLogs[id] = true
+	return nil
+}
+
+func (s *Storage) HasLog(id string) bool {
+	if s.savedLogs[id] {
+		return true
+	}
+
+	_, err := os.Stat(s.logPath(id))
+	if err != nil {
+		return false
+	}
+
+	s.savedLogs[id] = true
+	return true
+}
+
+/*
+	Returns the saved log of the game, see mafia_impl.Verify
+*/
+func (s *Storage) LoadLog(id string) ([]mafia_impl.Event, error) {
+	data, err := os.ReadFile(s.logPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	var events []mafia_impl.Event
+	err = json.Unmarshal(data, &events)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+/*
+	File is replaced with rename, so a crash during the write doesn't corrupt the old snapshot
+*/
+func (s *Storage) write(path string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+func (s *Storage) path(id string) string {
+	return filepath.Join(s.dir, id+snapshotExt)
+}
+
+func (s *Storage) logPath(id string) string {
+	return filepath.Join(s.dir, logsDir, id+snapshotExt)
+}
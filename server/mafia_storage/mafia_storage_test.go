@@ -0,0 +1,160 @@
+package mafia_storage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	mafia_impl "soa_mafia/server/mafia_impl"
+	mafia_stats "soa_mafia/server/mafia_stats"
+)
+
+func newTestStorage(t *testing.T) (*Storage, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	storage, err := NewStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return storage, dir
+}
+
+func snapshot(id string) mafia_impl.Snapshot {
+	return mafia_impl.Snapshot{
+		ID:      id,
+		Session: "session " + id,
+		Config:  mafia_impl.DefaultConfig(),
+		Players: []mafia_impl.PlayerSnapshot{{Name: "alice", Role: mafia_impl.Mafia, IsAlive: true, Token: "token"}},
+		Events:  []mafia_impl.Event{{Type: mafia_impl.EventAddPlayer, Player: "alice"}},
+	}
+}
+
+func TestSnapshotsAreSyncedWithTheGames(t *testing.T) {
+	storage, dir := newTestStorage(t)
+
+	err := storage.Sync([]mafia_impl.Snapshot{snapshot("a"), snapshot("b")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = storage.SaveStats([]mafia_stats.PlayerStats{{Name: "alice", Games: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = storage.SaveLog("c", snapshot("c").Events)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Other storage reads the directory as the server does after a restart
+	restarted, err := NewStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := restarted.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("Only the snapshots of the games must be loaded, got %d", len(loaded))
+	}
+	for _, got := range loaded {
+		if !reflect.DeepEqual(got.Players, snapshot(got.ID).Players) || got.Session != snapshot(got.ID).Session {
+			t.Errorf("Snapshot %s differs after the load: %+v", got.ID, got)
+		}
+	}
+
+	err = restarted.Sync([]mafia_impl.Snapshot{snapshot("b")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = os.Stat(filepath.Join(dir, "a"+snapshotExt))
+	if !os.IsNotExist(err) {
+		t.Errorf("Snapshot of the removed game must be deleted: %v", err)
+	}
+
+	for _, path := range []string{"b" + snapshotExt, statsID + snapshotExt, filepath.Join(logsDir, "c"+snapshotExt)} {
+		_, err = os.Stat(filepath.Join(dir, path))
+		if err != nil {
+			t.Errorf("%s must be kept: %v", path, err)
+		}
+	}
+
+	stats, err := restarted.LoadStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 1 || stats[0].Name != "alice" || stats[0].Games != 1 {
+		t.Errorf("Statistics differ after the load: %+v", stats)
+	}
+}
+
+func TestUnchangedSnapshotIsNotWritten(t *testing.T) {
+	storage, dir := newTestStorage(t)
+	path := filepath.Join(dir, "a"+snapshotExt)
+
+	err := storage.Sync([]mafia_impl.Snapshot{snapshot("a")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The file is removed behind the storage's back, so a write would bring it back
+	err = os.Remove(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = storage.Sync([]mafia_impl.Snapshot{snapshot("a")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("Unchanged snapshot must not be written again: %v", err)
+	}
+
+	changed := snapshot("a")
+	changed.Date = 2
+	err = storage.Sync([]mafia_impl.Snapshot{changed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(path); err != nil {
+		t.Fatalf("Changed snapshot must be written: %v", err)
+	}
+}
+
+func TestLogIsKeptAfterTheSnapshot(t *testing.T) {
+	storage, dir := newTestStorage(t)
+	events := snapshot("a").Events
+
+	if storage.HasLog("a") {
+		t.Fatal("Log must not exist before it's saved")
+	}
+
+	err := storage.SaveLog("a", events)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restarted, err := NewStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !restarted.HasLog("a") {
+		t.Fatal("Saved log must be found after the restart")
+	}
+
+	loaded, err := restarted.LoadLog("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, events) {
+		t.Fatalf("Log differs after the load: %+v", loaded)
+	}
+}
@@ -2,163 +2,218 @@ package main
 
 import (
 	"context"
-	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net"
-
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	mafia_config "soa_mafia/pkg/mafia_config"
 	"soa_mafia/pkg/mafia_grpc"
+	mafia_bot "soa_mafia/server/mafia_bot"
 	mafia_impl "soa_mafia/server/mafia_impl"
+	mafia_metrics "soa_mafia/server/mafia_metrics"
+	mafia_server "soa_mafia/server/mafia_server"
+	mafia_storage "soa_mafia/server/mafia_storage"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
-type server struct {
-	mafia_grpc.UnimplementedMafiaServer
-
-	session2game map[string]*mafia_impl.Game
-}
+/*
+	Time the open calls have to finish after the games are closed
+*/
+const stopTimeout = 5 * time.Second
 
-func (s *server) Join(ctx context.Context, request *mafia_grpc.JoinRequest) (*mafia_grpc.Response, error) {
-	log.Println(fmt.Sprintf("Join: %s", request))
-	player := request.GetPlayer()
-	session := player.GetSession()
-	name := player.GetName()
+/*
+	Admin service listens on its own address, by default it's reachable only from this host
+*/
+func serveAdmin(mafiaServer *mafia_server.Server, config mafia_config.AdminConfig) {
+	lis, err := net.Listen("tcp", config.Addr)
+	if err != nil {
+		log.Fatalf("failed to listen for admin: %v", err)
+	}
 
-	game, exists := s.session2game[session]
-	if !exists {
-		game = mafia_impl.NewGame(session)
-		s.session2game[session] = game
+	if config.Token == "" {
+		log.Printf("Admin service on %s has no token", config.Addr)
 	}
 
-	err := game.AddPlayer(name)
-	return &mafia_grpc.Response{Ok: err == nil}, err
+	srv := grpc.NewServer()
+	mafia_grpc.RegisterMafiaAdminServer(srv, mafia_server.NewAdminServer(mafiaServer, config.Token))
+	log.Fatalln(srv.Serve(lis))
 }
 
-func (s *server) Vote(ctx context.Context, request *mafia_grpc.SetVictimRequest) (*mafia_grpc.Response, error) {
-	log.Println(fmt.Sprintf("Vote: %s", request))
-	player := request.GetPlayer()
-	victim := request.GetVictim()
-	session := player.GetSession()
-	name := player.GetName()
+/*
+	Metrics are served in the Prometheus text format on /metrics
+*/
+func serveMetrics(metrics *mafia_metrics.Metrics, addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics)
+	log.Fatalln(http.ListenAndServe(addr, mux))
+}
 
-	game, exists := s.session2game[session]
-	if !exists {
-		return nil, errors.New("No game session: " + session)
+/*
+	Converts the game section of the config to the defaults of new sessions and checks them by the rules of the game
+*/
+func gameDefaults(config mafia_config.GameConfig) (mafia_impl.Config, error) {
+	defaults := mafia_impl.NewConfig(config.Players)
+	if len(config.Roles) > 0 {
+		defaults.Roles = make(map[mafia_impl.Role]int32)
+		for role, count := range config.Roles {
+			name := strings.ToUpper(role[:1]) + strings.ToLower(role[1:])
+			defaults.Roles[mafia_impl.Role(name)] = count
+		}
 	}
 
-	err := game.AddVote(name, victim)
-	return &mafia_grpc.Response{Ok: err == nil}, err
-}
-
-func (s *server) Kill(ctx context.Context, request *mafia_grpc.SetVictimRequest) (*mafia_grpc.Response, error) {
-	log.Println(fmt.Sprintf("Kill: %s", request))
-	player := request.GetPlayer()
-	victim := request.GetVictim()
-	session := player.GetSession()
-	name := player.GetName()
+	defaults.KillRule = mafia_grpc.KillRule(mafia_grpc.KillRule_value[strings.ToUpper(config.KillRule)])
+	defaults.DayDuration = config.DayDuration
+	defaults.NightDuration = config.NightDuration
+	defaults.BotFillDelay = config.BotFillDelay
+	defaults.ReconnectGrace = config.ReconnectGrace
+	defaults.NotificationLimit = config.NotificationLimit
 
-	game, exists := s.session2game[session]
-	if !exists {
-		return nil, errors.New("No game session: " + session)
+	policy, err := mafia_impl.ParseOverflowPolicy(config.OverflowPolicy)
+	if err != nil {
+		return defaults, err
 	}
+	defaults.OverflowPolicy = policy
 
-	err := game.KillPlayer(name, victim)
-	return &mafia_grpc.Response{Ok: err == nil}, err
+	return defaults, defaults.Validate()
 }
 
-func (s *server) CheckIfMafia(ctx context.Context, request *mafia_grpc.SetVictimRequest) (*mafia_grpc.CheckMafiaResponse, error) {
-	log.Println(fmt.Sprintf("CheckIfMafia: %s", request))
-	player := request.GetPlayer()
-	victim := request.GetVictim()
-	session := player.GetSession()
-	name := player.GetName()
-
-	game, exists := s.session2game[session]
-	if !exists {
-		return nil, errors.New("No game session: " + session)
+/*
+	Drain mode on SIGTERM or interrupt: new players are refused, players are warned,
+	games in progress get drain_timeout to finish (the second signal cuts it short),
+	unfinished games are saved to be resumed after the restart and the server stops
+*/
+func shutdown(srv *grpc.Server, mafiaServer *mafia_server.Server, storage *mafia_storage.Storage, drainTimeout time.Duration) {
+	text := "Server is shutting down"
+	if drainTimeout > 0 {
+		text += fmt.Sprintf(", games in progress have %s to finish", drainTimeout)
+	}
+	if storage != nil {
+		text += ", unfinished games will continue after the restart"
+	} else {
+		text += ", unfinished games will be lost"
 	}
 
-	isMafia, err := game.CheckIfMafia(name, victim)
-	return &mafia_grpc.CheckMafiaResponse{IsMafia: isMafia}, err
-}
-
-func (s *server) GetState(ctx context.Context, player *mafia_grpc.PlayerInfo) (*mafia_grpc.GameState, error) {
-	log.Println(fmt.Sprintf("GetState: %s", player))
-	session := player.GetSession()
+	log.Println("Draining: " + text)
+	mafiaServer.Drain(text)
 
-	game, exists := s.session2game[session]
-	if !exists {
-		return nil, errors.New("No game session: " + session)
+	if drainTimeout > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+		ctx, stop := signal.NotifyContext(ctx, syscall.SIGTERM, os.Interrupt)
+		if !mafiaServer.WaitGames(ctx) {
+			log.Println("Games in progress didn't finish in time")
+		}
+		stop()
+		cancel()
 	}
 
-	state := game.GetGameState()
-	return state, nil
+	if storage != nil {
+		err := mafiaServer.Save(storage)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+	mafiaServer.Close()
+
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(stopTimeout):
+		log.Println("Calls didn't finish in time, stopping")
+		srv.Stop()
+	}
 }
 
-func (s *server) CanChat(ctx context.Context, player *mafia_grpc.PlayerInfo) (*mafia_grpc.ChatResponse, error) {
-	log.Println(fmt.Sprintf("CanChat: %s", player))
-	session := player.GetSession()
-	name := player.GetName()
+func main() {
+	config, err := mafia_config.Load(flag.CommandLine, os.Args[1:], mafia_config.Server, mafia_config.Game, mafia_config.Admin)
+	if err != nil {
+		log.Fatalf("invalid config: %v", err)
+	}
 
-	game, exists := s.session2game[session]
-	if !exists {
-		return nil, errors.New("No game session: " + session)
+	defaults, err := gameDefaults(config.Game)
+	if err != nil {
+		log.Fatalf("invalid game defaults: %v", err)
 	}
 
-	canChat, err := game.CanChat(name)
-	return &mafia_grpc.ChatResponse{CanChat: canChat}, err
-}
+	log.Println("Server running ...")
+	lis, err := net.Listen("tcp", config.Server.ListenAddr)
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
 
-func (s *server) Quit(ctx context.Context, player *mafia_grpc.PlayerInfo) (*mafia_grpc.Response, error) {
-	log.Println(fmt.Sprintf("Quit: %s", player))
-	session := player.GetSession()
-	name := player.GetName()
+	mafiaServer := mafia_server.NewServer(mafia_bot.Options{Delay: config.Server.BotDelay}, defaults)
+	metrics := mafia_metrics.NewMetrics(mafiaServer.Games)
+	mafiaServer.OnFinish(metrics.RecordGame)
 
-	game, exists := s.session2game[session]
-	if !exists {
-		return nil, errors.New("No game session: " + session)
-	}
+	var storage *mafia_storage.Storage
+	if config.Server.DataDir != "" {
+		storage, err = mafia_storage.NewStorage(config.Server.DataDir)
+		if err != nil {
+			log.Fatalf("failed to open storage: %v", err)
+		}
 
-	err := game.DeletePlayer(name)
-	return &mafia_grpc.Response{Ok: err == nil}, nil
-}
+		err = mafiaServer.Restore(storage)
+		if err != nil {
+			log.Fatalf("failed to restore games: %v", err)
+		}
 
-func (s *server) GetNotifications(player *mafia_grpc.PlayerInfo, stream mafia_grpc.Mafia_GetNotificationsServer) error {
-	log.Println(fmt.Sprintf("GetNotifications: %s", player))
-	session := player.GetSession()
-	name := player.GetName()
+		go mafiaServer.RunSnapshots(storage, config.Server.SnapshotInterval)
+	}
+	go mafiaServer.RunCleanup(config.Server.CleanupInterval, config.Server.FinishedTTL, config.Server.IdleTTL)
+	go mafiaServer.RunBotFill(config.Server.BotFillInterval)
 
-	game, exists := s.session2game[session]
-	if !exists {
-		return errors.New("No game session: " + session)
+	if config.Admin.Addr != "" {
+		go serveAdmin(mafiaServer, config.Admin)
 	}
 
-	notifications, err := game.GetNotifications(name)
-	if err != nil {
-		return err
+	if config.Server.MetricsAddr != "" {
+		go serveMetrics(metrics, config.Server.MetricsAddr)
 	}
 
-	for notification := range *notifications {
-		log.Printf("Sending to %s notification %s", name, notification.Type)
-		err := stream.Send(notification)
+	options := []grpc.ServerOption{grpc.UnaryInterceptor(metrics.UnaryInterceptor), grpc.StreamInterceptor(metrics.StreamInterceptor)}
+	if config.Server.TLSEnabled() {
+		tlsConfig, err := config.Server.TLSConfig()
 		if err != nil {
-			log.Printf("Player %s: Notifications connection lost\n", name)
-			game.DeletePlayer(name)
+			log.Fatalf("invalid TLS config: %v", err)
 		}
+
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+		log.Printf("TLS is enabled, client certificates are required: %t", config.Server.TLSClientCA != "")
+	}
+	if config.Server.TLSClientCA == "" {
+		log.Println("Client certificates aren't required, statistics are kept by player name and anyone can play under any name")
 	}
 
-	return nil
-}
+	srv := grpc.NewServer(options...)
+	mafia_grpc.RegisterMafiaServer(srv, mafiaServer)
 
-func main() {
-	log.Println("Server running ...")
-	lis, err := net.Listen("tcp", ":9000")
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	stopped := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		stop()
+		shutdown(srv, mafiaServer, storage, config.Server.DrainTimeout)
+		close(stopped)
+	}()
+
+	err = srv.Serve(lis)
 	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+		log.Fatalln(err)
 	}
 
-	srv := grpc.NewServer()
-	mafia_grpc.RegisterMafiaServer(srv, &server{session2game: make(map[string]*mafia_impl.Game)})
-	log.Fatalln(srv.Serve(lis))
+	<-stopped
+	log.Println("Server stopped")
 }